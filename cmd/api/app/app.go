@@ -42,7 +42,7 @@ func New() (*App, error) {
 	}
 
 	// Create server instance
-	srv := server.New(cfg, l, container.UserUC, container.RateLimiter, container.GinHandler, container.RedisClient)
+	srv := server.New(cfg, l, container.UserUC, container.RateLimiter, container.GinHandler, container.AdminHandler, container.RedisClient)
 
 	return &App{
 		Config:    cfg,
@@ -87,6 +87,16 @@ func (a *App) Run(ctx context.Context) error {
 		}
 	}()
 
+	// Start background jobs; they stop when ctx is cancelled
+	if a.Config.Scheduler.Enabled {
+		a.Container.Scheduler.Start(ctx)
+	}
+
+	// Warm the user cache while the servers already accept traffic
+	if a.Container.CacheWarmer != nil {
+		go a.warmCache(ctx)
+	}
+
 	// Wait for context cancellation or server error
 	select {
 	case <-ctx.Done():
@@ -97,6 +107,35 @@ func (a *App) Run(ctx context.Context) error {
 	}
 }
 
+// warmCache preloads recently active users into the user cache. A failed
+// warmup only costs cache misses, so it is logged and not retried.
+func (a *App) warmCache(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.Logger.Error("panic recovered in cache warmup", zap.Any("panic", r), zap.Stack("stack"))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(a.Config.Cache.WarmupTimeoutSeconds)*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	n, err := a.Container.CacheWarmer.Run(ctx)
+	if err != nil {
+		a.Logger.Warn("cache warmup stopped early",
+			zap.Int64("users", n),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err),
+		)
+		return
+	}
+
+	a.Logger.Info("cache warmup complete",
+		zap.Int64("users", n),
+		zap.Duration("duration", time.Since(start)),
+	)
+}
+
 // shutdown gracefully shuts down the application
 func (a *App) shutdown() error {
 	// Create shutdown context with configurable timeout
@@ -134,6 +173,12 @@ func (a *App) shutdown() error {
 		a.Server.GRPC.GracefulStop()
 	}
 
+	// Wait for background jobs to finish their current batch
+	if a.Container != nil && a.Container.Scheduler != nil {
+		a.Logger.Info("waiting for background jobs...")
+		a.Container.Scheduler.Wait()
+	}
+
 	// Close container resources
 	if a.Container != nil {
 		a.Logger.Info("closing container resources...")
@@ -3,29 +3,42 @@ package di
 import (
 	"fmt"
 	"grpc-user-service/cmd/api/infrastructure"
+	"grpc-user-service/cmd/api/job"
 	"grpc-user-service/internal/adapter/cache"
 	ginhandler "grpc-user-service/internal/adapter/gin/handler"
 	"grpc-user-service/internal/adapter/grpc/middleware"
+	"grpc-user-service/internal/adapter/repository/batched"
 	"grpc-user-service/internal/adapter/repository/cached"
 	"grpc-user-service/internal/adapter/repository/postgres"
+	"grpc-user-service/internal/adapter/repository/resilient"
 	"grpc-user-service/internal/config"
 	"grpc-user-service/internal/usecase/user"
+	"grpc-user-service/pkg/breaker"
+	"grpc-user-service/pkg/dataloader"
+	"grpc-user-service/pkg/ratelimit"
 	redisclient "grpc-user-service/pkg/redis"
+	"grpc-user-service/pkg/scheduler"
+	"io"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 // Container holds all application dependencies
 type Container struct {
-	Config      *config.Config
-	Logger      *zap.Logger
-	DB          *gorm.DB
-	RedisClient *redisclient.Client
-	UserUC      user.Usecase
-	RateLimiter *middleware.RateLimiter
-	GinHandler  *ginhandler.UserHandler
+	Config       *config.Config
+	Logger       *zap.Logger
+	DB           *gorm.DB
+	RedisClient  *redisclient.Client
+	UserCache    cache.UserCache
+	UserUC       user.Usecase
+	RateLimiter  *middleware.RateLimiter
+	GinHandler   *ginhandler.UserHandler
+	AdminHandler *ginhandler.AdminHandler
+	Scheduler    *scheduler.Scheduler
+	CacheWarmer  *job.WarmUserCache // nil when warmup is disabled or there is no cache
 }
 
 // NewContainer creates and initializes all application dependencies
@@ -41,55 +54,208 @@ func NewContainer(cfg *config.Config, l *zap.Logger) (*Container, error) {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 
-	// Initialize Redis client
-	rdb, err := infrastructure.NewRedisClient(cfg, l)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize Redis: %w", err)
+	// Initialize Redis client; other backends run without Redis, keeping
+	// rate limit buckets and job locks in memory
+	var rdb *redisclient.Client
+	if cfg.Cache.Backend == "redis" {
+		rdb = infrastructure.NewRedisClient(cfg, l)
 	}
 
 	// Initialize cache layer
-	userCache := cache.NewRedisUserCache(
-		rdb.Client,
-		time.Duration(cfg.Redis.CacheTTL)*time.Second,
-		l,
-	)
+	cacheOpts := []cache.Option{
+		cache.WithNegativeTTL(time.Duration(cfg.Redis.NegativeTTL) * time.Second),
+		cache.WithTTLJitter(float64(cfg.Redis.TTLJitter) / 100),
+		cache.WithStaleWindow(time.Duration(cfg.Redis.StaleWindow) * time.Second),
+		cache.WithEarlyRefresh(time.Duration(cfg.Redis.EarlyRefresh) * time.Second),
+	}
+	keys, err := cfg.Cache.EncryptionKeyList()
+	if err != nil {
+		return nil, fmt.Errorf("invalid cache encryption keys: %w", err)
+	}
+	if len(keys) > 0 {
+		cacheKeys := make([]cache.EncryptionKey, len(keys))
+		for i, k := range keys {
+			cacheKeys[i] = cache.EncryptionKey{ID: k.ID, Secret: k.Secret}
+		}
+		keyring, err := cache.NewKeyring(cacheKeys...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build cache keyring: %w", err)
+		}
+		cacheOpts = append(cacheOpts, cache.WithEncryption(keyring))
+		if cfg.Cache.AcceptPlaintext {
+			cacheOpts = append(cacheOpts, cache.WithPlaintextReads())
+		}
+	}
+	var repoOpts []cached.Option
+	if cfg.Cache.DegradedModeEnabled {
+		cacheOpts = append(cacheOpts, cache.WithLastKnownGood(time.Duration(cfg.Cache.LastKnownGoodTTLSeconds)*time.Second))
+		repoOpts = append(repoOpts, cached.WithDegradedMode())
+	}
+	var userCache cache.UserCache
+	switch cfg.Cache.Backend {
+	case "redis":
+		userCache = cache.NewRedisUserCache(
+			rdb.Client,
+			time.Duration(cfg.Redis.CacheTTL)*time.Second,
+			l,
+			cacheOpts...,
+		)
+		if cfg.Breaker.Enabled {
+			// Below the local tier, which keeps serving while Redis is skipped
+			userCache = cache.NewBreakerUserCache(userCache, breaker.New("redis", breakerConfig(cfg), l))
+		}
+		if cfg.Cache.LocalEnabled {
+			userCache = cache.NewTieredUserCache(
+				userCache,
+				rdb.Client,
+				cfg.Cache.LocalMaxEntries,
+				time.Duration(cfg.Cache.LocalTTLSeconds)*time.Second,
+				l,
+			)
+		}
+	case "memory":
+		userCache = cache.NewMemoryUserCache(
+			cfg.Cache.MemoryMaxEntries,
+			time.Duration(cfg.Redis.CacheTTL)*time.Second,
+			l,
+			cacheOpts...,
+		)
+	default:
+		// No cache: the repository reads straight from the database
+		l.Warn("user cache disabled", zap.String("backend", cfg.Cache.Backend))
+	}
 
 	// Initialize repository
 	dbRepo := postgres.NewUserRepoPG(db, l)
-	repo := cached.NewCachedUserRepository(dbRepo, userCache, l)
+	var guardedRepo user.Repository = dbRepo
+	if cfg.DB.BatchEnabled {
+		guardedRepo = batched.NewUserRepository(dbRepo, dataloader.Config{
+			Wait:         time.Duration(cfg.DB.BatchWaitMs) * time.Millisecond,
+			MaxBatch:     cfg.DB.BatchMaxSize,
+			FetchTimeout: time.Duration(cfg.DB.BatchTimeoutMs) * time.Millisecond,
+		})
+	}
+	if cfg.Breaker.Enabled {
+		guardedRepo = resilient.NewUserRepository(guardedRepo, breaker.New("postgres", breakerConfig(cfg), l))
+	}
+	repo := cached.NewCachedUserRepository(guardedRepo, userCache, l, repoOpts...)
 
 	// Initialize use case
-	userUC := user.New(repo, l)
+	usernamePolicy, err := user.NewUsernamePolicy(
+		cfg.Username.MinLength,
+		cfg.Username.MaxLength,
+		cfg.Username.Charset,
+		cfg.Username.ReservedList(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build username policy: %w", err)
+	}
+	auditRepo := postgres.NewAuditRepoPG(db, l)
+	userUC := user.New(repo, l, user.WithUsernamePolicy(usernamePolicy), user.WithAuditLog(auditRepo))
 
 	// Initialize rate limiter
-	rateLimiter := middleware.NewRateLimiter(
-		rdb.Client,
-		middleware.RateLimiterConfig{
-			RequestsPerSecond: cfg.RateLimit.RequestsPerSecond,
-			BurstCapacity:     cfg.RateLimit.BurstCapacity,
-			Enabled:           cfg.RateLimit.Enabled,
-		},
-		l,
-	)
+	var limiterClient *redis.Client
+	if rdb != nil {
+		limiterClient = rdb.Client
+	}
+	rateLimitConfig := middleware.RateLimiterConfig{
+		RequestsPerSecond: cfg.RateLimit.RequestsPerSecond,
+		BurstCapacity:     cfg.RateLimit.BurstCapacity,
+		Algorithm:         ratelimit.Algorithm(cfg.RateLimit.Algorithm),
+		FailureMode:       ratelimit.FailureMode(cfg.RateLimit.FailureMode),
+		LocalShare:        float64(cfg.RateLimit.LocalShare) / 100,
+		Enabled:           cfg.RateLimit.Enabled,
+	}
+	if cfg.RateLimit.PolicyFile != "" {
+		rateLimitConfig.Policies, err = ratelimit.LoadPolicies(cfg.RateLimit.PolicyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rate limit policies: %w", err)
+		}
+	}
+	apiKeys, err := cfg.RateLimit.APIKeyList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rate limit API keys: %w", err)
+	}
+	if len(apiKeys) > 0 {
+		limiterKeys := make([]ratelimit.APIKey, len(apiKeys))
+		for i, k := range apiKeys {
+			limiterKeys[i] = ratelimit.APIKey{ID: k.ID, Tier: ratelimit.Tier(k.Tier), Secret: k.Secret}
+		}
+		rateLimitConfig.APIKeys = ratelimit.NewAPIKeys(limiterKeys)
+	}
+	rateLimiter := middleware.NewRateLimiter(limiterClient, rateLimitConfig, l)
 
 	// Initialize Gin handler
 	ginHandler := ginhandler.NewUserHandler(userUC, l)
 
+	// Initialize background jobs
+	// Fencing tokens stay above the ones recorded in job_fences, even if Redis
+	// loses its counters
+	jobScheduler := scheduler.New(rdb, l, scheduler.WithFenceStore(dbRepo))
+	jobDefaults := scheduler.JobOptions{
+		Retries:      cfg.Scheduler.Retries,
+		RetryBackoff: time.Duration(cfg.Scheduler.RetryBackoffSeconds) * time.Second,
+		Jitter:       time.Duration(cfg.Scheduler.JitterSeconds) * time.Second,
+	}
+	if cfg.Retention.Enabled {
+		opts := jobDefaults
+		opts.Schedule = cfg.Retention.Schedule
+		opts.Timeout = time.Duration(cfg.Retention.RunTimeoutSeconds) * time.Second
+		purge := job.NewPurgeDeletedUsers(
+			dbRepo,
+			time.Duration(cfg.Retention.DeletedUserDays)*24*time.Hour,
+			cfg.Retention.Mode,
+			cfg.Retention.BatchSize,
+			l,
+		)
+		if err := jobScheduler.Register(purge, opts); err != nil {
+			return nil, fmt.Errorf("failed to register retention job: %w", err)
+		}
+	}
+	cacheAdmin, _ := userCache.(ginhandler.CacheAdmin)
+	adminHandler := ginhandler.NewAdminHandler(jobScheduler, cacheAdmin, auditRepo, l)
+
+	// Initialize cache warmup, run once at startup
+	var cacheWarmer *job.WarmUserCache
+	if cfg.Cache.WarmupEnabled && userCache != nil {
+		cacheWarmer = job.NewWarmUserCache(auditRepo, guardedRepo, userCache, cfg.Cache.WarmupUsers, l)
+	}
+
 	return &Container{
-		Config:      cfg,
-		Logger:      l,
-		DB:          db,
-		RedisClient: rdb,
-		UserUC:      userUC,
-		RateLimiter: rateLimiter,
-		GinHandler:  ginHandler,
+		Config:       cfg,
+		Logger:       l,
+		DB:           db,
+		RedisClient:  rdb,
+		UserCache:    userCache,
+		UserUC:       userUC,
+		RateLimiter:  rateLimiter,
+		GinHandler:   ginHandler,
+		AdminHandler: adminHandler,
+		Scheduler:    jobScheduler,
+		CacheWarmer:  cacheWarmer,
 	}, nil
 }
 
+// breakerConfig returns the circuit breaker thresholds from configuration.
+func breakerConfig(cfg *config.Config) breaker.Config {
+	return breaker.Config{
+		FailureThreshold: cfg.Breaker.FailureThreshold,
+		OpenTimeout:      time.Duration(cfg.Breaker.OpenSeconds) * time.Second,
+		HalfOpenProbes:   cfg.Breaker.HalfOpenProbes,
+	}
+}
+
 // Close closes all resources held by the container
 func (c *Container) Close() error {
 	var errs []error
 
+	// Stop cache tiers holding their own Redis subscriptions
+	if closer, ok := c.UserCache.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close user cache: %w", err))
+		}
+	}
+
 	// Close Redis connection
 	if c.RedisClient != nil {
 		if err := c.RedisClient.Close(); err != nil {
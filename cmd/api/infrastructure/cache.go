@@ -1,15 +1,16 @@
 package infrastructure
 
 import (
-	"fmt"
 	"grpc-user-service/internal/config"
 	redisclient "grpc-user-service/pkg/redis"
 
 	"go.uber.org/zap"
 )
 
-// NewRedisClient creates a new Redis client with configuration
-func NewRedisClient(cfg *config.Config, l *zap.Logger) (*redisclient.Client, error) {
+// NewRedisClient creates a new Redis client with configuration. An unreachable
+// Redis does not prevent startup: the client reconnects once it is back, and
+// callers fall back in the meantime.
+func NewRedisClient(cfg *config.Config, l *zap.Logger) *redisclient.Client {
 	redisConfig := redisclient.Config{
 		Host:        cfg.Redis.Host,
 		Port:        cfg.Redis.Port,
@@ -20,10 +21,5 @@ func NewRedisClient(cfg *config.Config, l *zap.Logger) (*redisclient.Client, err
 		MinIdleConn: cfg.Redis.MinIdleConn,
 	}
 
-	rdb, err := redisclient.NewClient(redisConfig, l)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
-	}
-
-	return rdb, nil
+	return redisclient.Connect(redisConfig, l)
 }
@@ -0,0 +1,90 @@
+// Package job contains the background jobs run by the scheduler.
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"grpc-user-service/pkg/scheduler"
+)
+
+// Retention modes for PurgeDeletedUsers.
+const (
+	PurgeModeDelete    = "delete"    // Remove expired users permanently
+	PurgeModeAnonymize = "anonymize" // Erase personal data but keep the rows
+)
+
+// UserPurger removes or anonymizes soft-deleted users in batches. A batch
+// with a non-zero fencing token must be rejected once a higher token was used.
+type UserPurger interface {
+	PurgeDeleted(ctx context.Context, cutoff time.Time, limit int, token int64) (int64, error)
+	AnonymizeDeleted(ctx context.Context, cutoff time.Time, limit int, token int64) (int64, error)
+}
+
+// PurgeDeletedUsers enforces the retention period of soft-deleted users.
+// Users deleted longer than the retention period ago are purged or anonymized
+// in batches, each in its own short transaction.
+type PurgeDeletedUsers struct {
+	repo      UserPurger
+	retention time.Duration
+	mode      string
+	batchSize int
+	log       *zap.Logger
+	now       func() time.Time
+}
+
+// NewPurgeDeletedUsers creates the retention job for soft-deleted users.
+func NewPurgeDeletedUsers(repo UserPurger, retention time.Duration, mode string, batchSize int, log *zap.Logger) *PurgeDeletedUsers {
+	return &PurgeDeletedUsers{
+		repo:      repo,
+		retention: retention,
+		mode:      mode,
+		batchSize: batchSize,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// Name returns the job name.
+func (j *PurgeDeletedUsers) Name() string {
+	return "purge-deleted-users"
+}
+
+// Run processes batches until no expired users are left or ctx is done.
+// It returns the total number of users purged or anonymized. Every batch
+// carries the fencing token of the job lock, so that a run which lost its lock
+// stops at its next batch once a newer run has started.
+func (j *PurgeDeletedUsers) Run(ctx context.Context) (int64, error) {
+	var batch func(context.Context, time.Time, int, int64) (int64, error)
+	switch j.mode {
+	case PurgeModeDelete:
+		batch = j.repo.PurgeDeleted
+	case PurgeModeAnonymize:
+		batch = j.repo.AnonymizeDeleted
+	default:
+		return 0, fmt.Errorf("unknown retention mode %q", j.mode)
+	}
+
+	token, _ := scheduler.FencingToken(ctx)
+	cutoff := j.now().Add(-j.retention)
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		n, err := batch(ctx, cutoff, j.batchSize, token)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < int64(j.batchSize) {
+			return total, nil
+		}
+
+		j.log.Debug("retention batch processed", zap.String("mode", j.mode), zap.Int64("count", n), zap.Int64("total", total))
+	}
+}
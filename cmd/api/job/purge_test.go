@@ -0,0 +1,88 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"grpc-user-service/pkg/scheduler"
+)
+
+// fakePurger hands out batches from a fixed number of expired users
+type fakePurger struct {
+	remaining int64
+	calls     int
+	cutoff    time.Time
+	token     int64
+	err       error
+}
+
+func (p *fakePurger) batch(cutoff time.Time, limit int, token int64) (int64, error) {
+	p.calls++
+	p.cutoff = cutoff
+	p.token = token
+	if p.err != nil {
+		return 0, p.err
+	}
+	n := min(p.remaining, int64(limit))
+	p.remaining -= n
+	return n, nil
+}
+
+func (p *fakePurger) PurgeDeleted(ctx context.Context, cutoff time.Time, limit int, token int64) (int64, error) {
+	return p.batch(cutoff, limit, token)
+}
+
+func (p *fakePurger) AnonymizeDeleted(ctx context.Context, cutoff time.Time, limit int, token int64) (int64, error) {
+	return p.batch(cutoff, limit, token)
+}
+
+func TestPurgeDeletedUsers_RunsBatchesUntilDone(t *testing.T) {
+	repo := &fakePurger{remaining: 250}
+	job := NewPurgeDeletedUsers(repo, 30*24*time.Hour, PurgeModeDelete, 100, zaptest.NewLogger(t))
+	now := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	job.now = func() time.Time { return now }
+
+	n, err := job.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), n)
+	assert.Equal(t, 3, repo.calls)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), repo.cutoff)
+}
+
+func TestPurgeDeletedUsers_PassesFencingToken(t *testing.T) {
+	repo := &fakePurger{remaining: 150}
+	job := NewPurgeDeletedUsers(repo, time.Hour, PurgeModeAnonymize, 100, zaptest.NewLogger(t))
+
+	_, err := job.Run(scheduler.WithFencingToken(context.Background(), 42))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.calls)
+	assert.Equal(t, int64(42), repo.token)
+}
+
+func TestPurgeDeletedUsers_StopsOnError(t *testing.T) {
+	repo := &fakePurger{err: errors.New("db down")}
+	job := NewPurgeDeletedUsers(repo, time.Hour, PurgeModeAnonymize, 100, zaptest.NewLogger(t))
+
+	_, err := job.Run(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, repo.calls)
+}
+
+func TestPurgeDeletedUsers_UnknownMode(t *testing.T) {
+	repo := &fakePurger{}
+	job := NewPurgeDeletedUsers(repo, time.Hour, "shred", 100, zaptest.NewLogger(t))
+
+	_, err := job.Run(context.Background())
+
+	assert.Error(t, err)
+	assert.Zero(t, repo.calls)
+}
@@ -0,0 +1,92 @@
+package job
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"grpc-user-service/internal/adapter/cache"
+	domain "grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// RecentUserLister lists the users with the most recent activity.
+type RecentUserLister interface {
+	ListRecentUserIDs(ctx context.Context, limit int) ([]int64, error)
+}
+
+// UserGetter loads users from the database.
+type UserGetter interface {
+	GetByID(ctx context.Context, id int64) (*domain.User, error)
+}
+
+// WarmUserCache preloads the most recently created or updated users into the
+// user cache, so that the first wave of reads after a deploy or a cache flush
+// does not all land on the database. Users already cached, for example by
+// another replica warming the shared cache, are not loaded again.
+type WarmUserCache struct {
+	recent RecentUserLister
+	users  UserGetter
+	cache  cache.UserCache
+	limit  int
+	log    *zap.Logger
+}
+
+// NewWarmUserCache creates the cache warmup job for at most limit users.
+func NewWarmUserCache(recent RecentUserLister, users UserGetter, userCache cache.UserCache, limit int, log *zap.Logger) *WarmUserCache {
+	return &WarmUserCache{
+		recent: recent,
+		users:  users,
+		cache:  userCache,
+		limit:  limit,
+		log:    log,
+	}
+}
+
+// Name returns the job name.
+func (j *WarmUserCache) Name() string {
+	return "warm-user-cache"
+}
+
+// Run caches the most recently active users until done or ctx is done. It
+// returns the number of users loaded into the cache. Users deleted since are
+// skipped; any other failure stops the warmup.
+func (j *WarmUserCache) Run(ctx context.Context) (int64, error) {
+	ids, err := j.recent.ListRecentUserIDs(ctx, j.limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var warmed int64
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return warmed, err
+		}
+
+		cached, err := j.cache.Get(ctx, id)
+		if err != nil && !errors.Is(err, cache.ErrMissing) {
+			return warmed, err
+		}
+		if cached != nil {
+			continue
+		}
+
+		u, err := j.users.GetByID(ctx, id)
+		var notFoundErr *pkgerrors.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			continue
+		}
+		if err != nil {
+			return warmed, err
+		}
+
+		if err := j.cache.Set(ctx, u); err != nil {
+			return warmed, err
+		}
+		warmed++
+	}
+
+	j.log.Debug("user cache warmed", zap.Int("candidates", len(ids)), zap.Int64("loaded", warmed))
+	return warmed, nil
+}
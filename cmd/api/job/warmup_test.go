@@ -0,0 +1,79 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"grpc-user-service/internal/adapter/cache"
+	domain "grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// fakeRecent returns a fixed list of recently active user IDs
+type fakeRecent []int64
+
+func (f fakeRecent) ListRecentUserIDs(ctx context.Context, limit int) ([]int64, error) {
+	return f[:min(limit, len(f))], nil
+}
+
+// fakeUsers serves users from a map and counts lookups
+type fakeUsers struct {
+	users map[int64]*domain.User
+	calls int
+	err   error
+}
+
+func (f *fakeUsers) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	u, ok := f.users[id]
+	if !ok {
+		return nil, pkgerrors.NewNotFoundError("user", "user not found")
+	}
+	return u, nil
+}
+
+func TestWarmUserCache_LoadsRecentUsers(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	users := &fakeUsers{users: map[int64]*domain.User{
+		1: {ID: 1, Name: "John Doe"},
+		2: {ID: 2, Name: "Jane Doe"},
+		4: {ID: 4, Name: "Jim Doe"},
+	}}
+	userCache := cache.NewMemoryUserCache(100, time.Minute, logger)
+	ctx := context.Background()
+	require.NoError(t, userCache.Set(ctx, users.users[2]))
+
+	// 3 was deleted since, 2 is already cached and 4 is past the limit
+	job := NewWarmUserCache(fakeRecent{1, 2, 3, 4}, users, userCache, 3, logger)
+	n, err := job.Run(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.Equal(t, 2, users.calls)
+	cached, err := userCache.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", cached.Name)
+	cached, err = userCache.Get(ctx, 4)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func TestWarmUserCache_StopsOnError(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	users := &fakeUsers{err: pkgerrors.NewInternalError("database unavailable", errors.New("db down"))}
+	job := NewWarmUserCache(fakeRecent{1, 2, 3}, users, cache.NewMemoryUserCache(100, time.Minute, logger), 10, logger)
+
+	_, err := job.Run(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, users.calls)
+}
@@ -15,13 +15,15 @@ import (
 // SetupGinServer creates and configures the Gin REST API server
 func SetupGinServer(
 	handler *ginhandler.UserHandler,
+	adminHandler *ginhandler.AdminHandler,
+	adminToken string,
 	rateLimiter *grpcmiddleware.RateLimiter,
 	redisClient *redisclient.Client,
 	ginAddr string,
 	l *zap.Logger,
 ) (*http.Server, error) {
 	// Setup Gin router with all middleware and routes
-	router := ginrouter.SetupRouter(handler, rateLimiter, redisClient, l)
+	router := ginrouter.SetupRouter(handler, adminHandler, adminToken, rateLimiter.Enforcer(), redisClient, l)
 
 	l.Info("Gin REST API configured", zap.String("address", ginAddr))
 
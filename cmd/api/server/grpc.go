@@ -12,12 +12,14 @@ import (
 )
 
 // SetupGRPC creates and configures the gRPC server
-func SetupGRPC(userUC user.Usecase, l *zap.Logger, rateLimiter *middleware.RateLimiter) *grpc.Server {
-	// Create gRPC server with request ID and rate limit interceptors
+func SetupGRPC(userUC user.Usecase, l *zap.Logger, rateLimiter *middleware.RateLimiter, adminToken string) *grpc.Server {
+	// Create gRPC server with request ID, rate limit and admin auth interceptors
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			logger.RequestIDInterceptor(),
 			rateLimiter.UnaryInterceptor(),
+			middleware.AdminAuthInterceptor(adminToken, middleware.AdminMethods, l),
+			middleware.DegradedInterceptor(),
 		),
 	)
 	pb.RegisterUserServiceServer(grpcServer, grpcadapter.NewUserServiceServer(userUC, l))
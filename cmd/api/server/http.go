@@ -5,6 +5,7 @@ import (
 	"fmt"
 	pb "grpc-user-service/api/gen/go/user"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -16,8 +17,14 @@ import (
 
 // SetupHTTPGateway creates and configures the HTTP gateway server
 func SetupHTTPGateway(grpcAddr string, httpAddr string, l *zap.Logger) (*http.Server, error) {
-	// Create gRPC-Gateway mux
-	mux := runtime.NewServeMux()
+	// Create gRPC-Gateway mux; the API key header is forwarded, so that the
+	// rate limiter identifies the client
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+		if strings.EqualFold(key, "X-Api-Key") {
+			return "x-api-key", true
+		}
+		return runtime.DefaultHeaderMatcher(key)
+	}))
 	err := pb.RegisterUserServiceHandlerFromEndpoint(
 		context.Background(),
 		mux,
@@ -17,15 +17,16 @@ import (
 
 // Server struct holds all server dependencies
 type Server struct {
-	Config      *config.Config
-	Logger      *zap.Logger
-	UserUC      user.Usecase
-	GRPC        *grpc.Server
-	HTTP        *http.Server
-	Gin         *http.Server
-	GinHandler  *ginhandler.UserHandler
-	RateLimiter *middleware.RateLimiter
-	RedisClient *redisclient.Client
+	Config       *config.Config
+	Logger       *zap.Logger
+	UserUC       user.Usecase
+	GRPC         *grpc.Server
+	HTTP         *http.Server
+	Gin          *http.Server
+	GinHandler   *ginhandler.UserHandler
+	AdminHandler *ginhandler.AdminHandler
+	RateLimiter  *middleware.RateLimiter
+	RedisClient  *redisclient.Client
 }
 
 // New creates a new server instance
@@ -35,16 +36,18 @@ func New(
 	userUC user.Usecase,
 	rateLimiter *middleware.RateLimiter,
 	ginHandler *ginhandler.UserHandler,
+	adminHandler *ginhandler.AdminHandler,
 	redisClient *redisclient.Client,
 ) *Server {
 	return &Server{
-		Config:      cfg,
-		Logger:      l,
-		UserUC:      userUC,
-		GRPC:        SetupGRPC(userUC, l, rateLimiter),
-		GinHandler:  ginHandler,
-		RateLimiter: rateLimiter,
-		RedisClient: redisClient,
+		Config:       cfg,
+		Logger:       l,
+		UserUC:       userUC,
+		GRPC:         SetupGRPC(userUC, l, rateLimiter, cfg.Admin.Token),
+		GinHandler:   ginHandler,
+		AdminHandler: adminHandler,
+		RateLimiter:  rateLimiter,
+		RedisClient:  redisClient,
 	}
 }
 
@@ -112,6 +115,8 @@ func (s *Server) ginAddress() string {
 func (s *Server) startGinServer() error {
 	ginServer, err := SetupGinServer(
 		s.GinHandler,
+		s.AdminHandler,
+		s.Config.Admin.Token,
 		s.RateLimiter,
 		s.RedisClient,
 		s.ginAddress(),
@@ -0,0 +1,5 @@
+-- Drop contact fields from users
+ALTER TABLE users
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS locale,
+    DROP COLUMN IF EXISTS phone;
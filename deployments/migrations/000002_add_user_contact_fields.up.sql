@@ -0,0 +1,5 @@
+-- Add optional contact fields to users
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS phone VARCHAR(16),
+    ADD COLUMN IF NOT EXISTS locale VARCHAR(35),
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
@@ -0,0 +1,6 @@
+-- Drop structured name fields
+DROP INDEX IF EXISTS idx_users_name_order;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS display_name,
+    DROP COLUMN IF EXISTS family_name,
+    DROP COLUMN IF EXISTS given_name;
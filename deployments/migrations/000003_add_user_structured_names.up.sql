@@ -0,0 +1,15 @@
+-- Add structured name fields; name is kept as a computed compatibility column
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS given_name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS family_name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS display_name VARCHAR(100);
+
+-- Existing single-string names become display names
+UPDATE users SET display_name = name WHERE display_name IS NULL;
+
+-- Support ListUsers ordering (family name, then given name) with ICU collation
+CREATE INDEX IF NOT EXISTS idx_users_name_order ON users (
+    (COALESCE(NULLIF(family_name, ''), name) COLLATE "und-x-icu"),
+    given_name COLLATE "und-x-icu",
+    id
+);
@@ -0,0 +1,4 @@
+-- Drop username
+DROP INDEX IF EXISTS idx_users_username_lower;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS username;
@@ -0,0 +1,9 @@
+-- Add optional unique username; NULL means the user has not claimed one
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS username VARCHAR(100);
+
+-- Usernames are stored lowercase; the expression index also guards against
+-- rows written outside the service with different casing
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_lower
+    ON users (LOWER(username))
+    WHERE username IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Drop user emails; users.email keeps the primary address
+DROP TABLE IF EXISTS user_emails;
@@ -0,0 +1,23 @@
+-- Email addresses of a user; users.email mirrors the primary address
+CREATE TABLE IF NOT EXISTS user_emails (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    address VARCHAR(254) NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    verified BOOLEAN NOT NULL DEFAULT FALSE,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- An address belongs to at most one user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_emails_address ON user_emails(address);
+
+-- Every user has at most one primary address
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_emails_primary ON user_emails(user_id) WHERE is_primary;
+
+CREATE INDEX IF NOT EXISTS idx_user_emails_user_id ON user_emails(user_id);
+
+-- Existing addresses become unverified primary addresses
+INSERT INTO user_emails (user_id, address, is_primary)
+SELECT id, email, TRUE FROM users
+ON CONFLICT (address) DO NOTHING;
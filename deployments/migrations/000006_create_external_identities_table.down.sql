@@ -0,0 +1,2 @@
+-- Drop linked external identities
+DROP TABLE IF EXISTS external_identities;
@@ -0,0 +1,13 @@
+-- Accounts at external identity providers linked to users
+CREATE TABLE IF NOT EXISTS external_identities (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(64) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A provider account maps to exactly one user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_external_identities_provider_subject ON external_identities(provider, subject);
+
+CREATE INDEX IF NOT EXISTS idx_external_identities_user_id ON external_identities(user_id);
@@ -0,0 +1,3 @@
+-- Drop the audit trail and erasure tombstones
+DROP TABLE IF EXISTS erasure_tombstones;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,18 @@
+-- Append-only trail of operations on users. Entries carry no personal data and
+-- have no foreign key so they outlive the user they describe.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    details VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_user_id ON audit_log(user_id);
+
+-- One row per erased user, recording when the erasure ran
+CREATE TABLE IF NOT EXISTS erasure_tombstones (
+    user_id BIGINT PRIMARY KEY,
+    reason VARCHAR(255),
+    erased_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
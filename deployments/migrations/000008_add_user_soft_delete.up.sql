@@ -0,0 +1,15 @@
+-- Deleted users are kept until the retention job purges them
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Partial index used by the retention job to find expired users
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Emails and usernames of deleted users can be claimed again right away
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_active ON users(email) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_users_username_lower;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_lower
+    ON users (LOWER(username))
+    WHERE username IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- Drop the job fencing tokens
+DROP TABLE IF EXISTS job_fences;
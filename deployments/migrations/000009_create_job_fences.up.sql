@@ -0,0 +1,8 @@
+-- Highest fencing token seen per scheduled job. Batches of a job only commit
+-- while their token is at least the stored one, so a run that lost its lock
+-- cannot keep writing after a newer run has started.
+CREATE TABLE IF NOT EXISTS job_fences (
+    name VARCHAR(100) PRIMARY KEY,
+    token BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- Drop the pending email verification tokens
+ALTER TABLE user_emails DROP COLUMN IF EXISTS verification_expires_at;
+ALTER TABLE user_emails DROP COLUMN IF EXISTS verification_token_hash;
@@ -0,0 +1,4 @@
+-- Pending verification token of an address. Only the SHA-256 of the token is
+-- stored; verifying the address consumes it.
+ALTER TABLE user_emails ADD COLUMN IF NOT EXISTS verification_token_hash VARCHAR(64);
+ALTER TABLE user_emails ADD COLUMN IF NOT EXISTS verification_expires_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,316 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domain "grpc-user-service/internal/domain/user"
+)
+
+// ErrAdminUnsupported is returned for admin operations on a cache that does
+// not implement Admin.
+var ErrAdminUnsupported = errors.New("cache: admin operations not supported")
+
+// ErrInvalidPattern is returned by EvictPattern for a malformed pattern or one
+// reaching outside the user cache namespace.
+var ErrInvalidPattern = errors.New("cache: invalid pattern")
+
+// namespaces are the key prefixes owned by the user cache. Rate limiter
+// buckets and scheduler locks share Redis and must never be evicted here.
+var namespaces = []string{"user:", "users:"}
+
+// scanCount is the number of keys requested per SCAN call, and the size of
+// the DEL batches issued while evicting.
+const scanCount = 500
+
+// Entry describes a cached user for inspection.
+type Entry struct {
+	User          *domain.User  // User is the cached user, nil for a tombstone
+	Missing       bool          // Missing marks a tombstone for an ID that does not exist
+	FreshUntil    time.Time     // FreshUntil is when the entry goes stale; zero for a tombstone
+	ExpiresIn     time.Duration // ExpiresIn is the time left before the entry is dropped
+	LastKnownGood bool          // LastKnownGood reports whether a degraded mode copy is held
+	Local         bool          // Local reports whether this replica also holds it in its local tier
+}
+
+// Admin is implemented by caches that support operational inspection and
+// eviction. Patterns are Redis glob patterns over cache keys, such as
+// "user:42", "user:username:*" or "users:list:*", and must stay within the
+// user cache namespace.
+type Admin interface {
+	// Inspect returns the entry cached for a user ID, or nil if there is none.
+	Inspect(ctx context.Context, id int64) (*Entry, error)
+
+	// EvictPattern removes the keys matching pattern and returns how many
+	// were removed. The users generation is never removed.
+	EvictPattern(ctx context.Context, pattern string) (int64, error)
+
+	// Flush removes every key of the user cache and bumps the users
+	// generation, and returns how many keys were removed.
+	Flush(ctx context.Context) (int64, error)
+}
+
+// AdminOf returns the Admin of c, or ErrAdminUnsupported if it has none.
+func AdminOf(c UserCache) (Admin, error) {
+	a, ok := c.(Admin)
+	if !ok {
+		return nil, ErrAdminUnsupported
+	}
+	return a, nil
+}
+
+// checkPattern validates an eviction pattern.
+func checkPattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPattern, err)
+	}
+	for _, ns := range namespaces {
+		if strings.HasPrefix(pattern, ns) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q is outside the %s namespace", ErrInvalidPattern, pattern, strings.Join(namespaces, " and "))
+}
+
+// matches reports whether key matches a pattern already validated by checkPattern.
+func matches(pattern, key string) bool {
+	ok, _ := path.Match(pattern, key)
+	return ok
+}
+
+// Inspect returns the entry cached in Redis for a user ID.
+func (c *RedisUserCache) Inspect(ctx context.Context, id int64) (*Entry, error) {
+	var get *redis.StringCmd
+	var ttl *redis.DurationCmd
+	var lkg *redis.IntCmd
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, userKey(id))
+		ttl = pipe.PTTL(ctx, userKey(id))
+		lkg = pipe.Exists(ctx, lastKnownGoodKey(id))
+		return nil
+	})
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		c.log.Error("failed to inspect cached user", zap.Int64("user_id", id), zap.Error(err))
+		return nil, err
+	}
+
+	data, _ := get.Bytes()
+	entry := &Entry{ExpiresIn: max(ttl.Val(), 0), LastKnownGood: lkg.Val() > 0}
+	if string(data) == tombstone {
+		entry.Missing = true
+		return entry, nil
+	}
+
+	cached, err := c.decodeUser(data)
+	if errors.Is(err, errUnreadable) {
+		return nil, nil
+	}
+	if err != nil {
+		c.log.Error("failed to decode cached user", zap.Int64("user_id", id), zap.Error(err))
+		return nil, err
+	}
+	entry.User = cached.User
+	entry.FreshUntil = time.UnixMilli(cached.FreshUntil)
+	return entry, nil
+}
+
+// EvictPattern removes the Redis keys matching pattern.
+func (c *RedisUserCache) EvictPattern(ctx context.Context, pattern string) (int64, error) {
+	if err := checkPattern(pattern); err != nil {
+		return 0, err
+	}
+	return c.deleteMatching(ctx, pattern)
+}
+
+// Flush removes every key of the user cache from Redis.
+func (c *RedisUserCache) Flush(ctx context.Context) (int64, error) {
+	var total int64
+	for _, ns := range namespaces {
+		n, err := c.deleteMatching(ctx, ns+"*")
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+
+	// List pages written by readers that raced the flush land in the old generation
+	return total, c.BumpGeneration(ctx)
+}
+
+// deleteMatching scans for the keys matching pattern and deletes them in
+// batches. Keys present for the whole scan are guaranteed to be seen.
+func (c *RedisUserCache) deleteMatching(ctx context.Context, pattern string) (int64, error) {
+	var deleted int64
+	batch := make([]string, 0, scanCount)
+	del := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := c.client.Del(ctx, batch...).Result()
+		deleted += n
+		batch = batch[:0]
+		return err
+	}
+
+	iter := c.client.Scan(ctx, 0, pattern, scanCount).Iterator()
+	for iter.Next(ctx) {
+		if key := iter.Val(); key != generationKey {
+			batch = append(batch, key)
+		}
+		if len(batch) == scanCount {
+			if err := del(); err != nil {
+				c.log.Error("failed to evict from cache", zap.String("pattern", pattern), zap.Error(err))
+				return deleted, err
+			}
+		}
+	}
+	if err := errors.Join(iter.Err(), del()); err != nil {
+		c.log.Error("failed to evict from cache", zap.String("pattern", pattern), zap.Error(err))
+		return deleted, err
+	}
+
+	c.log.Info("evicted from cache", zap.String("pattern", pattern), zap.Int64("count", deleted))
+	return deleted, nil
+}
+
+// Inspect returns the entry cached in memory for a user ID.
+func (c *MemoryUserCache) Inspect(_ context.Context, id int64) (*Entry, error) {
+	e, expires, ok := c.users.peek(id)
+	if !ok {
+		return nil, nil
+	}
+	_, _, lkg := c.lastKnownGood.peek(id)
+
+	entry := &Entry{Missing: e.missing, ExpiresIn: max(expires.Sub(c.now()), 0), LastKnownGood: lkg}
+	if !e.missing {
+		u := e.user
+		entry.User = &u
+		entry.FreshUntil = e.freshUntil
+	}
+	return entry, nil
+}
+
+// EvictPattern removes the entries whose Redis key would match pattern.
+func (c *MemoryUserCache) EvictPattern(_ context.Context, pattern string) (int64, error) {
+	if err := checkPattern(pattern); err != nil {
+		return 0, err
+	}
+
+	n := c.users.removeIf(func(id int64) bool { return matches(pattern, userKey(id)) }) +
+		c.lastKnownGood.removeIf(func(id int64) bool { return matches(pattern, lastKnownGoodKey(id)) }) +
+		c.usernames.removeIf(func(username string) bool { return matches(pattern, usernameKey(username)) }) +
+		c.emails.removeIf(func(email string) bool { return matches(pattern, emailKey(email)) }) +
+		c.lists.removeIf(func(k listKey) bool {
+			return matches(pattern, listPageKey(k.generation, k.query, k.locale, k.page, k.limit))
+		})
+
+	c.log.Info("evicted from cache", zap.String("pattern", pattern), zap.Int("count", n))
+	return int64(n), nil
+}
+
+// Flush removes every entry from memory.
+func (c *MemoryUserCache) Flush(ctx context.Context) (int64, error) {
+	all := func(string) bool { return true }
+	n := c.users.removeIf(func(int64) bool { return true }) +
+		c.lastKnownGood.removeIf(func(int64) bool { return true }) +
+		c.usernames.removeIf(all) +
+		c.emails.removeIf(all) +
+		c.lists.removeIf(func(listKey) bool { return true })
+
+	c.log.Info("flushed cache", zap.Int("count", n))
+	return int64(n), c.BumpGeneration(ctx)
+}
+
+// Inspect returns the entry held by the remote cache for a user ID, noting
+// whether this replica also holds it locally.
+func (c *TieredUserCache) Inspect(ctx context.Context, id int64) (*Entry, error) {
+	a, err := AdminOf(c.UserCache)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := a.Inspect(ctx, id)
+	if err != nil || entry == nil {
+		return entry, err
+	}
+
+	_, _, entry.Local = c.local.peek(id)
+	return entry, nil
+}
+
+// EvictPattern removes the keys matching pattern from the remote cache, and
+// clears the local tier of every replica.
+func (c *TieredUserCache) EvictPattern(ctx context.Context, pattern string) (int64, error) {
+	a, err := AdminOf(c.UserCache)
+	if err != nil {
+		return 0, err
+	}
+	n, err := a.EvictPattern(ctx, pattern)
+	if errors.Is(err, ErrInvalidPattern) {
+		return n, err
+	}
+	return n, errors.Join(err, c.purgeAll(ctx))
+}
+
+// Flush removes every key from the remote cache, and clears the local tier of
+// every replica.
+func (c *TieredUserCache) Flush(ctx context.Context) (int64, error) {
+	a, err := AdminOf(c.UserCache)
+	if err != nil {
+		return 0, err
+	}
+	n, err := a.Flush(ctx)
+	return n, errors.Join(err, c.purgeAll(ctx))
+}
+
+// purgeAll clears the local tier here and on every other replica. Local
+// entries are only held by ID, so they are dropped wholesale rather than
+// matched: the tier refills within its short TTL anyway.
+func (c *TieredUserCache) purgeAll(ctx context.Context) error {
+	c.local.purge()
+
+	if err := c.client.Publish(ctx, InvalidationChannel, purgeMessage).Err(); err != nil {
+		c.log.Error("failed to publish cache purge", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Inspect returns the entry cached for a user ID.
+func (c *BreakerUserCache) Inspect(ctx context.Context, id int64) (*Entry, error) {
+	a, err := AdminOf(c.cache)
+	if err != nil {
+		return nil, err
+	}
+	return guard(c, func() (*Entry, error) { return a.Inspect(ctx, id) })
+}
+
+// EvictPattern removes the keys matching pattern.
+func (c *BreakerUserCache) EvictPattern(ctx context.Context, pattern string) (int64, error) {
+	a, err := AdminOf(c.cache)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkPattern(pattern); err != nil {
+		return 0, err
+	}
+	return guard(c, func() (int64, error) { return a.EvictPattern(ctx, pattern) })
+}
+
+// Flush removes every key of the user cache.
+func (c *BreakerUserCache) Flush(ctx context.Context) (int64, error) {
+	a, err := AdminOf(c.cache)
+	if err != nil {
+		return 0, err
+	}
+	return guard(c, func() (int64, error) { return a.Flush(ctx) })
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	domain "grpc-user-service/internal/domain/user"
+)
+
+// fillCache stores a user, a tombstone, a username, an email and a list page
+func fillCache(t *testing.T, c UserCache) {
+	ctx := context.Background()
+	require.NoError(t, c.Set(ctx, testUser()))
+	require.NoError(t, c.SetMissing(ctx, 2))
+	require.NoError(t, c.SetUsername(ctx, "johndoe", 1))
+	require.NoError(t, c.SetEmail(ctx, "john@example.com", 1))
+	require.NoError(t, c.SetList(ctx, 0, "", "", 1, 10, &UserList{Users: []domain.User{*testUser()}, Total: 1}))
+}
+
+func TestAdmin(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	caches := map[string]func(t *testing.T) (UserCache, *miniredis.Miniredis){
+		"redis": func(t *testing.T) (UserCache, *miniredis.Miniredis) {
+			client, mr := setupTestRedis(t)
+			return NewRedisUserCache(client, time.Minute, logger, WithLastKnownGood(time.Hour)), mr
+		},
+		"memory": func(t *testing.T) (UserCache, *miniredis.Miniredis) {
+			return NewMemoryUserCache(100, time.Minute, logger, WithLastKnownGood(time.Hour)), nil
+		},
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			t.Run("Inspect", func(t *testing.T) {
+				c, _ := newCache(t)
+				fillCache(t, c)
+				a, err := AdminOf(c)
+				require.NoError(t, err)
+
+				entry, err := a.Inspect(ctx, testUser().ID)
+				require.NoError(t, err)
+				require.NotNil(t, entry)
+				assert.Equal(t, testUser().Email, entry.User.Email)
+				assert.True(t, entry.LastKnownGood)
+				assert.False(t, entry.FreshUntil.IsZero())
+				assert.Positive(t, entry.ExpiresIn)
+
+				entry, err = a.Inspect(ctx, 2)
+				require.NoError(t, err)
+				require.NotNil(t, entry)
+				assert.True(t, entry.Missing)
+				assert.Nil(t, entry.User)
+
+				entry, err = a.Inspect(ctx, 3)
+				require.NoError(t, err)
+				assert.Nil(t, entry)
+			})
+
+			t.Run("EvictPattern", func(t *testing.T) {
+				c, _ := newCache(t)
+				fillCache(t, c)
+				a, err := AdminOf(c)
+				require.NoError(t, err)
+
+				n, err := a.EvictPattern(ctx, "user:username:*")
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), n)
+
+				id, err := c.GetIDByUsername(ctx, "johndoe")
+				require.NoError(t, err)
+				assert.Zero(t, id)
+				u, err := c.Get(ctx, testUser().ID)
+				require.NoError(t, err)
+				assert.NotNil(t, u, "other keys are kept")
+
+				_, err = a.EvictPattern(ctx, "ratelimit:*")
+				assert.ErrorIs(t, err, ErrInvalidPattern)
+				_, err = a.EvictPattern(ctx, "user:[")
+				assert.ErrorIs(t, err, ErrInvalidPattern)
+			})
+
+			t.Run("Flush", func(t *testing.T) {
+				c, mr := newCache(t)
+				fillCache(t, c)
+				if mr != nil {
+					require.NoError(t, mr.Set("ratelimit:tb:/user.v1.UserService/GetUser:127.0.0.1", "1"))
+				}
+				a, err := AdminOf(c)
+				require.NoError(t, err)
+
+				n, err := a.Flush(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, int64(6), n, "user, last known good, tombstone, username, email and list page")
+
+				u, err := c.Get(ctx, testUser().ID)
+				require.NoError(t, err)
+				assert.Nil(t, u)
+				generation, err := c.Generation(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), generation)
+				if mr != nil {
+					assert.True(t, mr.Exists("ratelimit:tb:/user.v1.UserService/GetUser:127.0.0.1"), "keys outside the namespace are kept")
+				}
+			})
+		})
+	}
+}
+
+func TestTieredUserCache_FlushClearsOtherReplicas(t *testing.T) {
+	mr := miniredis.RunT(t)
+	a := newTestReplica(t, mr.Addr())
+	b := newTestReplica(t, mr.Addr())
+	ctx := context.Background()
+
+	require.NoError(t, a.Set(ctx, &domain.User{ID: 1, Name: "John Doe"}))
+	_, err := b.Get(ctx, 1)
+	require.NoError(t, err)
+
+	entry, err := b.Inspect(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.True(t, entry.Local)
+
+	n, err := a.Flush(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	require.Eventually(t, func() bool {
+		return b.local.len() == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestAdminOf_Unsupported(t *testing.T) {
+	_, err := AdminOf(nil)
+	assert.ErrorIs(t, err, ErrAdminUnsupported)
+}
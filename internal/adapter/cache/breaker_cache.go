@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	domain "grpc-user-service/internal/domain/user"
+	"grpc-user-service/pkg/breaker"
+)
+
+// BreakerUserCache guards a UserCache with a circuit breaker. Once the cache
+// has failed repeatedly, calls fail immediately with breaker.ErrOpen instead
+// of each waiting for a timeout, and callers fall back as on any cache error.
+// ErrMissing is a result, not a failure of the cache.
+type BreakerUserCache struct {
+	cache   UserCache
+	breaker *breaker.Breaker
+}
+
+// NewBreakerUserCache creates a BreakerUserCache around cache.
+func NewBreakerUserCache(cache UserCache, b *breaker.Breaker) *BreakerUserCache {
+	return &BreakerUserCache{cache: cache, breaker: b}
+}
+
+// guard calls fn through the breaker of c.
+func guard[T any](c *BreakerUserCache, fn func() (T, error)) (T, error) {
+	var value T
+	var result error
+	err := c.breaker.Do(func() error {
+		value, result = fn()
+		if errors.Is(result, ErrMissing) {
+			return nil
+		}
+		return result
+	})
+	if errors.Is(err, breaker.ErrOpen) {
+		return value, err
+	}
+	return value, result
+}
+
+// guardErr is guard for calls returning only an error.
+func guardErr(c *BreakerUserCache, fn func() error) error {
+	_, err := guard(c, func() (struct{}, error) { return struct{}{}, fn() })
+	return err
+}
+
+// Get retrieves a user from cache by ID.
+func (c *BreakerUserCache) Get(ctx context.Context, id int64) (*domain.User, error) {
+	return guard(c, func() (*domain.User, error) { return c.cache.Get(ctx, id) })
+}
+
+// Lookup retrieves a user from cache by ID and reports whether it should be refreshed.
+func (c *BreakerUserCache) Lookup(ctx context.Context, id int64) (*domain.User, bool, error) {
+	var refresh bool
+	u, err := guard(c, func() (*domain.User, error) {
+		u, r, err := c.cache.Lookup(ctx, id)
+		refresh = r
+		return u, err
+	})
+	return u, refresh, err
+}
+
+// GetLastKnownGood retrieves the last known good copy of a user.
+func (c *BreakerUserCache) GetLastKnownGood(ctx context.Context, id int64) (*domain.User, error) {
+	return guard(c, func() (*domain.User, error) { return c.cache.GetLastKnownGood(ctx, id) })
+}
+
+// Set stores a user in cache.
+func (c *BreakerUserCache) Set(ctx context.Context, user *domain.User) error {
+	return guardErr(c, func() error { return c.cache.Set(ctx, user) })
+}
+
+// SetMissing stores a tombstone for a missing user ID.
+func (c *BreakerUserCache) SetMissing(ctx context.Context, id int64) error {
+	return guardErr(c, func() error { return c.cache.SetMissing(ctx, id) })
+}
+
+// Delete removes a user from cache by ID.
+func (c *BreakerUserCache) Delete(ctx context.Context, id int64) error {
+	return guardErr(c, func() error { return c.cache.Delete(ctx, id) })
+}
+
+// DeleteMultiple removes multiple users from cache by IDs.
+func (c *BreakerUserCache) DeleteMultiple(ctx context.Context, ids ...int64) error {
+	return guardErr(c, func() error { return c.cache.DeleteMultiple(ctx, ids...) })
+}
+
+// GetIDByUsername retrieves the user ID cached for a username.
+func (c *BreakerUserCache) GetIDByUsername(ctx context.Context, username string) (int64, error) {
+	return guard(c, func() (int64, error) { return c.cache.GetIDByUsername(ctx, username) })
+}
+
+// SetUsername stores a username to user ID mapping.
+func (c *BreakerUserCache) SetUsername(ctx context.Context, username string, id int64) error {
+	return guardErr(c, func() error { return c.cache.SetUsername(ctx, username, id) })
+}
+
+// DeleteUsername removes a username mapping from cache.
+func (c *BreakerUserCache) DeleteUsername(ctx context.Context, username string) error {
+	return guardErr(c, func() error { return c.cache.DeleteUsername(ctx, username) })
+}
+
+// Generation returns the current users generation.
+func (c *BreakerUserCache) Generation(ctx context.Context) (int64, error) {
+	return guard(c, func() (int64, error) { return c.cache.Generation(ctx) })
+}
+
+// BumpGeneration advances the users generation.
+func (c *BreakerUserCache) BumpGeneration(ctx context.Context) error {
+	return guardErr(c, func() error { return c.cache.BumpGeneration(ctx) })
+}
+
+// GetList retrieves a cached list page.
+func (c *BreakerUserCache) GetList(ctx context.Context, generation int64, query, locale string, page, limit int64) (*UserList, error) {
+	return guard(c, func() (*UserList, error) { return c.cache.GetList(ctx, generation, query, locale, page, limit) })
+}
+
+// SetList stores a list page.
+func (c *BreakerUserCache) SetList(ctx context.Context, generation int64, query, locale string, page, limit int64, list *UserList) error {
+	return guardErr(c, func() error { return c.cache.SetList(ctx, generation, query, locale, page, limit, list) })
+}
+
+// GetIDByEmail retrieves the ID of the user owning an email address.
+func (c *BreakerUserCache) GetIDByEmail(ctx context.Context, email string) (int64, bool, error) {
+	var found bool
+	id, err := guard(c, func() (int64, error) {
+		id, f, err := c.cache.GetIDByEmail(ctx, email)
+		found = f
+		return id, err
+	})
+	return id, found, err
+}
+
+// SetEmail stores an email to user ID mapping.
+func (c *BreakerUserCache) SetEmail(ctx context.Context, email string, id int64) error {
+	return guardErr(c, func() error { return c.cache.SetEmail(ctx, email, id) })
+}
+
+// DeleteEmails removes email mappings from cache.
+func (c *BreakerUserCache) DeleteEmails(ctx context.Context, emails ...string) error {
+	return guardErr(c, func() error { return c.cache.DeleteEmails(ctx, emails...) })
+}
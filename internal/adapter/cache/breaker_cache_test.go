@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"grpc-user-service/pkg/breaker"
+)
+
+func TestBreakerUserCache(t *testing.T) {
+	client, mr := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	b := breaker.New("test-cache", breaker.Config{FailureThreshold: 2, OpenTimeout: time.Minute, HalfOpenProbes: 1}, logger)
+	c := NewBreakerUserCache(NewRedisUserCache(client, time.Minute, logger), b)
+	ctx := context.Background()
+
+	// Tombstones are answers, not failures
+	for range 3 {
+		require.NoError(t, c.SetMissing(ctx, 1))
+		_, err := c.Get(ctx, 1)
+		assert.ErrorIs(t, err, ErrMissing)
+	}
+	assert.Equal(t, breaker.Closed, b.State())
+
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+	for range 2 {
+		_, _, err := c.Lookup(ctx, 1)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, breaker.ErrOpen)
+	}
+	assert.Equal(t, breaker.Open, b.State())
+
+	_, _, err := c.GetIDByEmail(ctx, "john@example.com")
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	assert.ErrorIs(t, c.Set(ctx, testUser()), breaker.ErrOpen)
+}
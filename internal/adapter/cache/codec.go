@@ -0,0 +1,356 @@
+package cache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	domain "grpc-user-service/internal/domain/user"
+)
+
+// SchemaVersion is the version of the layout of cached records. Bump it
+// whenever a record changes incompatibly: entries written with any other
+// version are treated as cache misses instead of being misread.
+const SchemaVersion = 1
+
+// errUnreadable is returned when a cached entry has an unknown schema version,
+// is encrypted with an unknown key, or is corrupt. Callers treat it as a miss.
+var errUnreadable = errors.New("cache: unreadable entry")
+
+// Every cached value is wrapped in an envelope, encoded in protobuf wire format:
+//
+//	1: schema version (varint)
+//	2: key ID (string, only when the payload is encrypted)
+//	3: payload (bytes), nonce followed by ciphertext when encrypted
+//
+// Records inside the payload are protobuf messages as well, so fields can be
+// added within a schema version: decoders skip fields they do not know.
+const (
+	envelopeVersion protowire.Number = 1
+	envelopeKeyID   protowire.Number = 2
+	envelopePayload protowire.Number = 3
+)
+
+// EncryptionKey is an AES key used to encrypt cached payloads.
+type EncryptionKey struct {
+	ID     string // Stored with each entry to select the key for decryption
+	Secret []byte // 16, 24 or 32 bytes for AES-128, AES-192 or AES-256
+}
+
+// Keyring holds the keys used to encrypt cached payloads with AES-GCM. The
+// first key encrypts new entries; all keys decrypt, so a new key can be
+// rolled out in front of the old one without invalidating the cache.
+type Keyring struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+}
+
+// NewKeyring creates a Keyring. The first key is the primary one.
+func NewKeyring(keys ...EncryptionKey) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("keyring needs at least one key")
+	}
+
+	k := &Keyring{primary: keys[0].ID, aeads: make(map[string]cipher.AEAD, len(keys))}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, fmt.Errorf("encryption key ID cannot be empty")
+		}
+		if _, ok := k.aeads[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate encryption key ID %q", key.ID)
+		}
+		block, err := aes.NewCipher(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", key.ID, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", key.ID, err)
+		}
+		k.aeads[key.ID] = aead
+	}
+	return k, nil
+}
+
+// seal wraps a payload in an envelope, encrypting it with the primary key of
+// keyring if there is one.
+func seal(keyring *Keyring, payload []byte) ([]byte, error) {
+	var keyID string
+	if keyring != nil {
+		keyID = keyring.primary
+		aead := keyring.aeads[keyID]
+
+		nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(payload)+aead.Overhead())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		payload = aead.Seal(nonce, nonce, payload, additionalData(SchemaVersion, keyID))
+	}
+
+	var b []byte
+	b = protowire.AppendTag(b, envelopeVersion, protowire.VarintType)
+	b = protowire.AppendVarint(b, SchemaVersion)
+	if keyID != "" {
+		b = protowire.AppendTag(b, envelopeKeyID, protowire.BytesType)
+		b = protowire.AppendString(b, keyID)
+	}
+	b = protowire.AppendTag(b, envelopePayload, protowire.BytesType)
+	b = protowire.AppendBytes(b, payload)
+	return b, nil
+}
+
+// open unwraps an envelope and returns its payload. It returns errUnreadable
+// for entries of another schema version, entries encrypted with a key not in
+// keyring, and corrupt entries. When keyring is set, unencrypted entries are
+// unreadable too, since anyone with write access to the cache could forge
+// them, unless acceptPlaintext allows them while encryption is rolled out.
+func open(keyring *Keyring, acceptPlaintext bool, data []byte) ([]byte, error) {
+	var version uint64
+	var keyID string
+	var payload []byte
+
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, b []byte) int {
+		switch {
+		case num == envelopeVersion && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			version = v
+			return n
+		case num == envelopeKeyID && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			keyID = v
+			return n
+		case num == envelopePayload && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			payload = v
+			return n
+		}
+		return protowire.ConsumeFieldValue(num, typ, b)
+	})
+	if err != nil || version != SchemaVersion {
+		return nil, errUnreadable
+	}
+	if keyID == "" {
+		if keyring != nil && !acceptPlaintext {
+			return nil, errUnreadable
+		}
+		return payload, nil
+	}
+
+	if keyring == nil {
+		return nil, errUnreadable
+	}
+	aead, ok := keyring.aeads[keyID]
+	if !ok || len(payload) < aead.NonceSize() {
+		return nil, errUnreadable
+	}
+	nonce, ciphertext := payload[:aead.NonceSize()], payload[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, additionalData(version, keyID))
+	if err != nil {
+		return nil, errUnreadable
+	}
+	return plain, nil
+}
+
+// additionalData binds the envelope header to the ciphertext, so that neither
+// the version nor the key ID can be swapped without failing authentication.
+func additionalData(version uint64, keyID string) []byte {
+	b := protowire.AppendVarint(nil, version)
+	return append(b, keyID...)
+}
+
+// consumeFields calls field for every field of a protobuf message. field
+// consumes the value and returns its length, or a negative value on error.
+func consumeFields(b []byte, field func(num protowire.Number, typ protowire.Type, b []byte) int) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		n = field(num, typ, b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// User record fields.
+const (
+	userID          protowire.Number = 1
+	userUsername    protowire.Number = 2
+	userName        protowire.Number = 3
+	userGivenName   protowire.Number = 4
+	userFamilyName  protowire.Number = 5
+	userDisplayName protowire.Number = 6
+	userEmail       protowire.Number = 7
+	userPhone       protowire.Number = 8
+	userLocale      protowire.Number = 9
+	userTimezone    protowire.Number = 10
+)
+
+// appendUser appends the record of a user.
+func appendUser(b []byte, u *domain.User) []byte {
+	b = protowire.AppendTag(b, userID, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(u.ID))
+	for _, f := range []struct {
+		num   protowire.Number
+		value string
+	}{
+		{userUsername, u.Username},
+		{userName, u.Name},
+		{userGivenName, u.GivenName},
+		{userFamilyName, u.FamilyName},
+		{userDisplayName, u.DisplayName},
+		{userEmail, u.Email},
+		{userPhone, u.Phone},
+		{userLocale, u.Locale},
+		{userTimezone, u.Timezone},
+	} {
+		if f.value != "" {
+			b = protowire.AppendTag(b, f.num, protowire.BytesType)
+			b = protowire.AppendString(b, f.value)
+		}
+	}
+	return b
+}
+
+// consumeUser decodes the record of a user.
+func consumeUser(b []byte) (*domain.User, error) {
+	var u domain.User
+	fields := map[protowire.Number]*string{
+		userUsername:    &u.Username,
+		userName:        &u.Name,
+		userGivenName:   &u.GivenName,
+		userFamilyName:  &u.FamilyName,
+		userDisplayName: &u.DisplayName,
+		userEmail:       &u.Email,
+		userPhone:       &u.Phone,
+		userLocale:      &u.Locale,
+		userTimezone:    &u.Timezone,
+	}
+
+	err := consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+		if num == userID && typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(b)
+			u.ID = int64(v)
+			return n
+		}
+		if dst, ok := fields[num]; ok && typ == protowire.BytesType {
+			v, n := protowire.ConsumeString(b)
+			*dst = v
+			return n
+		}
+		return protowire.ConsumeFieldValue(num, typ, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Cached user record fields.
+const (
+	entryUser       protowire.Number = 1
+	entryFreshUntil protowire.Number = 2 // Unix milliseconds
+)
+
+// cachedUser is a cached user with its freshness.
+type cachedUser struct {
+	User       *domain.User
+	FreshUntil int64 // Unix milliseconds
+}
+
+// marshal encodes the record of a cached user.
+func (e cachedUser) marshal() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, entryUser, protowire.BytesType)
+	b = protowire.AppendBytes(b, appendUser(nil, e.User))
+	b = protowire.AppendTag(b, entryFreshUntil, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(e.FreshUntil))
+	return b
+}
+
+// unmarshalCachedUser decodes the record of a cached user.
+func unmarshalCachedUser(b []byte) (cachedUser, error) {
+	var e cachedUser
+	var userErr error
+
+	err := consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+		switch {
+		case num == entryUser && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n >= 0 {
+				e.User, userErr = consumeUser(v)
+			}
+			return n
+		case num == entryFreshUntil && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			e.FreshUntil = int64(v)
+			return n
+		}
+		return protowire.ConsumeFieldValue(num, typ, b)
+	})
+	if err = errors.Join(err, userErr); err != nil {
+		return cachedUser{}, err
+	}
+	if e.User == nil {
+		return cachedUser{}, fmt.Errorf("cached entry has no user")
+	}
+	return e, nil
+}
+
+// User list record fields.
+const (
+	listUser  protowire.Number = 1 // Repeated
+	listTotal protowire.Number = 2
+)
+
+// marshalList encodes the record of a list page.
+func marshalList(list *UserList) []byte {
+	var b []byte
+	for i := range list.Users {
+		b = protowire.AppendTag(b, listUser, protowire.BytesType)
+		b = protowire.AppendBytes(b, appendUser(nil, &list.Users[i]))
+	}
+	b = protowire.AppendTag(b, listTotal, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(list.Total))
+	return b
+}
+
+// unmarshalList decodes the record of a list page.
+func unmarshalList(b []byte) (*UserList, error) {
+	list := &UserList{Users: []domain.User{}}
+	var userErr error
+
+	err := consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+		switch {
+		case num == listUser && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n >= 0 {
+				u, err := consumeUser(v)
+				if err != nil {
+					userErr = err
+				} else {
+					list.Users = append(list.Users, *u)
+				}
+			}
+			return n
+		case num == listTotal && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			list.Total = int64(v)
+			return n
+		}
+		return protowire.ConsumeFieldValue(num, typ, b)
+	})
+	if err = errors.Join(err, userErr); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	domain "grpc-user-service/internal/domain/user"
+)
+
+// testKeyring creates a Keyring from IDs, deriving a fixed 32-byte secret from each
+func testKeyring(t *testing.T, ids ...string) *Keyring {
+	keys := make([]EncryptionKey, len(ids))
+	for i, id := range ids {
+		keys[i] = EncryptionKey{ID: id, Secret: bytes.Repeat([]byte(id[:1]), 32)}
+	}
+	k, err := NewKeyring(keys...)
+	require.NoError(t, err)
+	return k
+}
+
+func testUser() *domain.User {
+	return &domain.User{
+		ID:          42,
+		Username:    "johndoe",
+		Name:        "John Doe",
+		GivenName:   "John",
+		FamilyName:  "Doe",
+		DisplayName: "JD",
+		Email:       "john@example.com",
+		Phone:       "+84901234567",
+		Locale:      "vi-VN",
+		Timezone:    "Asia/Ho_Chi_Minh",
+	}
+}
+
+func TestCodec_CachedUser_RoundTrip(t *testing.T) {
+	entry := cachedUser{User: testUser(), FreshUntil: 1700000000000}
+
+	data, err := seal(nil, entry.marshal())
+	require.NoError(t, err)
+
+	payload, err := open(nil, false, data)
+	require.NoError(t, err)
+	got, err := unmarshalCachedUser(payload)
+	require.NoError(t, err)
+	assert.Equal(t, entry, got)
+}
+
+func TestCodec_List_RoundTrip(t *testing.T) {
+	list := &UserList{Users: []domain.User{*testUser(), {ID: 7, Name: "Jane"}}, Total: 12}
+
+	got, err := unmarshalList(marshalList(list))
+	require.NoError(t, err)
+	assert.Equal(t, list, got)
+}
+
+func TestCodec_SkipsUnknownFields(t *testing.T) {
+	// A record written by a newer release with an extra field
+	b := appendUser(nil, testUser())
+	b = protowire.AppendTag(b, 99, protowire.BytesType)
+	b = protowire.AppendString(b, "new field")
+
+	got, err := consumeUser(b)
+	require.NoError(t, err)
+	assert.Equal(t, testUser(), got)
+}
+
+func TestCodec_UnknownVersionIsUnreadable(t *testing.T) {
+	var b []byte
+	b = protowire.AppendTag(b, envelopeVersion, protowire.VarintType)
+	b = protowire.AppendVarint(b, SchemaVersion+1)
+	b = protowire.AppendTag(b, envelopePayload, protowire.BytesType)
+	b = protowire.AppendBytes(b, cachedUser{User: testUser()}.marshal())
+
+	_, err := open(nil, false, b)
+	assert.ErrorIs(t, err, errUnreadable)
+}
+
+func TestCodec_Encryption(t *testing.T) {
+	keyring := testKeyring(t, "k1")
+	payload := appendUser(nil, testUser())
+
+	data, err := seal(keyring, payload)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "john@example.com")
+
+	got, err := open(keyring, false, data)
+	require.NoError(t, err)
+	assert.Equal(t, payload, got)
+
+	// Without the key the entry cannot be read
+	_, err = open(nil, false, data)
+	assert.ErrorIs(t, err, errUnreadable)
+	_, err = open(testKeyring(t, "k2"), false, data)
+	assert.ErrorIs(t, err, errUnreadable)
+
+	// Tampering is detected
+	data[len(data)-1] ^= 0xff
+	_, err = open(keyring, false, data)
+	assert.ErrorIs(t, err, errUnreadable)
+}
+
+func TestCodec_KeyRotation(t *testing.T) {
+	old := testKeyring(t, "k1")
+	data, err := seal(old, []byte("payload"))
+	require.NoError(t, err)
+
+	// The new key encrypts; the old one still decrypts existing entries
+	rotated := testKeyring(t, "k2", "k1")
+	got, err := open(rotated, false, data)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), got)
+
+	data, err = seal(rotated, []byte("payload"))
+	require.NoError(t, err)
+	_, err = open(old, false, data)
+	assert.ErrorIs(t, err, errUnreadable)
+
+}
+
+func TestCodec_PlaintextWithEncryption(t *testing.T) {
+	keyring := testKeyring(t, "k1")
+	data, err := seal(nil, []byte("payload"))
+	require.NoError(t, err)
+
+	// A plaintext entry could have been forged by anyone able to write to the cache
+	_, err = open(keyring, false, data)
+	assert.ErrorIs(t, err, errUnreadable)
+
+	// Unless plaintext is explicitly accepted while encryption is rolled out
+	got, err := open(keyring, true, data)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), got)
+}
+
+func TestNewKeyring_Invalid(t *testing.T) {
+	_, err := NewKeyring()
+	assert.Error(t, err)
+
+	_, err = NewKeyring(EncryptionKey{ID: "k1", Secret: []byte("short")})
+	assert.Error(t, err)
+
+	_, err = NewKeyring(EncryptionKey{ID: "", Secret: make([]byte, 32)})
+	assert.Error(t, err)
+
+	_, err = NewKeyring(EncryptionKey{ID: "k1", Secret: make([]byte, 32)}, EncryptionKey{ID: "k1", Secret: make([]byte, 16)})
+	assert.Error(t, err)
+}
+
+func TestRedisUserCache_Encryption(t *testing.T) {
+	client, mr := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, time.Minute, logger, WithEncryption(testKeyring(t, "k1")))
+	ctx := context.Background()
+
+	require.NoError(t, cache.Set(ctx, testUser()))
+	require.NoError(t, cache.SetList(ctx, 1, "", "", 1, 10, &UserList{Users: []domain.User{*testUser()}, Total: 1}))
+
+	for _, key := range mr.Keys() {
+		value, err := mr.Get(key)
+		require.NoError(t, err)
+		assert.NotContains(t, value, "john@example.com", key)
+	}
+
+	cached, err := cache.Get(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, testUser(), cached)
+
+	list, err := cache.GetList(ctx, 1, "", "", 1, 10)
+	require.NoError(t, err)
+	require.NotNil(t, list)
+	assert.Equal(t, testUser(), &list.Users[0])
+}
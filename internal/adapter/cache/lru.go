@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lru is a size-bounded in-memory map that evicts the least recently used
+// entry when full. Entries also expire after a TTL, fixed unless given per
+// entry, which bounds how long a missed invalidation can serve stale data.
+// It is safe for concurrent use.
+type lru[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+	order    *list.List // Front is the most recently used entry
+	items    map[K]*list.Element
+}
+
+// lruEntry is an element of lru.order.
+type lruEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// newLRU creates an lru holding at most capacity entries for ttl each.
+func newLRU[K comparable, V any](capacity int, ttl time.Duration) *lru[K, V] {
+	return &lru[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		order:    list.New(),
+		items:    make(map[K]*list.Element, capacity),
+	}
+}
+
+// get returns the value stored under key and marks it as recently used.
+func (c *lru[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := el.Value.(*lruEntry[K, V])
+	if !c.now().Before(e.expires) {
+		c.removeElement(el)
+		return zero, false
+	}
+
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+// add stores value under key, evicting the least recently used entry if the
+// cache is full.
+func (c *lru[K, V]) add(key K, value V) {
+	c.addTTL(key, value, c.ttl)
+}
+
+// addTTL is add with a TTL other than the default one.
+func (c *lru[K, V]) addTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.store(key, value, ttl)
+}
+
+// addIfAbsent is addTTL that leaves an unexpired entry under key untouched.
+// It reports whether value was stored.
+func (c *lru[K, V]) addIfAbsent(key K, value V, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok && c.now().Before(el.Value.(*lruEntry[K, V]).expires) {
+		return false
+	}
+	c.store(key, value, ttl)
+	return true
+}
+
+// store sets the entry under key; c.mu must be held.
+func (c *lru[K, V]) store(key K, value V, ttl time.Duration) {
+	expires := c.now().Add(ttl)
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*lruEntry[K, V])
+		e.value = value
+		e.expires = expires
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, expires: expires})
+	if c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// remove deletes the given keys.
+func (c *lru[K, V]) remove(keys ...K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.removeElement(el)
+		}
+	}
+}
+
+// peek returns the value stored under key and when it expires, without
+// marking it as recently used.
+func (c *lru[K, V]) peek(key K) (V, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, time.Time{}, false
+	}
+
+	e := el.Value.(*lruEntry[K, V])
+	if !c.now().Before(e.expires) {
+		c.removeElement(el)
+		return zero, time.Time{}, false
+	}
+	return e.value, e.expires, true
+}
+
+// removeIf deletes the entries whose key satisfies match and returns how many
+// were deleted.
+func (c *lru[K, V]) removeIf(match func(K) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, el := range c.items {
+		if match(key) {
+			c.removeElement(el)
+			removed++
+		}
+	}
+	return removed
+}
+
+// purge deletes every entry.
+func (c *lru[K, V]) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.items)
+}
+
+// len returns the number of entries, including expired ones not yet evicted.
+func (c *lru[K, V]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// removeElement unlinks an element. The caller must hold c.mu.
+func (c *lru[K, V]) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*lruEntry[K, V]).key)
+}
@@ -0,0 +1,228 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	domain "grpc-user-service/internal/domain/user"
+)
+
+// memoryUser is a user held by MemoryUserCache; missing marks a tombstone.
+type memoryUser struct {
+	user       domain.User
+	missing    bool
+	freshUntil time.Time
+}
+
+// listKey identifies a cached list page.
+type listKey struct {
+	generation  int64
+	query       string
+	locale      string
+	page, limit int64
+}
+
+// MemoryUserCache implements UserCache in process memory, for running without
+// Redis. Each kind of entry is held in its own LRU of at most maxEntries,
+// with the same TTLs as in Redis. Nothing is shared between replicas, so a
+// write on one replica does not invalidate the others: it is meant for local
+// development and single-instance deployments.
+//
+// Entries are stored as copies, and never encrypted since they do not leave
+// the process.
+type MemoryUserCache struct {
+	options
+	users         *lru[int64, memoryUser]
+	lastKnownGood *lru[int64, domain.User]
+	usernames     *lru[string, int64]
+	emails        *lru[string, int64]
+	lists         *lru[listKey, UserList]
+	generation    atomic.Int64
+	log           *zap.Logger
+}
+
+// NewMemoryUserCache creates an in-memory user cache holding at most
+// maxEntries entries of each kind.
+func NewMemoryUserCache(maxEntries int, ttl time.Duration, log *zap.Logger, opts ...Option) *MemoryUserCache {
+	c := &MemoryUserCache{
+		options:       newOptions(ttl, opts),
+		users:         newLRU[int64, memoryUser](maxEntries, ttl),
+		lastKnownGood: newLRU[int64, domain.User](maxEntries, 0),
+		usernames:     newLRU[string, int64](maxEntries, ttl),
+		emails:        newLRU[string, int64](maxEntries, ttl),
+		lists:         newLRU[listKey, UserList](maxEntries, ttl),
+		log:           log,
+	}
+
+	// Expire entries on the cache's clock, so that tests can move it
+	now := func() time.Time { return c.now() }
+	c.users.now = now
+	c.lastKnownGood.now = now
+	c.usernames.now = now
+	c.emails.now = now
+	c.lists.now = now
+
+	return c
+}
+
+// Get retrieves a user from memory.
+func (c *MemoryUserCache) Get(ctx context.Context, id int64) (*domain.User, error) {
+	user, _, err := c.Lookup(ctx, id)
+	return user, err
+}
+
+// Lookup retrieves a user from memory and reports whether it should be refreshed.
+func (c *MemoryUserCache) Lookup(_ context.Context, id int64) (*domain.User, bool, error) {
+	e, ok := c.users.get(id)
+	if !ok {
+		c.log.Debug("cache miss", zap.Int64("user_id", id))
+		return nil, false, nil
+	}
+	if e.missing {
+		c.log.Debug("cache negative hit", zap.Int64("user_id", id))
+		return nil, false, ErrMissing
+	}
+
+	refresh := c.shouldRefresh(e.freshUntil)
+	c.log.Debug("cache hit", zap.Int64("user_id", id), zap.Bool("refresh", refresh))
+	u := e.user
+	return &u, refresh, nil
+}
+
+// GetLastKnownGood retrieves the last known good copy of a user from memory.
+func (c *MemoryUserCache) GetLastKnownGood(_ context.Context, id int64) (*domain.User, error) {
+	u, ok := c.lastKnownGood.get(id)
+	if !ok {
+		return nil, nil
+	}
+	return &u, nil
+}
+
+// Set stores a user in memory with a jittered TTL. The entry outlives the
+// TTL by the stale window.
+func (c *MemoryUserCache) Set(_ context.Context, user *domain.User) error {
+	if user == nil {
+		return fmt.Errorf("cannot cache nil user")
+	}
+
+	ttl := c.jitteredTTL()
+	c.users.addTTL(user.ID, memoryUser{user: *user, freshUntil: c.now().Add(ttl)}, ttl+c.staleWindow)
+	if c.lastKnownGoodTTL > 0 {
+		c.lastKnownGood.addTTL(user.ID, *user, c.lastKnownGoodTTL)
+	}
+
+	c.log.Debug("cached user", zap.Int64("user_id", user.ID), zap.Duration("ttl", ttl))
+	return nil
+}
+
+// SetMissing stores a tombstone for a missing user ID in memory with the
+// negative TTL.
+func (c *MemoryUserCache) SetMissing(_ context.Context, id int64) error {
+	c.users.addTTL(id, memoryUser{missing: true}, c.negativeTTL)
+	c.lastKnownGood.remove(id)
+
+	c.log.Debug("cached missing user", zap.Int64("user_id", id), zap.Duration("ttl", c.negativeTTL))
+	return nil
+}
+
+// Delete removes a user from memory.
+func (c *MemoryUserCache) Delete(ctx context.Context, id int64) error {
+	return c.DeleteMultiple(ctx, id)
+}
+
+// DeleteMultiple removes multiple users from memory.
+func (c *MemoryUserCache) DeleteMultiple(_ context.Context, ids ...int64) error {
+	c.users.remove(ids...)
+	c.lastKnownGood.remove(ids...)
+	return nil
+}
+
+// GetIDByUsername retrieves the user ID mapped to a username from memory.
+func (c *MemoryUserCache) GetIDByUsername(_ context.Context, username string) (int64, error) {
+	id, _ := c.usernames.get(username)
+	return id, nil
+}
+
+// SetUsername stores a username to user ID mapping in memory with TTL.
+func (c *MemoryUserCache) SetUsername(_ context.Context, username string, id int64) error {
+	if username == "" {
+		return fmt.Errorf("cannot cache empty username")
+	}
+
+	c.usernames.add(username, id)
+	return nil
+}
+
+// DeleteUsername removes a username mapping from memory.
+func (c *MemoryUserCache) DeleteUsername(_ context.Context, username string) error {
+	c.usernames.remove(username)
+	return nil
+}
+
+// Generation returns the current users generation; 0 if it was never bumped.
+func (c *MemoryUserCache) Generation(context.Context) (int64, error) {
+	return c.generation.Load(), nil
+}
+
+// BumpGeneration increments the users generation.
+func (c *MemoryUserCache) BumpGeneration(context.Context) error {
+	generation := c.generation.Add(1)
+
+	c.log.Debug("bumped users generation", zap.Int64("generation", generation))
+	return nil
+}
+
+// GetList retrieves a list page from memory.
+func (c *MemoryUserCache) GetList(_ context.Context, generation int64, query, locale string, page, limit int64) (*UserList, error) {
+	list, ok := c.lists.get(listKey{generation, query, locale, page, limit})
+	if !ok {
+		c.log.Debug("list cache miss", zap.Int64("generation", generation), zap.Int64("page", page), zap.Int64("limit", limit))
+		return nil, nil
+	}
+
+	list.Users = slices.Clone(list.Users)
+	return &list, nil
+}
+
+// SetList stores a list page in memory with TTL. Pages of older generations
+// are never read again and are evicted or expire.
+func (c *MemoryUserCache) SetList(_ context.Context, generation int64, query, locale string, page, limit int64, list *UserList) error {
+	if list == nil {
+		return fmt.Errorf("cannot cache nil list")
+	}
+
+	c.lists.add(listKey{generation, query, locale, page, limit}, UserList{Users: slices.Clone(list.Users), Total: list.Total})
+	return nil
+}
+
+// GetIDByEmail retrieves the user ID mapped to an email address from memory.
+func (c *MemoryUserCache) GetIDByEmail(_ context.Context, email string) (int64, bool, error) {
+	id, ok := c.emails.get(email)
+	return id, ok, nil
+}
+
+// SetEmail stores an email to user ID mapping in memory. Negative entries
+// (ID 0) use the negative TTL.
+func (c *MemoryUserCache) SetEmail(_ context.Context, email string, id int64) error {
+	if email == "" {
+		return fmt.Errorf("cannot cache empty email")
+	}
+
+	if id == 0 {
+		c.emails.addIfAbsent(email, id, c.negativeTTL)
+		return nil
+	}
+	c.emails.addTTL(email, id, c.ttl)
+	return nil
+}
+
+// DeleteEmails removes email mappings from memory.
+func (c *MemoryUserCache) DeleteEmails(_ context.Context, emails ...string) error {
+	c.emails.remove(emails...)
+	return nil
+}
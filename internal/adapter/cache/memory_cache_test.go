@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	domain "grpc-user-service/internal/domain/user"
+)
+
+func TestMemoryUserCache_Users(t *testing.T) {
+	var c UserCache = NewMemoryUserCache(100, time.Minute, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, testUser()))
+
+	cached, err := c.Get(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, testUser(), cached)
+
+	// Callers cannot modify the cached copy through the returned user
+	cached.Name = "Changed"
+	cached, err = c.Get(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", cached.Name)
+
+	require.NoError(t, c.Delete(ctx, 42))
+	cached, err = c.Get(ctx, 42)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	require.NoError(t, c.SetMissing(ctx, 7))
+	_, err = c.Get(ctx, 7)
+	assert.ErrorIs(t, err, ErrMissing)
+}
+
+func TestMemoryUserCache_Expiry(t *testing.T) {
+	c := NewMemoryUserCache(100, time.Minute, zaptest.NewLogger(t),
+		WithNegativeTTL(10*time.Second),
+		WithStaleWindow(time.Minute),
+		WithLastKnownGood(time.Hour),
+	)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, testUser()))
+	require.NoError(t, c.SetMissing(ctx, 7))
+	require.NoError(t, c.SetEmail(ctx, "nobody@example.com", 0))
+
+	now = now.Add(30 * time.Second)
+	_, err := c.Get(ctx, 7)
+	require.NoError(t, err, "tombstones use the negative TTL")
+	_, found, err := c.GetIDByEmail(ctx, "nobody@example.com")
+	require.NoError(t, err)
+	assert.False(t, found, "negative email entries use the negative TTL")
+
+	// Past the TTL, within the stale window
+	now = now.Add(time.Minute)
+	cached, refresh, err := c.Lookup(ctx, 42)
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.True(t, refresh)
+
+	// Past the stale window only the last known good copy is left
+	now = now.Add(time.Minute)
+	cached, err = c.Get(ctx, 42)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+	cached, err = c.GetLastKnownGood(ctx, 42)
+	require.NoError(t, err)
+	assert.Equal(t, testUser(), cached)
+}
+
+func TestMemoryUserCache_Lists(t *testing.T) {
+	c := NewMemoryUserCache(100, time.Minute, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	generation, err := c.Generation(ctx)
+	require.NoError(t, err)
+	list := &UserList{Users: []domain.User{*testUser()}, Total: 1}
+	require.NoError(t, c.SetList(ctx, generation, "john", "", 1, 10, list))
+
+	cached, err := c.GetList(ctx, generation, "john", "", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, list, cached)
+
+	cached, err = c.GetList(ctx, generation, "jane", "", 1, 10)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	require.NoError(t, c.BumpGeneration(ctx))
+	generation, err = c.Generation(ctx)
+	require.NoError(t, err)
+	cached, err = c.GetList(ctx, generation, "john", "", 1, 10)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func TestMemoryUserCache_BoundedSize(t *testing.T) {
+	c := NewMemoryUserCache(2, time.Minute, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	for id := int64(1); id <= 3; id++ {
+		require.NoError(t, c.Set(ctx, &domain.User{ID: id}))
+		require.NoError(t, c.SetUsername(ctx, fmt.Sprintf("user%d", id), id))
+	}
+
+	cached, err := c.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+	assert.Equal(t, 2, c.users.len())
+	assert.Equal(t, 2, c.usernames.len())
+
+	id, err := c.GetIDByUsername(ctx, "user3")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), id)
+}
+
+func TestMemoryUserCache_NegativeEmailKeepsMapping(t *testing.T) {
+	c := NewMemoryUserCache(100, time.Minute, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	require.NoError(t, c.SetEmail(ctx, "john@example.com", 7))
+	require.NoError(t, c.SetEmail(ctx, "john@example.com", 0))
+
+	id, found, err := c.GetIDByEmail(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(7), id)
+}
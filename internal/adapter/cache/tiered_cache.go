@@ -0,0 +1,224 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	domain "grpc-user-service/internal/domain/user"
+)
+
+// InvalidationChannel is the Redis pub/sub channel on which TieredUserCache
+// instances broadcast the IDs of users to evict from their local tier.
+const InvalidationChannel = "users:invalidate"
+
+// purgeMessage is published on InvalidationChannel to clear the local tier of
+// every replica instead of evicting IDs.
+const purgeMessage = "*"
+
+// subscribeTimeout bounds the wait for the invalidation subscription at startup.
+const subscribeTimeout = 5 * time.Second
+
+// localUser is a user held in the local tier; missing marks a tombstone.
+type localUser struct {
+	user    domain.User
+	missing bool
+}
+
+// TieredUserCache is a two-tier UserCache: a size-bounded in-process LRU in
+// front of a shared remote cache. Only users by ID are held locally; every
+// other operation goes straight to the remote cache.
+//
+// Delete and DeleteMultiple publish the evicted IDs on InvalidationChannel, so
+// that every replica drops its local copy after a write on any one of them;
+// EvictPattern and Flush clear the local tier of every replica.
+// Local entries also expire after a short TTL, which bounds staleness when an
+// invalidation is lost, and the local tier is cleared whenever the
+// subscription is re-established after a disconnect.
+type TieredUserCache struct {
+	UserCache
+	local  *lru[int64, localUser]
+	client *redis.Client
+	pubsub *redis.PubSub
+	log    *zap.Logger
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTieredUserCache creates a TieredUserCache in front of remote holding at
+// most maxEntries users for ttl each. It waits for the invalidation
+// subscription on client before returning; if Redis is unreachable it starts
+// anyway and subscribes once Redis is back. Call Close to stop listening.
+func NewTieredUserCache(remote UserCache, client *redis.Client, maxEntries int, ttl time.Duration, log *zap.Logger) *TieredUserCache {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pubsub := client.Subscribe(ctx, InvalidationChannel)
+	if _, err := pubsub.ReceiveTimeout(ctx, subscribeTimeout); err != nil {
+		log.Warn("cache invalidation subscription unavailable, will retry", zap.Error(err))
+	}
+
+	c := &TieredUserCache{
+		UserCache: remote,
+		local:     newLRU[int64, localUser](maxEntries, ttl),
+		client:    client,
+		pubsub:    pubsub,
+		log:       log,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	go c.listen(ctx)
+
+	return c
+}
+
+// Close stops listening for invalidations.
+func (c *TieredUserCache) Close() error {
+	c.cancel()
+	err := c.pubsub.Close()
+	<-c.done
+	return err
+}
+
+// Get retrieves a user from the local tier, falling back to the remote cache.
+func (c *TieredUserCache) Get(ctx context.Context, id int64) (*domain.User, error) {
+	u, _, err := c.Lookup(ctx, id)
+	return u, err
+}
+
+// Lookup retrieves a user from the local tier, falling back to the remote
+// cache. Local entries never ask for a refresh: their short TTL already
+// sends readers back to the remote cache, which decides.
+func (c *TieredUserCache) Lookup(ctx context.Context, id int64) (*domain.User, bool, error) {
+	if e, ok := c.local.get(id); ok {
+		if e.missing {
+			return nil, false, ErrMissing
+		}
+		c.log.Debug("local cache hit", zap.Int64("user_id", id))
+		u := e.user
+		return &u, false, nil
+	}
+
+	u, refresh, err := c.UserCache.Lookup(ctx, id)
+	switch {
+	case errors.Is(err, ErrMissing):
+		c.local.add(id, localUser{missing: true})
+	case err == nil && u != nil && !refresh:
+		c.local.add(id, localUser{user: *u})
+	}
+	return u, refresh, err
+}
+
+// Set stores a user in both tiers.
+func (c *TieredUserCache) Set(ctx context.Context, user *domain.User) error {
+	if err := c.UserCache.Set(ctx, user); err != nil {
+		return err
+	}
+
+	c.local.add(user.ID, localUser{user: *user})
+	return nil
+}
+
+// SetMissing stores a tombstone in both tiers.
+func (c *TieredUserCache) SetMissing(ctx context.Context, id int64) error {
+	if err := c.UserCache.SetMissing(ctx, id); err != nil {
+		return err
+	}
+
+	c.local.add(id, localUser{missing: true})
+	return nil
+}
+
+// Delete removes a user from both tiers and from the local tier of every
+// other replica.
+func (c *TieredUserCache) Delete(ctx context.Context, id int64) error {
+	return c.DeleteMultiple(ctx, id)
+}
+
+// DeleteMultiple removes users from both tiers and from the local tier of
+// every other replica. The local entries are evicted even if the remote delete
+// fails.
+func (c *TieredUserCache) DeleteMultiple(ctx context.Context, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := c.UserCache.DeleteMultiple(ctx, ids...)
+	c.local.remove(ids...)
+
+	if pubErr := c.client.Publish(ctx, InvalidationChannel, encodeIDs(ids)).Err(); pubErr != nil {
+		c.log.Error("failed to publish cache invalidation", zap.Int64s("user_ids", ids), zap.Error(pubErr))
+		return errors.Join(err, pubErr)
+	}
+	return err
+}
+
+// listen evicts the users named in invalidation messages until ctx is cancelled.
+func (c *TieredUserCache) listen(ctx context.Context) {
+	defer close(c.done)
+
+	for {
+		msg, err := c.pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.log.Warn("cache invalidation subscription failed, retrying", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			// Re-subscribed after a disconnect: invalidations may have been missed
+			if m.Kind == "subscribe" {
+				c.local.purge()
+				c.log.Info("cache invalidation subscription restored, local cache cleared")
+			}
+		case *redis.Message:
+			if m.Payload == purgeMessage {
+				c.local.purge()
+				c.log.Info("local cache cleared on request")
+				continue
+			}
+			ids, err := decodeIDs(m.Payload)
+			if err != nil {
+				c.log.Warn("invalid cache invalidation message", zap.String("payload", m.Payload), zap.Error(err))
+				continue
+			}
+			c.local.remove(ids...)
+			c.log.Debug("evicted users from local cache", zap.Int64s("user_ids", ids))
+		}
+	}
+}
+
+// encodeIDs formats user IDs as an invalidation message.
+func encodeIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// decodeIDs parses an invalidation message.
+func decodeIDs(payload string) ([]int64, error) {
+	parts := strings.Split(payload, ",")
+	ids := make([]int64, len(parts))
+	for i, part := range parts {
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	domain "grpc-user-service/internal/domain/user"
+)
+
+// newTestReplica creates a TieredUserCache with its own Redis connections,
+// like a separate replica of the service sharing the Redis at addr.
+func newTestReplica(t *testing.T, addr string) *TieredUserCache {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	logger := zaptest.NewLogger(t)
+
+	c := NewTieredUserCache(NewRedisUserCache(client, 5*time.Minute, logger), client, 100, time.Minute, logger)
+	t.Cleanup(func() {
+		_ = c.Close()
+		_ = client.Close()
+	})
+	return c
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newLRU[int64, string](2, time.Minute)
+
+	c.add(1, "one")
+	c.add(2, "two")
+	_, _ = c.get(1) // 2 is now the least recently used entry
+	c.add(3, "three")
+
+	_, ok := c.get(2)
+	assert.False(t, ok)
+	v, ok := c.get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "one", v)
+	assert.Equal(t, 2, c.len())
+}
+
+func TestLRU_Expires(t *testing.T) {
+	c := newLRU[int64, string](2, time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.add(1, "one")
+	now = now.Add(time.Minute)
+
+	_, ok := c.get(1)
+	assert.False(t, ok)
+	assert.Zero(t, c.len())
+}
+
+func TestTieredUserCache_ServesFromLocalTier(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := newTestReplica(t, mr.Addr())
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, &domain.User{ID: 1, Name: "John Doe"}))
+
+	// The local copy survives the loss of the remote entry
+	mr.Del("user:1")
+	cached, err := c.Get(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.Equal(t, "John Doe", cached.Name)
+
+	// Callers cannot modify the local copy through the returned user
+	cached.Name = "Changed"
+	cached, err = c.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", cached.Name)
+
+	require.NoError(t, c.SetMissing(ctx, 2))
+	_, err = c.Get(ctx, 2)
+	assert.ErrorIs(t, err, ErrMissing)
+}
+
+func TestTieredUserCache_InvalidatesOtherReplicas(t *testing.T) {
+	mr := miniredis.RunT(t)
+	a := newTestReplica(t, mr.Addr())
+	b := newTestReplica(t, mr.Addr())
+	ctx := context.Background()
+
+	require.NoError(t, a.Set(ctx, &domain.User{ID: 1, Name: "John Doe"}))
+
+	// Replica b fills its local tier from Redis
+	cached, err := b.Get(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	_, ok := b.local.get(1)
+	require.True(t, ok)
+
+	require.NoError(t, a.Delete(ctx, 1))
+
+	require.Eventually(t, func() bool {
+		_, ok := b.local.get(1)
+		return !ok
+	}, time.Second, 5*time.Millisecond)
+
+	cached, err = b.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func TestTieredUserCache_PassesThroughOtherOperations(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := newTestReplica(t, mr.Addr())
+	ctx := context.Background()
+
+	require.NoError(t, c.SetUsername(ctx, "johndoe", 42))
+	assert.True(t, mr.Exists("user:username:johndoe"))
+
+	id, err := c.GetIDByUsername(ctx, "johndoe")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+}
+
+func TestDecodeIDs(t *testing.T) {
+	ids, err := decodeIDs(encodeIDs([]int64{1, 22, 333}))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 22, 333}, ids)
+
+	_, err = decodeIDs("1,x")
+	assert.Error(t, err)
+}
+
+func TestTieredUserCache_ClearsLocalTierAfterReconnect(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := newTestReplica(t, mr.Addr())
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, &domain.User{ID: 1, Name: "John Doe"}))
+
+	// Invalidations published while the subscription is down are lost
+	mr.Close()
+	require.NoError(t, mr.Restart())
+
+	require.Eventually(t, func() bool {
+		return c.local.len() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTieredUserCache_StartsWithoutRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	addr := mr.Addr()
+	mr.Close()
+	c := newTestReplica(t, addr)
+	ctx := context.Background()
+
+	_, err := c.Get(ctx, 1)
+	assert.Error(t, err)
+
+	// Once Redis is back the subscription is established and invalidations flow
+	require.NoError(t, mr.Restart())
+	b := newTestReplica(t, addr)
+	require.NoError(t, c.Set(ctx, &domain.User{ID: 1, Name: "John Doe"}))
+	require.Eventually(t, func() bool {
+		return mr.PubSubNumSub(InvalidationChannel)[InvalidationChannel] == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, b.Delete(ctx, 1))
+	require.Eventually(t, func() bool {
+		_, ok := c.local.get(1)
+		return !ok
+	}, time.Second, 5*time.Millisecond)
+}
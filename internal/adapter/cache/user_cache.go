@@ -2,8 +2,12 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -12,96 +16,382 @@ import (
 	domain "grpc-user-service/internal/domain/user"
 )
 
+// ErrMissing is returned by UserCache.Get when the cache holds a tombstone for
+// the ID, meaning the user is known not to exist.
+var ErrMissing = errors.New("cache: user known to be missing")
+
 // UserCache defines the interface for user caching operations.
 type UserCache interface {
 	// Get retrieves a user from cache by ID.
-	// Returns nil if user is not found in cache.
+	// Returns nil if user is not found in cache, and ErrMissing if the cache
+	// holds a tombstone for the ID.
 	Get(ctx context.Context, id int64) (*domain.User, error)
 
-	// Set stores a user in cache with the configured TTL.
+	// Lookup is Get that also reports whether the caller should refresh the
+	// entry: it is past its fresh TTL but within the stale window, or it was
+	// picked for early refresh as it approaches expiry.
+	Lookup(ctx context.Context, id int64) (user *domain.User, refresh bool, err error)
+
+	// GetLastKnownGood retrieves the long-lived copy of a user kept for
+	// degraded mode. Returns nil if there is none.
+	GetLastKnownGood(ctx context.Context, id int64) (*domain.User, error)
+
+	// Set stores a user in cache with the configured TTL, and its last known
+	// good copy when enabled.
 	Set(ctx context.Context, user *domain.User) error
 
-	// Delete removes a user from cache by ID.
+	// SetMissing stores a tombstone for an ID that does not exist, with the
+	// negative TTL, and drops its last known good copy. Delete removes it like
+	// a cached user.
+	SetMissing(ctx context.Context, id int64) error
+
+	// Delete removes a user and its last known good copy from cache by ID.
 	Delete(ctx context.Context, id int64) error
 
-	// DeleteMultiple removes multiple users from cache by IDs.
+	// DeleteMultiple removes multiple users and their last known good copies
+	// from cache by IDs.
 	DeleteMultiple(ctx context.Context, ids ...int64) error
+
+	// GetIDByUsername retrieves the user ID cached for a username.
+	// Returns 0 if the username is not found in cache.
+	GetIDByUsername(ctx context.Context, username string) (int64, error)
+
+	// SetUsername stores the username to user ID mapping with the configured TTL.
+	SetUsername(ctx context.Context, username string, id int64) error
+
+	// DeleteUsername removes a username mapping from cache.
+	DeleteUsername(ctx context.Context, username string) error
+
+	// Generation returns the current users generation. Cached list pages are
+	// keyed by generation, so bumping it invalidates all of them at once.
+	Generation(ctx context.Context) (int64, error)
+
+	// BumpGeneration advances the users generation after a write.
+	BumpGeneration(ctx context.Context) error
+
+	// GetList retrieves a cached list page for the given generation.
+	// Returns nil if the page is not found in cache.
+	GetList(ctx context.Context, generation int64, query, locale string, page, limit int64) (*UserList, error)
+
+	// SetList stores a list page for the given generation with the configured TTL.
+	SetList(ctx context.Context, generation int64, query, locale string, page, limit int64, list *UserList) error
+
+	// GetIDByEmail retrieves the ID of the user owning an email address.
+	// found is false on a cache miss; an ID of 0 with found set is a negative
+	// entry, meaning the address is known not to belong to any user.
+	GetIDByEmail(ctx context.Context, email string) (id int64, found bool, err error)
+
+	// SetEmail stores the email to user ID mapping with the configured TTL.
+	// An ID of 0 stores a negative entry with the shorter negative TTL, but
+	// only if no mapping is cached, so that a lookup racing a write cannot
+	// replace the mapping the write just cached.
+	SetEmail(ctx context.Context, email string, id int64) error
+
+	// DeleteEmails removes email mappings, positive or negative, from cache.
+	DeleteEmails(ctx context.Context, emails ...string) error
+}
+
+// tombstone is the value stored under a user key for IDs that do not exist.
+// It can never be mistaken for a JSON-encoded user.
+const tombstone = "!missing"
+
+// DefaultNegativeTTL is the lifetime of negative entries unless configured otherwise.
+const DefaultNegativeTTL = 30 * time.Second
+
+// options holds the settings shared by the UserCache implementations.
+type options struct {
+	ttl              time.Duration
+	negativeTTL      time.Duration
+	jitter           float64
+	staleWindow      time.Duration
+	earlyRefresh     time.Duration
+	lastKnownGoodTTL time.Duration
+	keyring          *Keyring
+	acceptPlaintext  bool
+	now              func() time.Time
+	random           func() float64
+}
+
+// newOptions returns the settings for a cache with the given TTL.
+func newOptions(ttl time.Duration, opts []Option) options {
+	o := options{
+		ttl:         ttl,
+		negativeTTL: DefaultNegativeTTL,
+		now:         time.Now,
+		random:      rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
 }
 
+// Option configures a UserCache.
+type Option func(*options)
+
+// WithNegativeTTL sets the lifetime of negative entries. It is kept short
+// because a negative entry that outlives the write it missed hides that write.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(c *options) {
+		c.negativeTTL = ttl
+	}
+}
+
+// WithTTLJitter spreads the TTL of cached users by up to fraction of the TTL
+// (e.g. 0.1 for ±10%), so that entries written together do not expire together.
+func WithTTLJitter(fraction float64) Option {
+	return func(c *options) {
+		c.jitter = fraction
+	}
+}
+
+// WithStaleWindow keeps cached users for window past their TTL. During the
+// window Lookup still returns them, asking the caller to refresh.
+func WithStaleWindow(window time.Duration) Option {
+	return func(c *options) {
+		c.staleWindow = window
+	}
+}
+
+// WithEarlyRefresh makes Lookup ask for a refresh of fresh entries with a
+// probability that grows as they approach expiry (probabilistic early
+// expiration). At window before expiry the probability is about 37%, and it
+// falls off exponentially further away.
+func WithEarlyRefresh(window time.Duration) Option {
+	return func(c *options) {
+		c.earlyRefresh = window
+	}
+}
+
+// WithLastKnownGood keeps a copy of every cached user for ttl, well past its
+// regular TTL, for GetLastKnownGood to serve while the database is down.
+func WithLastKnownGood(ttl time.Duration) Option {
+	return func(c *options) {
+		c.lastKnownGoodTTL = ttl
+	}
+}
+
+// WithEncryption encrypts cached users and list pages with AES-GCM using the
+// keys of keyring. It only applies to RedisUserCache.
+func WithEncryption(keyring *Keyring) Option {
+	return func(c *options) {
+		c.keyring = keyring
+	}
+}
+
+// WithPlaintextReads keeps reading unencrypted entries once encryption is
+// enabled, so that it can be turned on without a cache flush. Such entries
+// are not authenticated: only enable it for the rollout, for at most the TTL
+// of the cache.
+func WithPlaintextReads() Option {
+	return func(c *options) {
+		c.acceptPlaintext = true
+	}
+}
+
+// UserList is a cached page of a user listing.
+type UserList struct {
+	Users []domain.User `json:"users"`
+	Total int64         `json:"total"`
+}
+
+// generationKey is the Redis key of the users generation counter.
+const generationKey = "users:generation"
+
 // RedisUserCache implements UserCache using Redis as the backing store.
 type RedisUserCache struct {
+	options
 	client *redis.Client
-	ttl    time.Duration
 	log    *zap.Logger
 }
 
 // NewRedisUserCache creates a new Redis-backed user cache.
-func NewRedisUserCache(client *redis.Client, ttl time.Duration, log *zap.Logger) UserCache {
+func NewRedisUserCache(client *redis.Client, ttl time.Duration, log *zap.Logger, opts ...Option) UserCache {
 	return &RedisUserCache{
-		client: client,
-		ttl:    ttl,
-		log:    log,
+		options: newOptions(ttl, opts),
+		client:  client,
+		log:     log,
 	}
 }
 
-// cacheKey generates a Redis key for a user ID.
-func (c *RedisUserCache) cacheKey(id int64) string {
+// userKey generates a Redis key for a user ID.
+func userKey(id int64) string {
 	return fmt.Sprintf("user:%d", id)
 }
 
+// lastKnownGoodKey generates a Redis key for the last known good copy of a user.
+func lastKnownGoodKey(id int64) string {
+	return fmt.Sprintf("user:lkg:%d", id)
+}
+
+// usernameKey generates a Redis key for a username to user ID mapping.
+func usernameKey(username string) string {
+	return fmt.Sprintf("user:username:%s", username)
+}
+
+// emailKey generates a Redis key for an email to user ID mapping. The address
+// is hashed so that it does not appear in Redis in plaintext.
+func emailKey(email string) string {
+	sum := sha256.Sum256([]byte(email))
+	return fmt.Sprintf("user:email:%x", sum[:16])
+}
+
+// listPageKey generates a Redis key for a list page. The query is hashed so
+// that arbitrary search input yields a bounded, printable key.
+func listPageKey(generation int64, query, locale string, page, limit int64) string {
+	sum := sha256.Sum256([]byte(locale + "\x00" + query))
+	return fmt.Sprintf("users:list:%d:%d:%d:%x", generation, page, limit, sum[:16])
+}
+
 // Get retrieves a user from Redis cache.
 func (c *RedisUserCache) Get(ctx context.Context, id int64) (*domain.User, error) {
-	key := c.cacheKey(id)
+	user, _, err := c.Lookup(ctx, id)
+	return user, err
+}
+
+// Lookup retrieves a user from Redis cache and reports whether it should be
+// refreshed.
+func (c *RedisUserCache) Lookup(ctx context.Context, id int64) (*domain.User, bool, error) {
+	key := userKey(id)
 
 	data, err := c.client.Get(ctx, key).Bytes()
 	if err == redis.Nil {
 		// Cache miss - not an error
 		c.log.Debug("cache miss", zap.Int64("user_id", id))
-		return nil, nil
+		return nil, false, nil
 	}
 	if err != nil {
 		c.log.Error("failed to get from cache", zap.Int64("user_id", id), zap.Error(err))
-		return nil, err
+		return nil, false, err
+	}
+	if string(data) == tombstone {
+		c.log.Debug("cache negative hit", zap.Int64("user_id", id))
+		return nil, false, ErrMissing
 	}
 
-	var user domain.User
-	if err := json.Unmarshal(data, &user); err != nil {
-		c.log.Error("failed to unmarshal cached user", zap.Int64("user_id", id), zap.Error(err))
-		return nil, err
+	entry, err := c.decodeUser(data)
+	if errors.Is(err, errUnreadable) {
+		c.log.Debug("cache miss, unreadable entry", zap.Int64("user_id", id))
+		return nil, false, nil
 	}
+	if err != nil {
+		c.log.Error("failed to decode cached user", zap.Int64("user_id", id), zap.Error(err))
+		return nil, false, err
+	}
+
+	refresh := c.shouldRefresh(time.UnixMilli(entry.FreshUntil))
+	c.log.Debug("cache hit", zap.Int64("user_id", id), zap.Bool("refresh", refresh))
+	return entry.User, refresh, nil
+}
+
+// decodeUser opens an envelope and decodes the cached user inside.
+func (c *RedisUserCache) decodeUser(data []byte) (cachedUser, error) {
+	payload, err := open(c.keyring, c.acceptPlaintext, data)
+	if err != nil {
+		return cachedUser{}, err
+	}
+	return unmarshalCachedUser(payload)
+}
 
-	c.log.Debug("cache hit", zap.Int64("user_id", id))
-	return &user, nil
+// shouldRefresh reports whether an entry fresh until freshUntil should be
+// refreshed now: always once stale, and with probability
+// exp(-(freshUntil-now)/earlyRefresh) before.
+func (c *options) shouldRefresh(freshUntil time.Time) bool {
+	now := c.now()
+	if !now.Before(freshUntil) {
+		return true
+	}
+	if c.earlyRefresh <= 0 {
+		return false
+	}
+	// 1-random is in (0, 1], keeping the logarithm finite
+	return now.Add(time.Duration(-float64(c.earlyRefresh) * math.Log(1-c.random()))).After(freshUntil)
 }
 
-// Set stores a user in Redis cache with TTL.
+// Set stores a user in Redis cache with a jittered TTL. The key outlives the
+// TTL by the stale window.
 func (c *RedisUserCache) Set(ctx context.Context, user *domain.User) error {
 	if user == nil {
 		return fmt.Errorf("cannot cache nil user")
 	}
 
-	key := c.cacheKey(user.ID)
+	key := userKey(user.ID)
+	ttl := c.jitteredTTL()
 
-	data, err := json.Marshal(user)
+	data, err := seal(c.keyring, cachedUser{User: user, FreshUntil: c.now().Add(ttl).UnixMilli()}.marshal())
 	if err != nil {
-		c.log.Error("failed to marshal user for cache", zap.Int64("user_id", user.ID), zap.Error(err))
+		c.log.Error("failed to encode user for cache", zap.Int64("user_id", user.ID), zap.Error(err))
 		return err
 	}
 
-	if err := c.client.Set(ctx, key, data, c.ttl).Err(); err != nil {
+	_, err = c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, data, ttl+c.staleWindow)
+		if c.lastKnownGoodTTL > 0 {
+			pipe.Set(ctx, lastKnownGoodKey(user.ID), data, c.lastKnownGoodTTL)
+		}
+		return nil
+	})
+	if err != nil {
 		c.log.Error("failed to set cache", zap.Int64("user_id", user.ID), zap.Error(err))
 		return err
 	}
 
-	c.log.Debug("cached user", zap.Int64("user_id", user.ID), zap.Duration("ttl", c.ttl))
+	c.log.Debug("cached user", zap.Int64("user_id", user.ID), zap.Duration("ttl", ttl))
+	return nil
+}
+
+// jitteredTTL returns the configured TTL spread uniformly by ±jitter.
+func (c *options) jitteredTTL() time.Duration {
+	if c.jitter <= 0 {
+		return c.ttl
+	}
+	return time.Duration(float64(c.ttl) * (1 + c.jitter*(2*c.random()-1)))
+}
+
+// GetLastKnownGood retrieves the last known good copy of a user from Redis cache.
+func (c *RedisUserCache) GetLastKnownGood(ctx context.Context, id int64) (*domain.User, error) {
+	data, err := c.client.Get(ctx, lastKnownGoodKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		c.log.Error("failed to get last known good user from cache", zap.Int64("user_id", id), zap.Error(err))
+		return nil, err
+	}
+
+	entry, err := c.decodeUser(data)
+	if errors.Is(err, errUnreadable) {
+		return nil, nil
+	}
+	if err != nil {
+		c.log.Error("failed to decode last known good user", zap.Int64("user_id", id), zap.Error(err))
+		return nil, err
+	}
+	return entry.User, nil
+}
+
+// SetMissing stores a tombstone for a missing user ID in Redis cache with the
+// negative TTL.
+func (c *RedisUserCache) SetMissing(ctx context.Context, id int64) error {
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, userKey(id), tombstone, c.negativeTTL)
+		pipe.Del(ctx, lastKnownGoodKey(id))
+		return nil
+	})
+	if err != nil {
+		c.log.Error("failed to set cache tombstone", zap.Int64("user_id", id), zap.Error(err))
+		return err
+	}
+
+	c.log.Debug("cached missing user", zap.Int64("user_id", id), zap.Duration("ttl", c.negativeTTL))
 	return nil
 }
 
 // Delete removes a user from Redis cache.
 func (c *RedisUserCache) Delete(ctx context.Context, id int64) error {
-	key := c.cacheKey(id)
+	key := userKey(id)
 
-	if err := c.client.Del(ctx, key).Err(); err != nil {
+	if err := c.client.Del(ctx, key, lastKnownGoodKey(id)).Err(); err != nil {
 		c.log.Error("failed to delete from cache", zap.Int64("user_id", id), zap.Error(err))
 		return err
 	}
@@ -116,9 +406,9 @@ func (c *RedisUserCache) DeleteMultiple(ctx context.Context, ids ...int64) error
 		return nil
 	}
 
-	keys := make([]string, len(ids))
-	for i, id := range ids {
-		keys[i] = c.cacheKey(id)
+	keys := make([]string, 0, 2*len(ids))
+	for _, id := range ids {
+		keys = append(keys, userKey(id), lastKnownGoodKey(id))
 	}
 
 	if err := c.client.Del(ctx, keys...).Err(); err != nil {
@@ -129,3 +419,194 @@ func (c *RedisUserCache) DeleteMultiple(ctx context.Context, ids ...int64) error
 	c.log.Debug("deleted multiple from cache", zap.Int("count", len(ids)))
 	return nil
 }
+
+// GetIDByUsername retrieves the user ID mapped to a username from Redis cache.
+func (c *RedisUserCache) GetIDByUsername(ctx context.Context, username string) (int64, error) {
+	data, err := c.client.Get(ctx, usernameKey(username)).Result()
+	if err == redis.Nil {
+		c.log.Debug("username cache miss", zap.String("username", username))
+		return 0, nil
+	}
+	if err != nil {
+		c.log.Error("failed to get username from cache", zap.String("username", username), zap.Error(err))
+		return 0, err
+	}
+
+	id, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		c.log.Error("failed to parse cached user id", zap.String("username", username), zap.Error(err))
+		return 0, err
+	}
+
+	c.log.Debug("username cache hit", zap.String("username", username), zap.Int64("user_id", id))
+	return id, nil
+}
+
+// SetUsername stores a username to user ID mapping in Redis cache with TTL.
+func (c *RedisUserCache) SetUsername(ctx context.Context, username string, id int64) error {
+	if username == "" {
+		return fmt.Errorf("cannot cache empty username")
+	}
+
+	if err := c.client.Set(ctx, usernameKey(username), id, c.ttl).Err(); err != nil {
+		c.log.Error("failed to set username cache", zap.String("username", username), zap.Int64("user_id", id), zap.Error(err))
+		return err
+	}
+
+	c.log.Debug("cached username", zap.String("username", username), zap.Int64("user_id", id), zap.Duration("ttl", c.ttl))
+	return nil
+}
+
+// DeleteUsername removes a username mapping from Redis cache.
+func (c *RedisUserCache) DeleteUsername(ctx context.Context, username string) error {
+	if err := c.client.Del(ctx, usernameKey(username)).Err(); err != nil {
+		c.log.Error("failed to delete username from cache", zap.String("username", username), zap.Error(err))
+		return err
+	}
+
+	c.log.Debug("deleted username from cache", zap.String("username", username))
+	return nil
+}
+
+// Generation returns the current users generation from Redis; 0 if it was never bumped.
+func (c *RedisUserCache) Generation(ctx context.Context) (int64, error) {
+	generation, err := c.client.Get(ctx, generationKey).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		c.log.Error("failed to get users generation", zap.Error(err))
+		return 0, err
+	}
+
+	return generation, nil
+}
+
+// BumpGeneration increments the users generation in Redis. The counter has no
+// TTL: losing it would reset it to a value that may still have pages cached.
+func (c *RedisUserCache) BumpGeneration(ctx context.Context) error {
+	generation, err := c.client.Incr(ctx, generationKey).Result()
+	if err != nil {
+		c.log.Error("failed to bump users generation", zap.Error(err))
+		return err
+	}
+
+	c.log.Debug("bumped users generation", zap.Int64("generation", generation))
+	return nil
+}
+
+// GetList retrieves a list page from Redis cache.
+func (c *RedisUserCache) GetList(ctx context.Context, generation int64, query, locale string, page, limit int64) (*UserList, error) {
+	data, err := c.client.Get(ctx, listPageKey(generation, query, locale, page, limit)).Bytes()
+	if err == redis.Nil {
+		c.log.Debug("list cache miss", zap.Int64("generation", generation), zap.Int64("page", page), zap.Int64("limit", limit))
+		return nil, nil
+	}
+	if err != nil {
+		c.log.Error("failed to get list from cache", zap.Int64("generation", generation), zap.Error(err))
+		return nil, err
+	}
+
+	payload, err := open(c.keyring, c.acceptPlaintext, data)
+	if err != nil {
+		c.log.Debug("list cache miss, unreadable entry", zap.Int64("generation", generation))
+		return nil, nil
+	}
+	list, err := unmarshalList(payload)
+	if err != nil {
+		c.log.Error("failed to decode cached list", zap.Int64("generation", generation), zap.Error(err))
+		return nil, err
+	}
+
+	c.log.Debug("list cache hit", zap.Int64("generation", generation), zap.Int64("page", page), zap.Int64("limit", limit))
+	return list, nil
+}
+
+// SetList stores a list page in Redis cache with TTL. Pages of older
+// generations are never read again and simply expire.
+func (c *RedisUserCache) SetList(ctx context.Context, generation int64, query, locale string, page, limit int64, list *UserList) error {
+	if list == nil {
+		return fmt.Errorf("cannot cache nil list")
+	}
+
+	data, err := seal(c.keyring, marshalList(list))
+	if err != nil {
+		c.log.Error("failed to encode list for cache", zap.Int64("generation", generation), zap.Error(err))
+		return err
+	}
+
+	if err := c.client.Set(ctx, listPageKey(generation, query, locale, page, limit), data, c.ttl).Err(); err != nil {
+		c.log.Error("failed to set list cache", zap.Int64("generation", generation), zap.Error(err))
+		return err
+	}
+
+	c.log.Debug("cached list", zap.Int64("generation", generation), zap.Int("count", len(list.Users)), zap.Duration("ttl", c.ttl))
+	return nil
+}
+
+// GetIDByEmail retrieves the user ID mapped to an email address from Redis cache.
+func (c *RedisUserCache) GetIDByEmail(ctx context.Context, email string) (int64, bool, error) {
+	data, err := c.client.Get(ctx, emailKey(email)).Result()
+	if err == redis.Nil {
+		c.log.Debug("email cache miss", zap.String("email", email))
+		return 0, false, nil
+	}
+	if err != nil {
+		c.log.Error("failed to get email from cache", zap.String("email", email), zap.Error(err))
+		return 0, false, err
+	}
+
+	id, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		c.log.Error("failed to parse cached user id", zap.String("email", email), zap.Error(err))
+		return 0, false, err
+	}
+
+	c.log.Debug("email cache hit", zap.String("email", email), zap.Int64("user_id", id))
+	return id, true, nil
+}
+
+// SetEmail stores an email to user ID mapping in Redis cache. Negative
+// entries (ID 0) use the negative TTL and are only written if the key is
+// absent.
+func (c *RedisUserCache) SetEmail(ctx context.Context, email string, id int64) error {
+	if email == "" {
+		return fmt.Errorf("cannot cache empty email")
+	}
+
+	ttl := c.ttl
+	var err error
+	if id == 0 {
+		ttl = c.negativeTTL
+		err = c.client.SetNX(ctx, emailKey(email), id, ttl).Err()
+	} else {
+		err = c.client.Set(ctx, emailKey(email), id, ttl).Err()
+	}
+	if err != nil {
+		c.log.Error("failed to set email cache", zap.String("email", email), zap.Int64("user_id", id), zap.Error(err))
+		return err
+	}
+
+	c.log.Debug("cached email", zap.String("email", email), zap.Int64("user_id", id), zap.Duration("ttl", ttl))
+	return nil
+}
+
+// DeleteEmails removes email mappings from Redis cache.
+func (c *RedisUserCache) DeleteEmails(ctx context.Context, emails ...string) error {
+	if len(emails) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(emails))
+	for i, email := range emails {
+		keys[i] = emailKey(email)
+	}
+
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		c.log.Error("failed to delete emails from cache", zap.Int("count", len(emails)), zap.Error(err))
+		return err
+	}
+
+	c.log.Debug("deleted emails from cache", zap.Int("count", len(emails)))
+	return nil
+}
@@ -47,13 +47,15 @@ func TestRedisUserCache_Set_Success(t *testing.T) {
 	data, err := client.Get(context.Background(), "user:1").Bytes()
 	require.NoError(t, err)
 
-	var cached domain.User
-	err = json.Unmarshal(data, &cached)
+	payload, err := open(nil, false, data)
+	require.NoError(t, err)
+	entry, err := unmarshalCachedUser(payload)
 	require.NoError(t, err)
 
-	assert.Equal(t, user.ID, cached.ID)
-	assert.Equal(t, user.Name, cached.Name)
-	assert.Equal(t, user.Email, cached.Email)
+	assert.Equal(t, user.ID, entry.User.ID)
+	assert.Equal(t, user.Name, entry.User.Name)
+	assert.Equal(t, user.Email, entry.User.Email)
+	assert.Positive(t, entry.FreshUntil)
 }
 
 func TestRedisUserCache_Set_NilUser(t *testing.T) {
@@ -201,3 +203,259 @@ func TestRedisUserCache_TTL(t *testing.T) {
 	require.NoError(t, err)
 	assert.Nil(t, cached)
 }
+
+func TestRedisUserCache_TTLJitter(t *testing.T) {
+	client, mr := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, 100*time.Second, logger, WithTTLJitter(0.1)).(*RedisUserCache)
+	ctx := context.Background()
+
+	cache.random = func() float64 { return 0 }
+	require.NoError(t, cache.Set(ctx, &domain.User{ID: 1}))
+	assert.Equal(t, 90*time.Second, mr.TTL("user:1"))
+
+	cache.random = func() float64 { return 0.75 }
+	require.NoError(t, cache.Set(ctx, &domain.User{ID: 2}))
+	assert.Equal(t, 105*time.Second, mr.TTL("user:2"))
+}
+
+func TestRedisUserCache_Lookup_StaleWindow(t *testing.T) {
+	client, mr := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, time.Minute, logger, WithStaleWindow(time.Minute)).(*RedisUserCache)
+	ctx := context.Background()
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	require.NoError(t, cache.Set(ctx, &domain.User{ID: 1, Name: "John Doe"}))
+	assert.Equal(t, 2*time.Minute, mr.TTL("user:1"))
+
+	cached, refresh, err := cache.Lookup(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.False(t, refresh)
+
+	// Past the TTL the stale entry is still served, asking for a refresh
+	now = now.Add(90 * time.Second)
+	cached, refresh, err = cache.Lookup(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.Equal(t, "John Doe", cached.Name)
+	assert.True(t, refresh)
+}
+
+func TestRedisUserCache_Lookup_EarlyRefresh(t *testing.T) {
+	client, _ := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, time.Minute, logger, WithEarlyRefresh(10*time.Second)).(*RedisUserCache)
+	ctx := context.Background()
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	require.NoError(t, cache.Set(ctx, &domain.User{ID: 1}))
+
+	// 10s before expiry: refreshed when -10s*ln(1-random) exceeds 10s
+	now = now.Add(50 * time.Second)
+
+	cache.random = func() float64 { return 0.5 } // ~7s
+	_, refresh, err := cache.Lookup(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, refresh)
+
+	cache.random = func() float64 { return 0.9 } // ~23s
+	_, refresh, err = cache.Lookup(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, refresh)
+}
+
+func TestRedisUserCache_Get_LegacyJSONEntry(t *testing.T) {
+	client, mr := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, time.Minute, logger)
+
+	// Entries written before the versioned envelope are misses
+	data, err := json.Marshal(domain.User{ID: 1, Name: "John Doe"})
+	require.NoError(t, err)
+	require.NoError(t, mr.Set("user:1", string(data)))
+
+	cached, err := cache.Get(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func TestRedisUserCache_LastKnownGood(t *testing.T) {
+	client, mr := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, time.Minute, logger, WithLastKnownGood(24*time.Hour))
+	ctx := context.Background()
+
+	require.NoError(t, cache.Set(ctx, &domain.User{ID: 1, Name: "John Doe"}))
+	assert.Equal(t, 24*time.Hour, mr.TTL("user:lkg:1"))
+
+	// The copy outlives the regular entry
+	mr.FastForward(time.Hour)
+	cached, err := cache.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	cached, err = cache.GetLastKnownGood(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.Equal(t, "John Doe", cached.Name)
+
+	// Deleting the user drops the copy
+	require.NoError(t, cache.Delete(ctx, 1))
+	cached, err = cache.GetLastKnownGood(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func TestRedisUserCache_Username_RoundTrip(t *testing.T) {
+	client, mr := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, 5*time.Minute, logger)
+	ctx := context.Background()
+
+	// Miss before anything is cached
+	id, err := cache.GetIDByUsername(ctx, "johndoe")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), id)
+
+	require.NoError(t, cache.SetUsername(ctx, "johndoe", 42))
+	assert.True(t, mr.Exists("user:username:johndoe"))
+	assert.Equal(t, 5*time.Minute, mr.TTL("user:username:johndoe"))
+
+	id, err = cache.GetIDByUsername(ctx, "johndoe")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+
+	require.NoError(t, cache.DeleteUsername(ctx, "johndoe"))
+	assert.False(t, mr.Exists("user:username:johndoe"))
+}
+
+func TestRedisUserCache_SetUsername_Empty(t *testing.T) {
+	client, _ := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, 5*time.Minute, logger)
+
+	err := cache.SetUsername(context.Background(), "", 1)
+	assert.Error(t, err)
+}
+
+func TestRedisUserCache_SetMissing(t *testing.T) {
+	client, mr := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, 5*time.Minute, logger, WithNegativeTTL(10*time.Second))
+	ctx := context.Background()
+
+	require.NoError(t, cache.SetMissing(ctx, 99))
+	assert.Equal(t, 10*time.Second, mr.TTL("user:99"))
+
+	cached, err := cache.Get(ctx, 99)
+	assert.ErrorIs(t, err, ErrMissing)
+	assert.Nil(t, cached)
+
+	// Delete clears the tombstone
+	require.NoError(t, cache.Delete(ctx, 99))
+	cached, err = cache.Get(ctx, 99)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func TestRedisUserCache_Email_RoundTrip(t *testing.T) {
+	client, mr := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, 5*time.Minute, logger, WithNegativeTTL(10*time.Second)).(*RedisUserCache)
+	ctx := context.Background()
+	johnKey, freeKey := emailKey("john@example.com"), emailKey("free@example.com")
+	assert.NotContains(t, johnKey, "john@example.com")
+
+	// Miss before anything is cached
+	_, found, err := cache.GetIDByEmail(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, cache.SetEmail(ctx, "john@example.com", 42))
+	assert.Equal(t, 5*time.Minute, mr.TTL(johnKey))
+
+	id, found, err := cache.GetIDByEmail(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(42), id)
+
+	// Negative entries use the shorter TTL
+	require.NoError(t, cache.SetEmail(ctx, "free@example.com", 0))
+	assert.Equal(t, 10*time.Second, mr.TTL(freeKey))
+
+	id, found, err = cache.GetIDByEmail(ctx, "free@example.com")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Zero(t, id)
+
+	// A negative entry never replaces a cached mapping
+	require.NoError(t, cache.SetEmail(ctx, "john@example.com", 0))
+	id, found, err = cache.GetIDByEmail(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(42), id)
+
+	require.NoError(t, cache.DeleteEmails(ctx, "john@example.com", "free@example.com"))
+	assert.False(t, mr.Exists(johnKey))
+	assert.False(t, mr.Exists(freeKey))
+
+	assert.Error(t, cache.SetEmail(ctx, "", 1))
+}
+
+func TestRedisUserCache_Generation(t *testing.T) {
+	client, _ := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, 5*time.Minute, logger)
+	ctx := context.Background()
+
+	generation, err := cache.Generation(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), generation)
+
+	require.NoError(t, cache.BumpGeneration(ctx))
+	require.NoError(t, cache.BumpGeneration(ctx))
+
+	generation, err = cache.Generation(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), generation)
+}
+
+func TestRedisUserCache_List_RoundTrip(t *testing.T) {
+	client, _ := setupTestRedis(t)
+	logger := zaptest.NewLogger(t)
+	cache := NewRedisUserCache(client, 5*time.Minute, logger)
+	ctx := context.Background()
+
+	list := &UserList{
+		Users: []domain.User{{ID: 1, Name: "John Doe", Email: "john@example.com"}},
+		Total: 11,
+	}
+	require.NoError(t, cache.SetList(ctx, 3, "john", "", 2, 10, list))
+
+	cached, err := cache.GetList(ctx, 3, "john", "", 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, list, cached)
+
+	// Any other generation, query, locale, page or limit is a miss
+	for _, miss := range []struct {
+		generation  int64
+		query       string
+		locale      string
+		page, limit int64
+	}{
+		{4, "john", "", 2, 10},
+		{3, "jane", "", 2, 10},
+		{3, "john", "sv", 2, 10},
+		{3, "john", "", 1, 10},
+		{3, "john", "", 2, 20},
+	} {
+		cached, err := cache.GetList(ctx, miss.generation, miss.query, miss.locale, miss.page, miss.limit)
+		require.NoError(t, err)
+		assert.Nil(t, cached)
+	}
+}
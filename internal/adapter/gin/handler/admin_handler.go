@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"grpc-user-service/internal/adapter/cache"
+	domain "grpc-user-service/internal/domain/user"
+	"grpc-user-service/pkg/scheduler"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// JobStatusLister reports the status of background jobs
+type JobStatusLister interface {
+	Status() []scheduler.JobStatus
+}
+
+// CacheAdmin inspects and evicts entries of the user cache
+type CacheAdmin interface {
+	cache.Admin
+	Delete(ctx context.Context, id int64) error
+}
+
+// AuditRecorder records audit entries
+type AuditRecorder interface {
+	Record(ctx context.Context, entry *domain.AuditEntry) error
+}
+
+// AdminHandler handles HTTP requests for operational endpoints
+type AdminHandler struct {
+	jobs  JobStatusLister
+	cache CacheAdmin    // nil when the cache is disabled
+	audit AuditRecorder // Records every cache operation
+	log   *zap.Logger
+}
+
+// NewAdminHandler creates a new AdminHandler instance. cacheAdmin may be nil
+// when the user cache is disabled.
+func NewAdminHandler(jobs JobStatusLister, cacheAdmin CacheAdmin, audit AuditRecorder, log *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		jobs:  jobs,
+		cache: cacheAdmin,
+		audit: audit,
+		log:   log,
+	}
+}
+
+// JobStatusResponse represents the status of a background job on this instance
+type JobStatusResponse struct {
+	Name             string     `json:"name"`
+	Schedule         string     `json:"schedule"`
+	NextRun          *time.Time `json:"next_run,omitempty"`
+	Running          bool       `json:"running"`
+	LastRun          *time.Time `json:"last_run,omitempty"`
+	LastOutcome      string     `json:"last_outcome,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+	LastProcessed    int64      `json:"last_processed"`
+	LastDurationMs   int64      `json:"last_duration_ms"`
+	LastAttempts     int        `json:"last_attempts"`
+	LastFencingToken int64      `json:"last_fencing_token,omitempty"`
+	Runs             int64      `json:"runs"`
+	Failures         int64      `json:"failures"`
+	Skips            int64      `json:"skips"`
+}
+
+// ListJobsResponse represents the HTTP response listing background jobs
+type ListJobsResponse struct {
+	Jobs []JobStatusResponse `json:"jobs"`
+}
+
+// ListJobs handles GET /admin/jobs
+func (h *AdminHandler) ListJobs(c *gin.Context) {
+	statuses := h.jobs.Status()
+
+	jobs := make([]JobStatusResponse, len(statuses))
+	for i, s := range statuses {
+		jobs[i] = JobStatusResponse{
+			Name:             s.Name,
+			Schedule:         s.Schedule,
+			NextRun:          optionalTime(s.NextRun),
+			Running:          s.Running,
+			LastRun:          optionalTime(s.LastRun),
+			LastOutcome:      s.LastOutcome,
+			LastError:        s.LastError,
+			LastProcessed:    s.LastProcessed,
+			LastDurationMs:   s.LastDuration.Milliseconds(),
+			LastAttempts:     s.LastAttempts,
+			LastFencingToken: s.LastFencingToken,
+			Runs:             s.Runs,
+			Failures:         s.Failures,
+			Skips:            s.Skips,
+		}
+	}
+
+	c.JSON(http.StatusOK, ListJobsResponse{Jobs: jobs})
+}
+
+// optionalTime returns nil for the zero time so that it is omitted from JSON
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// CachedUserResponse represents a user cache entry
+type CachedUserResponse struct {
+	User          *UserResponse `json:"user,omitempty"`
+	Missing       bool          `json:"missing"`
+	FreshUntil    *time.Time    `json:"fresh_until,omitempty"`
+	ExpiresInMs   int64         `json:"expires_in_ms"`
+	LastKnownGood bool          `json:"last_known_good"`
+	Local         bool          `json:"local"`
+}
+
+// maxPatternLength bounds eviction patterns, which are kept in audit details
+const maxPatternLength = 200
+
+// EvictCacheResponse represents the result of a cache eviction
+type EvictCacheResponse struct {
+	Evicted int64 `json:"evicted"`
+}
+
+// InspectCachedUser handles GET /admin/cache/users/:id
+func (h *AdminHandler) InspectCachedUser(c *gin.Context) {
+	id, ok := h.cacheUserID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.cache.Inspect(c.Request.Context(), id)
+	if err != nil {
+		h.cacheError(c, "inspect", err)
+		return
+	}
+
+	details := "miss"
+	if entry != nil {
+		details = "hit"
+	}
+	h.recordAudit(c.Request.Context(), id, domain.AuditCacheInspected, details)
+
+	if entry == nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_cached",
+			Message: "User is not cached",
+		})
+		return
+	}
+
+	resp := CachedUserResponse{
+		Missing:       entry.Missing,
+		FreshUntil:    optionalTime(entry.FreshUntil),
+		ExpiresInMs:   entry.ExpiresIn.Milliseconds(),
+		LastKnownGood: entry.LastKnownGood,
+		Local:         entry.Local,
+	}
+	if u := entry.User; u != nil {
+		resp.User = &UserResponse{
+			ID:          u.ID,
+			Username:    u.Username,
+			Name:        u.Name,
+			GivenName:   u.GivenName,
+			FamilyName:  u.FamilyName,
+			DisplayName: u.DisplayName,
+			Email:       u.Email,
+			Phone:       u.Phone,
+			Locale:      u.Locale,
+			Timezone:    u.Timezone,
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// EvictCachedUser handles DELETE /admin/cache/users/:id
+func (h *AdminHandler) EvictCachedUser(c *gin.Context) {
+	id, ok := h.cacheUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.cache.Delete(c.Request.Context(), id); err != nil {
+		h.cacheError(c, "evict", err)
+		return
+	}
+	h.recordAudit(c.Request.Context(), id, domain.AuditCacheEvicted, "")
+
+	c.Status(http.StatusNoContent)
+}
+
+// EvictCachePattern handles DELETE /admin/cache/users?pattern=
+func (h *AdminHandler) EvictCachePattern(c *gin.Context) {
+	if !h.cacheEnabled(c) {
+		return
+	}
+	pattern := c.Query("pattern")
+	if pattern == "" || len(pattern) > maxPatternLength {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_pattern",
+			Message: fmt.Sprintf("A pattern of at most %d characters is required, e.g. user:username:*", maxPatternLength),
+		})
+		return
+	}
+
+	n, err := h.cache.EvictPattern(c.Request.Context(), pattern)
+	if errors.Is(err, cache.ErrInvalidPattern) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_pattern",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		h.cacheError(c, "evict", err)
+		return
+	}
+	h.recordAudit(c.Request.Context(), 0, domain.AuditCacheEvicted, fmt.Sprintf("pattern=%s keys=%d", pattern, n))
+
+	c.JSON(http.StatusOK, EvictCacheResponse{Evicted: n})
+}
+
+// FlushCache handles POST /admin/cache/flush
+func (h *AdminHandler) FlushCache(c *gin.Context) {
+	if !h.cacheEnabled(c) {
+		return
+	}
+
+	n, err := h.cache.Flush(c.Request.Context())
+	if err != nil {
+		h.cacheError(c, "flush", err)
+		return
+	}
+	h.recordAudit(c.Request.Context(), 0, domain.AuditCacheFlushed, fmt.Sprintf("keys=%d", n))
+
+	c.JSON(http.StatusOK, EvictCacheResponse{Evicted: n})
+}
+
+// cacheEnabled writes an error response and returns false when there is no
+// cache to administer.
+func (h *AdminHandler) cacheEnabled(c *gin.Context) bool {
+	if h.cache == nil {
+		c.JSON(http.StatusNotImplemented, ErrorResponse{
+			Error:   "cache_disabled",
+			Message: "The user cache is disabled",
+		})
+		return false
+	}
+	return true
+}
+
+// cacheUserID parses the user ID of a cache route, writing an error response
+// and returning false when it is invalid or the cache is disabled.
+func (h *AdminHandler) cacheUserID(c *gin.Context) (int64, bool) {
+	if !h.cacheEnabled(c) {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_id",
+			Message: "User ID must be a valid number",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// cacheError writes the response for a failed cache operation.
+func (h *AdminHandler) cacheError(c *gin.Context, op string, err error) {
+	h.log.Error("cache admin operation failed", zap.String("operation", op), zap.Error(err))
+	c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+		Error:   "cache_unavailable",
+		Message: "The user cache is unavailable",
+	})
+}
+
+// recordAudit records an audit entry for a cache operation. Failures are
+// logged but do not fail the operation, which has already happened.
+func (h *AdminHandler) recordAudit(ctx context.Context, userID int64, action, details string) {
+	if h.audit == nil {
+		return
+	}
+	entry := &domain.AuditEntry{UserID: userID, Action: action, Details: details}
+	if err := h.audit.Record(ctx, entry); err != nil {
+		h.log.Error("failed to record audit entry", zap.Int64("user_id", userID), zap.String("action", action), zap.Error(err))
+	}
+}
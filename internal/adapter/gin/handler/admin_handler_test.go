@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"grpc-user-service/internal/adapter/cache"
+	"grpc-user-service/internal/adapter/gin/middleware"
+	domain "grpc-user-service/internal/domain/user"
+	"grpc-user-service/pkg/scheduler"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// staticJobs returns a fixed job status list
+type staticJobs []scheduler.JobStatus
+
+func (s staticJobs) Status() []scheduler.JobStatus { return s }
+
+// recordingAudit keeps the audit entries recorded
+type recordingAudit struct {
+	mu      sync.Mutex
+	entries []domain.AuditEntry
+}
+
+func (a *recordingAudit) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, *entry)
+	return nil
+}
+
+func setupAdminTest(t *testing.T, jobs staticJobs) *gin.Engine {
+	return setupAdminCacheTest(t, jobs, nil, nil)
+}
+
+func setupAdminCacheTest(t *testing.T, jobs staticJobs, cacheAdmin CacheAdmin, audit AuditRecorder) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := zaptest.NewLogger(t)
+	handler := NewAdminHandler(jobs, cacheAdmin, audit, logger)
+
+	r := gin.New()
+	admin := r.Group("/admin", middleware.AdminAuth("s3cret", logger))
+	admin.GET("/jobs", handler.ListJobs)
+	admin.GET("/cache/users/:id", handler.InspectCachedUser)
+	admin.DELETE("/cache/users/:id", handler.EvictCachedUser)
+	admin.DELETE("/cache/users", handler.EvictCachePattern)
+	admin.POST("/cache/flush", handler.FlushCache)
+	return r
+}
+
+// adminRequest serves an authenticated admin request
+func adminRequest(r *gin.Engine, method, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestListJobs(t *testing.T) {
+	lastRun := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	r := setupAdminTest(t, staticJobs{{
+		Name:          "purge-deleted-users",
+		Schedule:      "0 * * * *",
+		LastRun:       lastRun,
+		LastOutcome:   scheduler.OutcomeSucceeded,
+		LastProcessed: 12,
+		LastDuration:  1500 * time.Millisecond,
+		Runs:          3,
+	}})
+
+	t.Run("Success", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/admin/jobs", nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp ListJobsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Jobs, 1)
+		job := resp.Jobs[0]
+		assert.Equal(t, "purge-deleted-users", job.Name)
+		assert.Equal(t, scheduler.OutcomeSucceeded, job.LastOutcome)
+		assert.Equal(t, int64(12), job.LastProcessed)
+		assert.Equal(t, int64(1500), job.LastDurationMs)
+		assert.Nil(t, job.NextRun)
+		require.NotNil(t, job.LastRun)
+		assert.True(t, lastRun.Equal(*job.LastRun))
+	})
+
+	t.Run("Missing Token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/admin/jobs", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Wrong Token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/admin/jobs", nil)
+		req.Header.Set("Authorization", "Bearer guess")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestAdminCache(t *testing.T) {
+	ctx := context.Background()
+	userCache := cache.NewMemoryUserCache(100, time.Minute, zaptest.NewLogger(t))
+	require.NoError(t, userCache.Set(ctx, &domain.User{ID: 1, Name: "John Doe", Email: "john@example.com"}))
+	require.NoError(t, userCache.SetUsername(ctx, "johndoe", 1))
+	audit := &recordingAudit{}
+	r := setupAdminCacheTest(t, nil, userCache, audit)
+
+	t.Run("Inspect", func(t *testing.T) {
+		w := adminRequest(r, "GET", "/admin/cache/users/1")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp CachedUserResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.NotNil(t, resp.User)
+		assert.Equal(t, "john@example.com", resp.User.Email)
+		assert.Positive(t, resp.ExpiresInMs)
+		assert.NotNil(t, resp.FreshUntil)
+
+		w = adminRequest(r, "GET", "/admin/cache/users/2")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Evict Pattern", func(t *testing.T) {
+		w := adminRequest(r, "DELETE", "/admin/cache/users?pattern=user:username:*")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"evicted":1}`, w.Body.String())
+
+		w = adminRequest(r, "DELETE", "/admin/cache/users?pattern=ratelimit:*")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = adminRequest(r, "DELETE", "/admin/cache/users")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Evict", func(t *testing.T) {
+		w := adminRequest(r, "DELETE", "/admin/cache/users/1")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		cached, err := userCache.Get(ctx, 1)
+		require.NoError(t, err)
+		assert.Nil(t, cached)
+	})
+
+	t.Run("Flush", func(t *testing.T) {
+		require.NoError(t, userCache.Set(ctx, &domain.User{ID: 3, Name: "Jane Doe"}))
+
+		w := adminRequest(r, "POST", "/admin/cache/flush")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"evicted":1}`, w.Body.String())
+	})
+
+	t.Run("Audited", func(t *testing.T) {
+		actions := make([]string, len(audit.entries))
+		for i, e := range audit.entries {
+			actions[i] = e.Action
+		}
+		assert.Equal(t, []string{
+			domain.AuditCacheInspected,
+			domain.AuditCacheInspected,
+			domain.AuditCacheEvicted,
+			domain.AuditCacheEvicted,
+			domain.AuditCacheFlushed,
+		}, actions)
+		assert.Equal(t, int64(1), audit.entries[0].UserID)
+		assert.Equal(t, "pattern=user:username:* keys=1", audit.entries[2].Details)
+		assert.Equal(t, int64(0), audit.entries[4].UserID)
+	})
+}
+
+func TestAdminCache_Disabled(t *testing.T) {
+	r := setupAdminCacheTest(t, nil, nil, &recordingAudit{})
+
+	w := adminRequest(r, "POST", "/admin/cache/flush")
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"grpc-user-service/internal/usecase/user"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// UserEmailRequest represents the HTTP request body for email address operations
+type UserEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// VerifyUserEmailRequest represents the HTTP request body for verifying an email address
+type VerifyUserEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Token string `json:"token" binding:"required"` // Token sent to the address
+}
+
+// EmailVerificationResponse represents a newly issued email verification token
+type EmailVerificationResponse struct {
+	UserID    int64     `json:"user_id"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UserEmailResponse represents a single email address of a user
+type UserEmailResponse struct {
+	Email      string     `json:"email"`
+	Primary    bool       `json:"primary"`
+	Verified   bool       `json:"verified"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+}
+
+// UserEmailsResponse represents the HTTP response listing a user's email addresses
+type UserEmailsResponse struct {
+	UserID int64               `json:"user_id"`
+	Emails []UserEmailResponse `json:"emails"`
+}
+
+// ListUserEmails handles GET /v1/users/:id/emails
+func (h *UserHandler) ListUserEmails(c *gin.Context) {
+	id, ok := h.parseUserID(c)
+	if !ok {
+		return
+	}
+
+	h.log.Info("Gin ListUserEmails request", zap.Int64("user_id", id))
+
+	resp, err := h.uc.ListUserEmails(c.Request.Context(), user.ListUserEmailsRequest{UserID: id})
+	if err != nil {
+		h.log.Error("Gin ListUserEmails failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserEmailsResponse(resp))
+}
+
+// AddUserEmail handles POST /v1/users/:id/emails
+func (h *UserHandler) AddUserEmail(c *gin.Context) {
+	id, req, ok := h.bindUserEmail(c)
+	if !ok {
+		return
+	}
+
+	h.log.Info("Gin AddUserEmail request", zap.Int64("user_id", id), zap.String("email", req.Email))
+
+	resp, err := h.uc.AddUserEmail(c.Request.Context(), user.AddUserEmailRequest{UserID: id, Email: req.Email})
+	if err != nil {
+		h.log.Error("Gin AddUserEmail failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserEmailsResponse(resp))
+}
+
+// RemoveUserEmail handles DELETE /v1/users/:id/emails/:email
+func (h *UserHandler) RemoveUserEmail(c *gin.Context) {
+	id, ok := h.parseUserID(c)
+	if !ok {
+		return
+	}
+	email := c.Param("email")
+
+	h.log.Info("Gin RemoveUserEmail request", zap.Int64("user_id", id), zap.String("email", email))
+
+	resp, err := h.uc.RemoveUserEmail(c.Request.Context(), user.RemoveUserEmailRequest{UserID: id, Email: email})
+	if err != nil {
+		h.log.Error("Gin RemoveUserEmail failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserEmailsResponse(resp))
+}
+
+// SetPrimaryUserEmail handles PUT /v1/users/:id/emails/primary
+func (h *UserHandler) SetPrimaryUserEmail(c *gin.Context) {
+	id, req, ok := h.bindUserEmail(c)
+	if !ok {
+		return
+	}
+
+	h.log.Info("Gin SetPrimaryUserEmail request", zap.Int64("user_id", id), zap.String("email", req.Email))
+
+	resp, err := h.uc.SetPrimaryUserEmail(c.Request.Context(), user.SetPrimaryUserEmailRequest{UserID: id, Email: req.Email})
+	if err != nil {
+		h.log.Error("Gin SetPrimaryUserEmail failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserEmailsResponse(resp))
+}
+
+// IssueUserEmailVerification handles POST /v1/users/:id/emails/verification
+func (h *UserHandler) IssueUserEmailVerification(c *gin.Context) {
+	id, req, ok := h.bindUserEmail(c)
+	if !ok {
+		return
+	}
+
+	h.log.Info("Gin IssueUserEmailVerification request", zap.Int64("user_id", id), zap.String("email", req.Email))
+
+	resp, err := h.uc.IssueUserEmailVerification(c.Request.Context(), user.IssueUserEmailVerificationRequest{UserID: id, Email: req.Email})
+	if err != nil {
+		h.log.Error("Gin IssueUserEmailVerification failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EmailVerificationResponse{
+		UserID:    resp.UserID,
+		Email:     resp.Email,
+		Token:     resp.Token,
+		ExpiresAt: resp.ExpiresAt,
+	})
+}
+
+// VerifyUserEmail handles POST /v1/users/:id/emails/verify
+func (h *UserHandler) VerifyUserEmail(c *gin.Context) {
+	id, ok := h.parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req VerifyUserEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid verify user email request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Gin VerifyUserEmail request", zap.Int64("user_id", id), zap.String("email", req.Email))
+
+	resp, err := h.uc.VerifyUserEmail(c.Request.Context(), user.VerifyUserEmailRequest{UserID: id, Email: req.Email, Token: req.Token})
+	if err != nil {
+		h.log.Error("Gin VerifyUserEmail failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserEmailsResponse(resp))
+}
+
+// parseUserID reads the :id path parameter, writing a 400 response when it is not a number
+func (h *UserHandler) parseUserID(c *gin.Context) (int64, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.log.Warn("Invalid user ID", zap.String("id", idStr), zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_id",
+			Message: "User ID must be a valid number",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// bindUserEmail reads the :id path parameter and the email request body
+func (h *UserHandler) bindUserEmail(c *gin.Context) (int64, UserEmailRequest, bool) {
+	var req UserEmailRequest
+
+	id, ok := h.parseUserID(c)
+	if !ok {
+		return 0, req, false
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid user email request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return 0, req, false
+	}
+
+	return id, req, true
+}
+
+// toUserEmailsResponse maps a usecase UserEmailsResponse to its HTTP representation
+func toUserEmailsResponse(resp *user.UserEmailsResponse) UserEmailsResponse {
+	emails := make([]UserEmailResponse, len(resp.Emails))
+	for i, e := range resp.Emails {
+		emails[i] = UserEmailResponse{
+			Email:    e.Email,
+			Primary:  e.Primary,
+			Verified: e.Verified,
+		}
+		if !e.VerifiedAt.IsZero() {
+			verifiedAt := e.VerifiedAt
+			emails[i].VerifiedAt = &verifiedAt
+		}
+	}
+
+	return UserEmailsResponse{
+		UserID: resp.UserID,
+		Emails: emails,
+	}
+}
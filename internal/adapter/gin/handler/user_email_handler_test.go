@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	usecase "grpc-user-service/internal/usecase/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListUserEmails(t *testing.T) {
+	r, handler, mockUsecase := setupTest(t)
+	r.GET("/users/:id/emails", handler.ListUserEmails)
+
+	verifiedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockUsecase.On("ListUserEmails", mock.Anything, usecase.ListUserEmailsRequest{UserID: 1}).Return(&usecase.UserEmailsResponse{
+		UserID: 1,
+		Emails: []usecase.UserEmail{
+			{Email: "john@example.com", Primary: true, Verified: true, VerifiedAt: verifiedAt},
+			{Email: "john@work.example.com"},
+		},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/users/1/emails", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp UserEmailsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Emails, 2)
+	assert.True(t, resp.Emails[0].Primary)
+	require.NotNil(t, resp.Emails[0].VerifiedAt)
+	assert.True(t, verifiedAt.Equal(*resp.Emails[0].VerifiedAt))
+	assert.Nil(t, resp.Emails[1].VerifiedAt)
+}
+
+func TestAddUserEmail(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.POST("/users/:id/emails", handler.AddUserEmail)
+
+		mockUsecase.On("AddUserEmail", mock.Anything, usecase.AddUserEmailRequest{UserID: 1, Email: "john@work.example.com"}).
+			Return(&usecase.UserEmailsResponse{UserID: 1, Emails: []usecase.UserEmail{
+				{Email: "john@example.com", Primary: true},
+				{Email: "john@work.example.com"},
+			}}, nil)
+
+		body, _ := json.Marshal(UserEmailRequest{Email: "john@work.example.com"})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/users/1/emails", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Invalid Email", func(t *testing.T) {
+		r, handler, _ := setupTest(t)
+		r.POST("/users/:id/emails", handler.AddUserEmail)
+
+		body, _ := json.Marshal(UserEmailRequest{Email: "not-an-email"})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/users/1/emails", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Already Exists", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.POST("/users/:id/emails", handler.AddUserEmail)
+
+		mockUsecase.On("AddUserEmail", mock.Anything, usecase.AddUserEmailRequest{UserID: 1, Email: "jane@example.com"}).
+			Return(nil, pkgerrors.NewAlreadyExistsError("user", "email already exists"))
+
+		body, _ := json.Marshal(UserEmailRequest{Email: "jane@example.com"})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/users/1/emails", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestRemoveUserEmail(t *testing.T) {
+	t.Run("Primary Email", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.DELETE("/users/:id/emails/:email", handler.RemoveUserEmail)
+
+		mockUsecase.On("RemoveUserEmail", mock.Anything, usecase.RemoveUserEmailRequest{UserID: 1, Email: "john@example.com"}).
+			Return(nil, pkgerrors.NewValidationError("email", "cannot remove the primary email; set another primary email first"))
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("DELETE", "/users/1/emails/john@example.com", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		r, handler, _ := setupTest(t)
+		r.DELETE("/users/:id/emails/:email", handler.RemoveUserEmail)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("DELETE", "/users/abc/emails/john@example.com", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
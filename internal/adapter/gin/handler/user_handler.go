@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"grpc-user-service/internal/usecase/user"
+	pkgerrors "grpc-user-service/pkg/errors"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -27,21 +29,43 @@ func NewUserHandler(uc user.Usecase, log *zap.Logger) *UserHandler {
 
 // CreateUserRequest represents the HTTP request body for creating a user
 type CreateUserRequest struct {
-	Name  string `json:"name" binding:"required,min=3,max=100"`
-	Email string `json:"email" binding:"required,email"`
+	Username    string `json:"username,omitempty" binding:"max=100"`
+	Name        string `json:"name" binding:"required_without_all=GivenName FamilyName DisplayName,max=100"`
+	GivenName   string `json:"given_name,omitempty" binding:"max=100"`
+	FamilyName  string `json:"family_name,omitempty" binding:"max=100"`
+	DisplayName string `json:"display_name,omitempty" binding:"max=100"`
+	Email       string `json:"email" binding:"required,email"`
+	Phone       string `json:"phone,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+	Timezone    string `json:"timezone,omitempty"`
 }
 
 // UpdateUserRequest represents the HTTP request body for updating a user
 type UpdateUserRequest struct {
-	Name  string `json:"name" binding:"omitempty,min=3,max=100"`
-	Email string `json:"email" binding:"omitempty,email"`
+	Username    string   `json:"username,omitempty" binding:"max=100"`
+	Name        string   `json:"name" binding:"omitempty,max=100"`
+	GivenName   string   `json:"given_name,omitempty" binding:"max=100"`
+	FamilyName  string   `json:"family_name,omitempty" binding:"max=100"`
+	DisplayName string   `json:"display_name,omitempty" binding:"max=100"`
+	Email       string   `json:"email" binding:"omitempty,email"`
+	Phone       string   `json:"phone,omitempty"`
+	Locale      string   `json:"locale,omitempty"`
+	Timezone    string   `json:"timezone,omitempty"`
+	Clear       []string `json:"clear,omitempty"` // Optional fields to empty: phone, locale or timezone
 }
 
 // UserResponse represents the HTTP response for user data
 type UserResponse struct {
-	ID    int64  `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID          int64  `json:"id"`
+	Username    string `json:"username,omitempty"`
+	Name        string `json:"name"`
+	GivenName   string `json:"given_name,omitempty"`
+	FamilyName  string `json:"family_name,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+	Timezone    string `json:"timezone,omitempty"`
 }
 
 // ListUsersResponse represents the HTTP response for listing users
@@ -79,8 +103,15 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	h.log.Info("Gin CreateUser request", zap.String("name", req.Name), zap.String("email", req.Email))
 
 	ucReq := user.CreateUserRequest{
-		Name:  req.Name,
-		Email: req.Email,
+		Username:    req.Username,
+		Name:        req.Name,
+		GivenName:   req.GivenName,
+		FamilyName:  req.FamilyName,
+		DisplayName: req.DisplayName,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Locale:      req.Locale,
+		Timezone:    req.Timezone,
 	}
 
 	resp, err := h.uc.CreateUser(c.Request.Context(), ucReq)
@@ -118,11 +149,40 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, UserResponse{
-		ID:    resp.ID,
-		Name:  resp.Name,
-		Email: resp.Email,
-	})
+	c.JSON(http.StatusOK, toUserResponse(resp))
+}
+
+// GetUserByUsername handles GET /v1/users/by-username/:username
+func (h *UserHandler) GetUserByUsername(c *gin.Context) {
+	username := c.Param("username")
+
+	h.log.Info("Gin GetUserByUsername request", zap.String("username", username))
+
+	ucReq := user.GetUserByUsernameRequest{Username: username}
+	resp, err := h.uc.GetUserByUsername(c.Request.Context(), ucReq)
+	if err != nil {
+		h.log.Error("Gin GetUserByUsername failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserResponse(resp))
+}
+
+// toUserResponse maps a usecase GetUserResponse to the HTTP user representation
+func toUserResponse(resp *user.GetUserResponse) UserResponse {
+	return UserResponse{
+		ID:          resp.ID,
+		Username:    resp.Username,
+		Name:        resp.Name,
+		GivenName:   resp.GivenName,
+		FamilyName:  resp.FamilyName,
+		DisplayName: resp.DisplayName,
+		Email:       resp.Email,
+		Phone:       resp.Phone,
+		Locale:      resp.Locale,
+		Timezone:    resp.Timezone,
+	}
 }
 
 // UpdateUser handles PUT /v1/users/:id
@@ -151,9 +211,17 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	h.log.Info("Gin UpdateUser request", zap.Int64("id", id), zap.String("name", req.Name), zap.String("email", req.Email))
 
 	ucReq := user.UpdateUserRequest{
-		ID:    id,
-		Name:  req.Name,
-		Email: req.Email,
+		ID:          id,
+		Username:    req.Username,
+		Name:        req.Name,
+		GivenName:   req.GivenName,
+		FamilyName:  req.FamilyName,
+		DisplayName: req.DisplayName,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Locale:      req.Locale,
+		Timezone:    req.Timezone,
+		Clear:       req.Clear,
 	}
 
 	resp, err := h.uc.UpdateUser(c.Request.Context(), ucReq)
@@ -199,6 +267,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 // ListUsers handles GET /v1/users
 func (h *UserHandler) ListUsers(c *gin.Context) {
 	query := c.DefaultQuery("query", "")
+	locale := c.DefaultQuery("locale", "")
 	pageStr := c.DefaultQuery("page", "1")
 	limitStr := c.DefaultQuery("limit", "10")
 
@@ -215,12 +284,13 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		limit = 100
 	}
 
-	h.log.Info("Gin ListUsers request", zap.String("query", query), zap.Int64("page", page), zap.Int64("limit", limit))
+	h.log.Info("Gin ListUsers request", zap.String("query", query), zap.String("locale", locale), zap.Int64("page", page), zap.Int64("limit", limit))
 
 	ucReq := user.ListUsersRequest{
-		Query: query,
-		Page:  page,
-		Limit: limit,
+		Query:  query,
+		Locale: locale,
+		Page:   page,
+		Limit:  limit,
 	}
 
 	resp, err := h.uc.ListUsers(c.Request.Context(), ucReq)
@@ -233,9 +303,16 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	users := make([]UserResponse, len(resp.Users))
 	for i, u := range resp.Users {
 		users[i] = UserResponse{
-			ID:    u.ID,
-			Name:  u.Name,
-			Email: u.Email,
+			ID:          u.ID,
+			Username:    u.Username,
+			Name:        u.Name,
+			GivenName:   u.GivenName,
+			FamilyName:  u.FamilyName,
+			DisplayName: u.DisplayName,
+			Email:       u.Email,
+			Phone:       u.Phone,
+			Locale:      u.Locale,
+			Timezone:    u.Timezone,
 		}
 	}
 
@@ -257,6 +334,34 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 
 // handleError converts usecase errors to appropriate HTTP responses
 func (h *UserHandler) handleError(c *gin.Context, err error) {
+	// Validation errors carry field-specific messages that don't always mention "invalid"
+	var validationErr *pkgerrors.ValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_input",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var notFoundErr *pkgerrors.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var alreadyExistsErr *pkgerrors.AlreadyExistsError
+	if errors.As(err, &alreadyExistsErr) {
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "already_exists",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	// Check for custom error types from pkg/errors
 	type grpcStatuser interface {
 		GRPCStatus() *status.Status
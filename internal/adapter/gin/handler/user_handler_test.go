@@ -39,6 +39,14 @@ func (m *MockUserUsecase) GetUser(ctx context.Context, req usecase.GetUserReques
 	return args.Get(0).(*usecase.GetUserResponse), args.Error(1)
 }
 
+func (m *MockUserUsecase) GetUserByUsername(ctx context.Context, req usecase.GetUserByUsernameRequest) (*usecase.GetUserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.GetUserResponse), args.Error(1)
+}
+
 func (m *MockUserUsecase) UpdateUser(ctx context.Context, req usecase.UpdateUserRequest) (*usecase.UpdateUserResponse, error) {
 	args := m.Called(ctx, req)
 	if args.Get(0) == nil {
@@ -63,6 +71,94 @@ func (m *MockUserUsecase) ListUsers(ctx context.Context, req usecase.ListUsersRe
 	return args.Get(0).(*usecase.ListUsersResponse), args.Error(1)
 }
 
+func (m *MockUserUsecase) ListUserEmails(ctx context.Context, req usecase.ListUserEmailsRequest) (*usecase.UserEmailsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.UserEmailsResponse), args.Error(1)
+}
+
+func (m *MockUserUsecase) AddUserEmail(ctx context.Context, req usecase.AddUserEmailRequest) (*usecase.UserEmailsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.UserEmailsResponse), args.Error(1)
+}
+
+func (m *MockUserUsecase) RemoveUserEmail(ctx context.Context, req usecase.RemoveUserEmailRequest) (*usecase.UserEmailsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.UserEmailsResponse), args.Error(1)
+}
+
+func (m *MockUserUsecase) SetPrimaryUserEmail(ctx context.Context, req usecase.SetPrimaryUserEmailRequest) (*usecase.UserEmailsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.UserEmailsResponse), args.Error(1)
+}
+
+func (m *MockUserUsecase) IssueUserEmailVerification(ctx context.Context, req usecase.IssueUserEmailVerificationRequest) (*usecase.EmailVerificationResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.EmailVerificationResponse), args.Error(1)
+}
+
+func (m *MockUserUsecase) VerifyUserEmail(ctx context.Context, req usecase.VerifyUserEmailRequest) (*usecase.UserEmailsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.UserEmailsResponse), args.Error(1)
+}
+
+func (m *MockUserUsecase) LinkIdentity(ctx context.Context, req usecase.LinkIdentityRequest) (*usecase.LinkIdentityResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.LinkIdentityResponse), args.Error(1)
+}
+
+func (m *MockUserUsecase) UnlinkIdentity(ctx context.Context, req usecase.UnlinkIdentityRequest) (*usecase.UnlinkIdentityResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.UnlinkIdentityResponse), args.Error(1)
+}
+
+func (m *MockUserUsecase) GetUserByIdentity(ctx context.Context, req usecase.GetUserByIdentityRequest) (*usecase.GetUserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.GetUserResponse), args.Error(1)
+}
+
+func (m *MockUserUsecase) ExportUserData(ctx context.Context, req usecase.ExportUserDataRequest) (*usecase.ExportUserDataResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ExportUserDataResponse), args.Error(1)
+}
+
+func (m *MockUserUsecase) EraseUser(ctx context.Context, req usecase.EraseUserRequest) (*usecase.EraseUserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.EraseUserResponse), args.Error(1)
+}
+
 func setupTest(t *testing.T) (*gin.Engine, *UserHandler, *MockUserUsecase) {
 	gin.SetMode(gin.TestMode)
 	mockUsecase := new(MockUserUsecase)
@@ -135,6 +231,52 @@ func TestCreateUser(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 
+	t.Run("Structured Name Only", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.POST("/users", handler.CreateUser)
+
+		reqBody := CreateUserRequest{
+			GivenName:  "Li",
+			FamilyName: "Wei",
+			Email:      "li.wei@example.com",
+		}
+		jsonBody, _ := json.Marshal(reqBody)
+
+		mockUsecase.On("CreateUser", mock.Anything, mock.MatchedBy(func(req usecase.CreateUserRequest) bool {
+			return req.Name == "" && req.GivenName == "Li" && req.FamilyName == "Wei"
+		})).Return(&usecase.CreateUserResponse{ID: 1}, nil)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/users", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Contact Validation Error", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.POST("/users", handler.CreateUser)
+
+		reqBody := CreateUserRequest{
+			Name:     "John Doe",
+			Email:    "john@example.com",
+			Timezone: "Mars/Olympus_Mons",
+		}
+		jsonBody, _ := json.Marshal(reqBody)
+
+		mockUsecase.On("CreateUser", mock.Anything, mock.MatchedBy(func(req usecase.CreateUserRequest) bool {
+			return req.Timezone == "Mars/Olympus_Mons"
+		})).Return(nil, pkgerrors.NewValidationError("timezone", "timezone must be a valid IANA time zone"))
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/users", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 	t.Run("Usecase Error", func(t *testing.T) {
 		r, handler, mockUsecase := setupTest(t)
 		r.POST("/users", handler.CreateUser)
@@ -206,6 +348,43 @@ func TestGetUser(t *testing.T) {
 	})
 }
 
+func TestGetUserByUsername(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.GET("/users/:id", handler.GetUser)
+		r.GET("/users/by-username/:username", handler.GetUserByUsername)
+
+		expected := &usecase.GetUserResponse{ID: 7, Username: "johndoe", Name: "John Doe", Email: "john@example.com"}
+		mockUsecase.On("GetUserByUsername", mock.Anything, usecase.GetUserByUsernameRequest{Username: "JohnDoe"}).Return(expected, nil)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/users/by-username/JohnDoe", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp UserResponse
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), resp.ID)
+		assert.Equal(t, "johndoe", resp.Username)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.GET("/users/by-username/:username", handler.GetUserByUsername)
+
+		mockUsecase.On("GetUserByUsername", mock.Anything, usecase.GetUserByUsernameRequest{Username: "ghost"}).
+			Return(nil, pkgerrors.NewNotFoundError("user", "user not found: username=ghost"))
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/users/by-username/ghost", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
 func TestUpdateUser(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		r, handler, mockUsecase := setupTest(t)
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"grpc-user-service/internal/usecase/user"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// LinkIdentityRequest represents the HTTP request body for linking an external identity
+type LinkIdentityRequest struct {
+	Provider string `json:"provider" binding:"required,max=64"`
+	Subject  string `json:"subject" binding:"required,max=255"`
+}
+
+// IdentityResponse represents an external identity linked to a user
+type IdentityResponse struct {
+	UserID   int64  `json:"user_id"`
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+}
+
+// LinkIdentity handles POST /v1/users/:id/identities
+func (h *UserHandler) LinkIdentity(c *gin.Context) {
+	id, ok := h.parseUserID(c)
+	if !ok {
+		return
+	}
+
+	var req LinkIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid link identity request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Gin LinkIdentity request", zap.Int64("user_id", id), zap.String("provider", req.Provider))
+
+	ucReq := user.LinkIdentityRequest{
+		UserID:   id,
+		Provider: req.Provider,
+		Subject:  req.Subject,
+	}
+
+	resp, err := h.uc.LinkIdentity(c.Request.Context(), ucReq)
+	if err != nil {
+		h.log.Error("Gin LinkIdentity failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, IdentityResponse{
+		UserID:   resp.UserID,
+		Provider: resp.Provider,
+		Subject:  resp.Subject,
+	})
+}
+
+// UnlinkIdentity handles DELETE /v1/users/:id/identities/:provider/:subject
+func (h *UserHandler) UnlinkIdentity(c *gin.Context) {
+	id, ok := h.parseUserID(c)
+	if !ok {
+		return
+	}
+	provider := c.Param("provider")
+
+	h.log.Info("Gin UnlinkIdentity request", zap.Int64("user_id", id), zap.String("provider", provider))
+
+	ucReq := user.UnlinkIdentityRequest{
+		UserID:   id,
+		Provider: provider,
+		Subject:  c.Param("subject"),
+	}
+
+	resp, err := h.uc.UnlinkIdentity(c.Request.Context(), ucReq)
+	if err != nil {
+		h.log.Error("Gin UnlinkIdentity failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": resp.UserID,
+	})
+}
+
+// GetUserByIdentity handles GET /v1/users/by-identity/:provider/:subject
+func (h *UserHandler) GetUserByIdentity(c *gin.Context) {
+	provider := c.Param("provider")
+
+	h.log.Info("Gin GetUserByIdentity request", zap.String("provider", provider))
+
+	ucReq := user.GetUserByIdentityRequest{
+		Provider: provider,
+		Subject:  c.Param("subject"),
+	}
+
+	resp, err := h.uc.GetUserByIdentity(c.Request.Context(), ucReq)
+	if err != nil {
+		h.log.Error("Gin GetUserByIdentity failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toUserResponse(resp))
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	usecase "grpc-user-service/internal/usecase/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkIdentity(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.POST("/users/:id/identities", handler.LinkIdentity)
+
+		mockUsecase.On("LinkIdentity", mock.Anything, usecase.LinkIdentityRequest{UserID: 1, Provider: "google", Subject: "abc123"}).
+			Return(&usecase.LinkIdentityResponse{UserID: 1, Provider: "google", Subject: "abc123"}, nil)
+
+		body, _ := json.Marshal(LinkIdentityRequest{Provider: "google", Subject: "abc123"})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/users/1/identities", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp IdentityResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(1), resp.UserID)
+		assert.Equal(t, "google", resp.Provider)
+	})
+
+	t.Run("Linked To Another User", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.POST("/users/:id/identities", handler.LinkIdentity)
+
+		mockUsecase.On("LinkIdentity", mock.Anything, mock.Anything).
+			Return(nil, pkgerrors.NewAlreadyExistsError("identity", "identity already linked to another user"))
+
+		body, _ := json.Marshal(LinkIdentityRequest{Provider: "google", Subject: "abc123"})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/users/2/identities", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Missing Subject", func(t *testing.T) {
+		r, handler, _ := setupTest(t)
+		r.POST("/users/:id/identities", handler.LinkIdentity)
+
+		body, _ := json.Marshal(LinkIdentityRequest{Provider: "google"})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/users/1/identities", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetUserByIdentity(t *testing.T) {
+	r, handler, mockUsecase := setupTest(t)
+	r.GET("/users/:id", handler.GetUser)
+	r.GET("/users/by-identity/:provider/:subject", handler.GetUserByIdentity)
+
+	mockUsecase.On("GetUserByIdentity", mock.Anything, usecase.GetUserByIdentityRequest{Provider: "github", Subject: "42"}).
+		Return(&usecase.GetUserResponse{ID: 7, Name: "John Doe", Email: "john@example.com"}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/users/by-identity/github/42", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp UserResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(7), resp.ID)
+}
+
+func TestUnlinkIdentity_NotFound(t *testing.T) {
+	r, handler, mockUsecase := setupTest(t)
+	r.DELETE("/users/:id/identities/:provider/:subject", handler.UnlinkIdentity)
+
+	mockUsecase.On("UnlinkIdentity", mock.Anything, usecase.UnlinkIdentityRequest{UserID: 1, Provider: "github", Subject: "42"}).
+		Return(nil, pkgerrors.NewNotFoundError("identity", "identity not found: user_id=1 provider=github"))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("DELETE", "/users/1/identities/github/42", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"grpc-user-service/internal/usecase/user"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// EraseUserRequest represents the optional HTTP request body for erasing a user
+type EraseUserRequest struct {
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+// LinkedIdentityResponse represents an external identity in a data export
+type LinkedIdentityResponse struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditEntryResponse represents an audit trail entry in a data export
+type AuditEntryResponse struct {
+	Action    string    `json:"action"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportUserDataResponse represents the HTTP response for a user data export
+type ExportUserDataResponse struct {
+	FormatVersion int                      `json:"format_version"`
+	ExportedAt    time.Time                `json:"exported_at"`
+	Profile       UserResponse             `json:"profile"`
+	Emails        []UserEmailResponse      `json:"emails"`
+	Identities    []LinkedIdentityResponse `json:"identities"`
+	AuditEntries  []AuditEntryResponse     `json:"audit_entries"`
+}
+
+// EraseUserResponse represents the HTTP response for an erasure
+type EraseUserResponse struct {
+	UserID   int64     `json:"user_id"`
+	ErasedAt time.Time `json:"erased_at"`
+}
+
+// ExportUserData handles GET /v1/users/:id/export
+func (h *UserHandler) ExportUserData(c *gin.Context) {
+	id, ok := h.parseUserID(c)
+	if !ok {
+		return
+	}
+
+	h.log.Info("Gin ExportUserData request", zap.Int64("user_id", id))
+
+	resp, err := h.uc.ExportUserData(c.Request.Context(), user.ExportUserDataRequest{UserID: id})
+	if err != nil {
+		h.log.Error("Gin ExportUserData failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	out := ExportUserDataResponse{
+		FormatVersion: resp.FormatVersion,
+		ExportedAt:    resp.ExportedAt,
+		Profile:       toUserResponse(&resp.Profile),
+		Emails:        toUserEmailsResponse(&user.UserEmailsResponse{UserID: id, Emails: resp.Emails}).Emails,
+		Identities:    make([]LinkedIdentityResponse, len(resp.Identities)),
+		AuditEntries:  make([]AuditEntryResponse, len(resp.AuditEntries)),
+	}
+	for i, identity := range resp.Identities {
+		out.Identities[i] = LinkedIdentityResponse{
+			Provider:  identity.Provider,
+			Subject:   identity.Subject,
+			CreatedAt: identity.CreatedAt,
+		}
+	}
+	for i, e := range resp.AuditEntries {
+		out.AuditEntries[i] = AuditEntryResponse{
+			Action:    e.Action,
+			Details:   e.Details,
+			CreatedAt: e.CreatedAt,
+		}
+	}
+
+	// Suggest a file name so browsers save the export instead of rendering it
+	c.Header("Content-Disposition", "attachment; filename=\"user-data-export.json\"")
+	c.JSON(http.StatusOK, out)
+}
+
+// EraseUser handles POST /v1/users/:id/erase
+func (h *UserHandler) EraseUser(c *gin.Context) {
+	id, ok := h.parseUserID(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional; an empty body erases without a reason
+	var req EraseUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.log.Warn("Invalid erase user request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.log.Info("Gin EraseUser request", zap.Int64("user_id", id))
+
+	resp, err := h.uc.EraseUser(c.Request.Context(), user.EraseUserRequest{UserID: id, Reason: req.Reason})
+	if err != nil {
+		h.log.Error("Gin EraseUser failed", zap.Error(err))
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EraseUserResponse{
+		UserID:   resp.UserID,
+		ErasedAt: resp.ErasedAt,
+	})
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	usecase "grpc-user-service/internal/usecase/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportUserData(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.GET("/users/:id/export", handler.ExportUserData)
+
+		mockUsecase.On("ExportUserData", mock.Anything, usecase.ExportUserDataRequest{UserID: 1}).
+			Return(&usecase.ExportUserDataResponse{
+				FormatVersion: usecase.ExportFormatVersion,
+				ExportedAt:    time.Now().UTC(),
+				Profile:       usecase.GetUserResponse{ID: 1, Name: "John Doe", Email: "john@example.com"},
+				Emails:        []usecase.UserEmail{{Email: "john@example.com", Primary: true}},
+				Identities:    []usecase.LinkedIdentity{{Provider: "google", Subject: "abc123"}},
+				AuditEntries:  []usecase.AuditEntry{{Action: "user.exported"}},
+			}, nil)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/users/1/export", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+
+		var resp ExportUserDataResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, usecase.ExportFormatVersion, resp.FormatVersion)
+		assert.Equal(t, "john@example.com", resp.Profile.Email)
+		assert.Len(t, resp.Emails, 1)
+		assert.Len(t, resp.Identities, 1)
+		assert.Equal(t, "user.exported", resp.AuditEntries[0].Action)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.GET("/users/:id/export", handler.ExportUserData)
+
+		mockUsecase.On("ExportUserData", mock.Anything, mock.Anything).
+			Return(nil, pkgerrors.NewNotFoundError("user", "user not found: id=99"))
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/users/99/export", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestEraseUser(t *testing.T) {
+	t.Run("With Reason", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.POST("/users/:id/erase", handler.EraseUser)
+
+		mockUsecase.On("EraseUser", mock.Anything, usecase.EraseUserRequest{UserID: 1, Reason: "ticket-42"}).
+			Return(&usecase.EraseUserResponse{UserID: 1, ErasedAt: time.Now().UTC()}, nil)
+
+		body, _ := json.Marshal(EraseUserRequest{Reason: "ticket-42"})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/users/1/erase", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp EraseUserResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, int64(1), resp.UserID)
+		assert.False(t, resp.ErasedAt.IsZero())
+	})
+
+	t.Run("Empty Body", func(t *testing.T) {
+		r, handler, mockUsecase := setupTest(t)
+		r.POST("/users/:id/erase", handler.EraseUser)
+
+		mockUsecase.On("EraseUser", mock.Anything, usecase.EraseUserRequest{UserID: 1}).
+			Return(&usecase.EraseUserResponse{UserID: 1, ErasedAt: time.Now().UTC()}, nil)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/users/1/erase", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Reason Too Long", func(t *testing.T) {
+		r, handler, _ := setupTest(t)
+		r.POST("/users/:id/erase", handler.EraseUser)
+
+		body, _ := json.Marshal(EraseUserRequest{Reason: strings.Repeat("x", 256)})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/users/1/erase", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
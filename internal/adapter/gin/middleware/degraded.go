@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"grpc-user-service/pkg/degraded"
+)
+
+// Degraded returns a Gin middleware that sets the X-Degraded response header
+// when a lower layer reports that the response is served in a degraded mode.
+func Degraded() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := degraded.WithReporter(c.Request.Context(), func(reason string) {
+			// Reports come from the handler's use case call, before the body is written
+			c.Header(degraded.Header, reason)
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -3,94 +3,122 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
-	grpcmiddleware "grpc-user-service/internal/adapter/grpc/middleware"
+	"grpc-user-service/pkg/ratelimit"
 
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
 )
 
-// RateLimiter returns a Gin middleware for rate limiting using Token Bucket algorithm
-func RateLimiter(limiter *grpcmiddleware.RateLimiter, redisClient *redis.Client) gin.HandlerFunc {
+// RateLimiter returns a Gin middleware for rate limiting, by the algorithm of
+// each limit. It shares the enforcer, and so its configuration, with the gRPC
+// interceptor. Limits are kept per route template, so that /v1/users/1 and
+// /v1/users/2 share one, and per client: the principal identified by the
+// request context or X-API-Key header, or else the client IP.
+func RateLimiter(enforcer *ratelimit.Enforcer, log *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if limiter == nil || redisClient == nil {
+		// Skip rate limiting if disabled
+		if !enforcer.Enabled() {
 			c.Next()
 			return
 		}
 
+		ctx := c.Request.Context()
+
 		// Get client IP
 		clientIP := c.ClientIP()
 
-		// Get request method and path for rate limit key
-		method := c.Request.Method
-		path := c.Request.URL.Path
-		// Use Token Bucket key prefix for consistency with gRPC
-		key := fmt.Sprintf("ratelimit:tb:%s:%s:%s", method, path, clientIP)
-
-		// Get rate limiter config
-		// Note: We use the same config as gRPC rate limiter
-		requestsPerSecond := 10.0 // Default, should match gRPC config
-		burstCapacity := 20       // Default, should match gRPC config
-
-		// Token Bucket algorithm implemented in Lua for atomicity
-		// Data structure: {last_refill_time, current_tokens}
-		luaScript := `
-			local key = KEYS[1]
-			local rate = tonumber(ARGV[1])         -- tokens per second
-			local capacity = tonumber(ARGV[2])     -- max tokens in bucket
-			local now = tonumber(ARGV[3])          -- current timestamp
-			local requested = tonumber(ARGV[4])    -- tokens requested (always 1)
-			
-			-- Get current bucket state
-			local bucket = redis.call('HMGET', key, 'last_refill', 'tokens')
-			local last_refill = tonumber(bucket[1]) or now
-			local tokens = tonumber(bucket[2]) or capacity
-			
-			-- Calculate tokens to add based on elapsed time
-			local elapsed = math.max(0, now - last_refill)
-			local tokens_to_add = elapsed * rate
-			tokens = math.min(capacity, tokens + tokens_to_add)
-			
-			-- Try to consume requested tokens
-			if tokens >= requested then
-				-- Success: consume token
-				tokens = tokens - requested
-				redis.call('HMSET', key, 'last_refill', now, 'tokens', tokens)
-				redis.call('EXPIRE', key, 60)  -- Keep bucket for 60 seconds
-				return 1  -- Allow request
-			else
-				-- Failure: not enough tokens
-				redis.call('HMSET', key, 'last_refill', now, 'tokens', tokens)
-				redis.call('EXPIRE', key, 60)
-				return 0  -- Deny request
-			end
-		`
-
-		// Get current timestamp in seconds
-		now := float64(redisClient.Time(c.Request.Context()).Val().Unix())
+		// Identified clients are limited by principal instead of IP
+		principal, identified := enforcer.Identify(ctx, c.GetHeader("X-API-Key"))
+		subject := clientIP
+		if identified {
+			subject = principal.ID
+		}
 
-		allowed, err := redisClient.Eval(c.Request.Context(), luaScript, []string{key},
-			requestsPerSecond,
-			burstCapacity,
-			now,
-			1, // Always request 1 token
-		).Int64()
+		// Get request method and route template for rate limit key; requests
+		// matching no route share the empty template
+		method := c.Request.Method
+		path := c.FullPath()
+		key := fmt.Sprintf("%s:%s:%s", method, path, subject)
 
+		config := enforcer.Config()
+		limit := config.HTTPLimit(method+" "+path, principal)
+		res, err := enforcer.Allow(ctx, key, limit)
 		if err != nil {
-			// Log error but allow request (fail-open strategy)
+			// Failing open, the enforcer returns Redis errors: allow request to proceed
+			log.Warn("rate limiter redis error, allowing request",
+				zap.String("client_ip", clientIP),
+				zap.String("principal", principal.ID),
+				zap.String("path", path),
+				zap.Error(err),
+			)
 			c.Next()
 			return
 		}
 
-		if allowed == 0 {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":   "rate_limit_exceeded",
-				"message": fmt.Sprintf("Rate limit exceeded: %.2f requests/second (burst capacity: %d)", requestsPerSecond, burstCapacity),
-			})
-			c.Abort()
+		// Tell the client about its limit, whether allowed or not
+		c.Header(ratelimit.HeaderLimit, strconv.Itoa(res.Limit.BurstCapacity))
+		c.Header(ratelimit.HeaderRemaining, strconv.Itoa(res.Remaining))
+
+		if !res.Allowed {
+			log.Warn("rate limit exceeded",
+				zap.String("client_ip", clientIP),
+				zap.String("principal", principal.ID),
+				zap.String("path", path),
+				zap.Float64("rate", res.Limit.RequestsPerSecond),
+				zap.Int("burst_capacity", res.Limit.BurstCapacity),
+			)
+			c.Header(ratelimit.HeaderRetryAfter, strconv.Itoa(ratelimit.RetryAfterSeconds(res.RetryAfter)))
+			rateLimitExceeded(c, res.Limit.RequestsPerSecond, res.Limit.BurstCapacity)
 			return
 		}
 
+		// Count the request against the quotas of identified clients
+		if identified {
+			exceeded, err := enforcer.UseQuota(ctx, principal)
+			if err != nil {
+				// Failing open, the enforcer returns Redis errors: allow request to proceed
+				log.Warn("rate limiter quota error, allowing request",
+					zap.String("principal", principal.ID),
+					zap.String("path", path),
+					zap.Error(err),
+				)
+				c.Next()
+				return
+			}
+			if exceeded != nil {
+				log.Warn("quota exceeded",
+					zap.String("principal", principal.ID),
+					zap.String("tier", string(principal.Tier)),
+					zap.String("path", path),
+					zap.String("period", exceeded.Period),
+					zap.Int64("limit", exceeded.Limit),
+					zap.Bool("unchecked", exceeded.Unchecked),
+				)
+				c.Header(ratelimit.HeaderRetryAfter, strconv.Itoa(ratelimit.RetryAfterSeconds(exceeded.RetryAfter)))
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+					"error":   "quota_exceeded",
+					"message": "Quota exceeded: " + exceeded.Description(),
+					"quota": gin.H{
+						"period":   exceeded.Period,
+						"limit":    exceeded.Limit,
+						"reset_at": exceeded.ResetAt,
+					},
+				})
+				return
+			}
+		}
+
 		c.Next()
 	}
 }
+
+// rateLimitExceeded aborts the request with 429 Too Many Requests.
+func rateLimitExceeded(c *gin.Context, requestsPerSecond float64, burstCapacity int) {
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":   "rate_limit_exceeded",
+		"message": fmt.Sprintf("Rate limit exceeded: %.2f requests/second (burst capacity: %d)", requestsPerSecond, burstCapacity),
+	})
+	c.Abort()
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"grpc-user-service/pkg/ratelimit"
+)
+
+// setupRateLimitRouter serves /v1/users and /v1/users/:id behind the rate limiter
+func setupRateLimitRouter(t *testing.T, config ratelimit.Config) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimiter(ratelimit.New(nil, config, zaptest.NewLogger(t)), zaptest.NewLogger(t)))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/v1/users", ok)
+	router.GET("/v1/users/:id", ok)
+	router.DELETE("/v1/users/:id", ok)
+	return router
+}
+
+// rateLimitRequest sends a request and returns the status code
+func rateLimitRequest(router *gin.Engine, method, path string) int {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w.Code
+}
+
+func TestRateLimiter_KeysOnRouteTemplate(t *testing.T) {
+	router := setupRateLimitRouter(t, ratelimit.Config{RequestsPerSecond: 1, BurstCapacity: 2, Enabled: true})
+
+	assert.Equal(t, http.StatusOK, rateLimitRequest(router, http.MethodGet, "/v1/users/1"))
+	assert.Equal(t, http.StatusOK, rateLimitRequest(router, http.MethodGet, "/v1/users/2"))
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitRequest(router, http.MethodGet, "/v1/users/3"),
+		"concrete URLs of one route share a bucket")
+	assert.Equal(t, http.StatusOK, rateLimitRequest(router, http.MethodGet, "/v1/users"))
+}
+
+func TestRateLimiter_RoutePolicies(t *testing.T) {
+	policy, err := ratelimit.NewPolicy([]ratelimit.Rule{
+		{Match: "DELETE /v1/users/:id", RequestsPerSecond: 1, BurstCapacity: 1},
+	})
+	require.NoError(t, err)
+	router := setupRateLimitRouter(t, ratelimit.Config{
+		RequestsPerSecond: 10,
+		BurstCapacity:     10,
+		Enabled:           true,
+		Policies:          ratelimit.Policies{HTTP: policy},
+	})
+
+	assert.Equal(t, http.StatusOK, rateLimitRequest(router, http.MethodDelete, "/v1/users/1"))
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitRequest(router, http.MethodDelete, "/v1/users/2"))
+	for range 5 {
+		assert.Equal(t, http.StatusOK, rateLimitRequest(router, http.MethodGet, "/v1/users/1"))
+	}
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	router := setupRateLimitRouter(t, ratelimit.Config{RequestsPerSecond: 1, BurstCapacity: 1})
+
+	for range 3 {
+		assert.Equal(t, http.StatusOK, rateLimitRequest(router, http.MethodGet, "/v1/users"))
+	}
+}
+
+func TestRateLimiter_Quotas(t *testing.T) {
+	router := setupRateLimitRouter(t, ratelimit.Config{
+		RequestsPerSecond: 10,
+		BurstCapacity:     10,
+		Enabled:           true,
+		Policies: ratelimit.Policies{Tiers: map[ratelimit.Tier]ratelimit.TierLimits{
+			ratelimit.TierFree: {MonthlyQuota: 1},
+		}},
+		APIKeys: ratelimit.NewAPIKeys([]ratelimit.APIKey{{ID: "hobby", Tier: ratelimit.TierFree, Secret: "s3cret"}}),
+	})
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+		req.Header.Set("X-API-Key", "s3cret")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request().Code)
+	w := request()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), `"error":"quota_exceeded"`)
+	assert.Contains(t, w.Body.String(), `"period":"monthly"`)
+
+	// Anonymous requests have no quota
+	assert.Equal(t, http.StatusOK, rateLimitRequest(router, http.MethodGet, "/v1/users"))
+}
+
+func TestRateLimiter_Headers(t *testing.T) {
+	router := setupRateLimitRouter(t, ratelimit.Config{RequestsPerSecond: 0.5, BurstCapacity: 2, Enabled: true})
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users", nil))
+		return w
+	}
+
+	w := request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Empty(t, w.Header().Get("Retry-After"))
+
+	request()
+	w = request()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+}
+
+func TestRateLimiter_RedisFailure(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	config := ratelimit.Config{
+		RequestsPerSecond: 10,
+		BurstCapacity:     10,
+		FailureMode:       ratelimit.FailClosed,
+		Enabled:           true,
+		Policies: ratelimit.Policies{Tiers: map[ratelimit.Tier]ratelimit.TierLimits{
+			ratelimit.TierFree: {DailyQuota: 100},
+		}},
+		APIKeys: ratelimit.NewAPIKeys([]ratelimit.APIKey{{ID: "hobby", Tier: ratelimit.TierFree, Secret: "s3cret"}}),
+	}
+	request := func(router *gin.Engine) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+		req.Header.Set("X-API-Key", "s3cret")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range []struct {
+		mode  ratelimit.FailureMode
+		code  int
+		limit string
+	}{
+		{ratelimit.FailOpen, http.StatusOK, ""},
+		{ratelimit.FailClosed, http.StatusTooManyRequests, "10"},
+		{ratelimit.FailLocal, http.StatusOK, "5"}, // Half of the burst of 10
+	} {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			config := config
+			config.FailureMode = tt.mode
+			config.LocalShare = 0.5
+			router := gin.New()
+			router.Use(RateLimiter(ratelimit.New(client, config, zaptest.NewLogger(t)), zaptest.NewLogger(t)))
+			router.GET("/v1/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+			mr.SetError("ERR injected failure")
+			t.Cleanup(func() { mr.SetError("") })
+
+			w := request(router)
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.limit, w.Header().Get("X-RateLimit-Limit"))
+		})
+	}
+}
@@ -1,11 +1,13 @@
 package router
 
 import (
+	"expvar"
 	"net/http"
 
 	"grpc-user-service/internal/adapter/gin/handler"
 	"grpc-user-service/internal/adapter/gin/middleware"
-	grpcmiddleware "grpc-user-service/internal/adapter/grpc/middleware"
+	"grpc-user-service/pkg/breaker"
+	"grpc-user-service/pkg/ratelimit"
 	redisclient "grpc-user-service/pkg/redis"
 
 	"github.com/gin-gonic/gin"
@@ -15,7 +17,9 @@ import (
 // SetupRouter configures and returns a Gin router with all routes and middleware
 func SetupRouter(
 	userHandler *handler.UserHandler,
-	rateLimiter *grpcmiddleware.RateLimiter,
+	adminHandler *handler.AdminHandler,
+	adminToken string,
+	rateLimiter *ratelimit.Enforcer,
 	redisClient *redisclient.Client,
 	log *zap.Logger,
 ) *gin.Engine {
@@ -27,16 +31,46 @@ func SetupRouter(
 	// Global middleware
 	router.Use(middleware.Recovery(log))
 	router.Use(middleware.Logger(log))
-	router.Use(middleware.RateLimiter(rateLimiter, redisClient.Client))
+	router.Use(middleware.RateLimiter(rateLimiter, log))
+	router.Use(middleware.Degraded())
 
-	// Health check endpoint
+	// Health check endpoint; an unreachable Redis or an open circuit degrades
+	// the service but does not make it unhealthy, since callers fall back
 	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
+		body := gin.H{
 			"status":  "healthy",
 			"service": "grpc-user-service-gin",
-		})
+		}
+		if redisClient != nil && !redisClient.Available() {
+			body["status"] = "degraded"
+			body["redis"] = "unavailable"
+		}
+		if states := breaker.States(); len(states) > 0 {
+			breakers := gin.H{}
+			for name, state := range states {
+				breakers[name] = state.String()
+				if state != breaker.Closed {
+					body["status"] = "degraded"
+				}
+			}
+			body["breakers"] = breakers
+		}
+		c.JSON(http.StatusOK, body)
 	})
 
+	// Admin routes are only served when an admin token is configured
+	if adminHandler != nil && adminToken != "" {
+		admin := router.Group("/admin", middleware.AdminAuth(adminToken, log))
+		{
+			admin.GET("/jobs", adminHandler.ListJobs)
+			admin.GET("/vars", gin.WrapH(expvar.Handler()))
+			admin.GET("/cache/users/:id", adminHandler.InspectCachedUser)
+			admin.DELETE("/cache/users/:id", adminHandler.EvictCachedUser)
+			admin.DELETE("/cache/users", adminHandler.EvictCachePattern)
+			admin.POST("/cache/flush", adminHandler.FlushCache)
+		}
+	}
+
 	// API v1 routes
 	v1 := router.Group("/v1")
 	{
@@ -45,8 +79,30 @@ func SetupRouter(
 			users.POST("", userHandler.CreateUser)
 			users.GET("", userHandler.ListUsers)
 			users.GET("/:id", userHandler.GetUser)
+			users.GET("/by-username/:username", userHandler.GetUserByUsername)
+			users.GET("/by-identity/:provider/:subject", userHandler.GetUserByIdentity)
 			users.PUT("/:id", userHandler.UpdateUser)
 			users.DELETE("/:id", userHandler.DeleteUser)
+
+			users.GET("/:id/emails", userHandler.ListUserEmails)
+			users.POST("/:id/emails", userHandler.AddUserEmail)
+			users.PUT("/:id/emails/primary", userHandler.SetPrimaryUserEmail)
+			users.POST("/:id/emails/verify", userHandler.VerifyUserEmail)
+			users.DELETE("/:id/emails/:email", userHandler.RemoveUserEmail)
+
+			users.POST("/:id/identities", userHandler.LinkIdentity)
+			users.DELETE("/:id/identities/:provider/:subject", userHandler.UnlinkIdentity)
+		}
+
+		// Exporting and erasing a user's personal data, and issuing email
+		// verification tokens for delivery, are reserved for admins
+		if adminToken != "" {
+			privacy := users.Group("", middleware.AdminAuth(adminToken, log))
+			{
+				privacy.GET("/:id/export", userHandler.ExportUserData)
+				privacy.POST("/:id/erase", userHandler.EraseUser)
+				privacy.POST("/:id/emails/verification", userHandler.IssueUserEmailVerification)
+			}
 		}
 	}
 
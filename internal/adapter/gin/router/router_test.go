@@ -0,0 +1,83 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"grpc-user-service/internal/adapter/gin/handler"
+	"grpc-user-service/internal/usecase/user"
+	"grpc-user-service/pkg/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+// privacyUsecase serves the privacy endpoints; any other call panics.
+type privacyUsecase struct {
+	user.Usecase
+}
+
+func (privacyUsecase) ExportUserData(_ context.Context, in user.ExportUserDataRequest) (*user.ExportUserDataResponse, error) {
+	return &user.ExportUserDataResponse{FormatVersion: user.ExportFormatVersion, Profile: user.GetUserResponse{ID: in.UserID}}, nil
+}
+
+func (privacyUsecase) EraseUser(_ context.Context, in user.EraseUserRequest) (*user.EraseUserResponse, error) {
+	return &user.EraseUserResponse{UserID: in.UserID}, nil
+}
+
+func (privacyUsecase) IssueUserEmailVerification(_ context.Context, in user.IssueUserEmailVerificationRequest) (*user.EmailVerificationResponse, error) {
+	return &user.EmailVerificationResponse{UserID: in.UserID, Email: in.Email, Token: "token"}, nil
+}
+
+func setupTestRouter(t *testing.T, adminToken string) http.Handler {
+	log := zaptest.NewLogger(t)
+	return SetupRouter(
+		handler.NewUserHandler(privacyUsecase{}, log),
+		nil,
+		adminToken,
+		ratelimit.New(nil, ratelimit.Config{}, log),
+		nil,
+		log,
+	)
+}
+
+func TestSetupRouter_PrivacyRoutesRequireAdminToken(t *testing.T) {
+	r := setupTestRouter(t, "secret")
+
+	for _, route := range []struct{ method, path, body string }{
+		{http.MethodGet, "/v1/users/1/export", ""},
+		{http.MethodPost, "/v1/users/1/erase", ""},
+		{http.MethodPost, "/v1/users/1/emails/verification", `{"email":"john@example.com"}`},
+	} {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(route.method, route.path, strings.NewReader(route.body)))
+			assert.Equal(t, http.StatusUnauthorized, w.Code, "unauthenticated")
+
+			w = httptest.NewRecorder()
+			req := httptest.NewRequest(route.method, route.path, strings.NewReader(route.body))
+			req.Header.Set("Authorization", "Bearer wrong")
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code, "wrong token")
+
+			w = httptest.NewRecorder()
+			req = httptest.NewRequest(route.method, route.path, strings.NewReader(route.body))
+			req.Header.Set("Authorization", "Bearer secret")
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code, "admin token")
+		})
+	}
+}
+
+func TestSetupRouter_PrivacyRoutesDisabledWithoutAdminToken(t *testing.T) {
+	r := setupTestRouter(t, "")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/1/export", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminAuthInterceptor(t *testing.T) {
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+	call := func(interceptor grpc.UnaryServerInterceptor, method, authorization string) (any, error) {
+		ctx := context.Background()
+		if authorization != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", authorization))
+		}
+		return interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+
+	interceptor := AdminAuthInterceptor("secret", AdminMethods, zaptest.NewLogger(t))
+
+	for _, method := range AdminMethods {
+		_, err := call(interceptor, method, "")
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), "%s without a token", method)
+
+		_, err = call(interceptor, method, "Bearer wrong")
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), "%s with a wrong token", method)
+
+		resp, err := call(interceptor, method, "Bearer secret")
+		require.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+	}
+
+	// Other methods need no token
+	resp, err := call(interceptor, "/user.UserService/GetUser", "")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+}
+
+func TestAdminAuthInterceptor_EmptyTokenRejectsAll(t *testing.T) {
+	interceptor := AdminAuthInterceptor("", AdminMethods, zaptest.NewLogger(t))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "))
+
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: AdminMethods[0]}, func(context.Context, any) (any, error) {
+		return "ok", nil
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"grpc-user-service/pkg/degraded"
+)
+
+// DegradedInterceptor returns a gRPC unary interceptor that sets the
+// x-degraded response header when a lower layer reports that the response is
+// served in a degraded mode. The gRPC gateway forwards it to HTTP clients as
+// Grpc-Metadata-X-Degraded.
+func DegradedInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx = degraded.WithReporter(ctx, func(reason string) {
+			// Only fails once headers were sent, which unary handlers do on return
+			_ = grpc.SetHeader(ctx, metadata.Pairs(degraded.MetadataKey, reason))
+		})
+		return handler(ctx, req)
+	}
+}
@@ -3,39 +3,47 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"grpc-user-service/pkg/ratelimit"
 )
 
-// RateLimiterConfig holds configuration for the Token Bucket rate limiter.
-type RateLimiterConfig struct {
-	RequestsPerSecond float64 // Token refill rate (tokens per second)
-	BurstCapacity     int     // Maximum tokens in bucket (allows burst traffic)
-	Enabled           bool
-}
+// RateLimiterConfig holds configuration for the rate limiter.
+type RateLimiterConfig = ratelimit.Config
 
-// RateLimiter implements gRPC rate limiting using Token Bucket algorithm with Redis.
+// RateLimiter implements gRPC rate limiting with Redis, by token bucket,
+// sliding window or GCRA as configured per limit.
 type RateLimiter struct {
-	client *redis.Client
-	config RateLimiterConfig
-	log    *zap.Logger
+	enforcer *ratelimit.Enforcer
+	log      *zap.Logger
 }
 
-// NewRateLimiter creates a new rate limiter interceptor.
+// NewRateLimiter creates a new rate limiter interceptor. With a nil client
+// the limiter state is kept in memory, and limits apply per replica.
 func NewRateLimiter(client *redis.Client, config RateLimiterConfig, log *zap.Logger) *RateLimiter {
 	return &RateLimiter{
-		client: client,
-		config: config,
-		log:    log,
+		enforcer: ratelimit.New(client, config, log),
+		log:      log,
 	}
 }
 
+// Enforcer returns the limiter core, shared with the HTTP gateway.
+func (rl *RateLimiter) Enforcer() *ratelimit.Enforcer {
+	return rl.enforcer
+}
+
 // UnaryInterceptor returns a gRPC unary interceptor for rate limiting.
 func (rl *RateLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
 	return func(
@@ -45,90 +53,129 @@ func (rl *RateLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
 		handler grpc.UnaryHandler,
 	) (any, error) {
 		// Skip rate limiting if disabled
-		if !rl.config.Enabled {
+		if !rl.enforcer.Enabled() {
 			return handler(ctx, req)
 		}
 
 		// Get client IP from peer info
 		clientIP := rl.getClientIP(ctx)
 
-		// Create rate limit key: ratelimit:tb:{method}:{ip}
-		key := fmt.Sprintf("ratelimit:tb:%s:%s", info.FullMethod, clientIP)
-
-		// Token Bucket algorithm implemented in Lua for atomicity
-		// Data structure: {last_refill_time, current_tokens}
-		luaScript := `
-			local key = KEYS[1]
-			local rate = tonumber(ARGV[1])         -- tokens per second
-			local capacity = tonumber(ARGV[2])     -- max tokens in bucket
-			local now = tonumber(ARGV[3])          -- current timestamp
-			local requested = tonumber(ARGV[4])    -- tokens requested (always 1)
-			
-			-- Get current bucket state
-			local bucket = redis.call('HMGET', key, 'last_refill', 'tokens')
-			local last_refill = tonumber(bucket[1]) or now
-			local tokens = tonumber(bucket[2]) or capacity
-			
-			-- Calculate tokens to add based on elapsed time
-			local elapsed = math.max(0, now - last_refill)
-			local tokens_to_add = elapsed * rate
-			tokens = math.min(capacity, tokens + tokens_to_add)
-			
-			-- Try to consume requested tokens
-			if tokens >= requested then
-				-- Success: consume token
-				tokens = tokens - requested
-				redis.call('HMSET', key, 'last_refill', now, 'tokens', tokens)
-				redis.call('EXPIRE', key, 60)  -- Keep bucket for 60 seconds
-				return 1  -- Allow request
-			else
-				-- Failure: not enough tokens
-				-- Still update last_refill to prevent token accumulation during rate limit
-				redis.call('HMSET', key, 'last_refill', now, 'tokens', tokens)
-				redis.call('EXPIRE', key, 60)
-				return 0  -- Deny request
-			end
-		`
-
-		// Execute Lua script
-		// Get current timestamp in seconds (floating point for precision)
-		now := float64(rl.client.Time(ctx).Val().Unix())
-
-		allowed, err := rl.client.Eval(ctx, luaScript, []string{key},
-			rl.config.RequestsPerSecond,
-			rl.config.BurstCapacity,
-			now,
-			1, // Always request 1 token
-		).Int64()
+		// Identified clients are limited by principal instead of IP
+		principal, identified := rl.enforcer.Identify(ctx, rl.getAPIKey(ctx))
+		subject := clientIP
+		if identified {
+			subject = principal.ID
+		}
+
+		// Create rate limit key: {method}:{subject}, stored as ratelimit:tb:{method}:{subject}
+		key := fmt.Sprintf("%s:%s", info.FullMethod, subject)
 
+		config := rl.enforcer.Config()
+		limit := config.GRPCLimit(info.FullMethod, principal)
+		res, err := rl.enforcer.Allow(ctx, key, limit)
 		if err != nil {
-			// On Redis error, allow request to proceed (fail open)
+			// Failing open, the enforcer returns Redis errors: allow request to proceed
 			rl.log.Warn("rate limiter redis error, allowing request",
 				zap.String("client_ip", clientIP),
+				zap.String("principal", principal.ID),
 				zap.String("method", info.FullMethod),
 				zap.Error(err),
 			)
 			return handler(ctx, req)
 		}
 
+		// Tell the client about its limit, whether allowed or not
+		trailer := metadata.Pairs(
+			ratelimit.HeaderLimit, strconv.Itoa(res.Limit.BurstCapacity),
+			ratelimit.HeaderRemaining, strconv.Itoa(res.Remaining),
+		)
+
 		// Check if request is allowed
-		if allowed == 0 {
+		if !res.Allowed {
 			rl.log.Warn("rate limit exceeded",
 				zap.String("client_ip", clientIP),
+				zap.String("principal", principal.ID),
 				zap.String("method", info.FullMethod),
-				zap.Float64("rate", rl.config.RequestsPerSecond),
-				zap.Int("burst_capacity", rl.config.BurstCapacity),
+				zap.Float64("rate", res.Limit.RequestsPerSecond),
+				zap.Int("burst_capacity", res.Limit.BurstCapacity),
 			)
-			return nil, status.Errorf(codes.ResourceExhausted,
+			setRetryTrailer(ctx, trailer, res.RetryAfter)
+			st := status.Newf(codes.ResourceExhausted,
 				"rate limit exceeded: %.2f requests/second (burst capacity: %d)",
-				rl.config.RequestsPerSecond, rl.config.BurstCapacity)
+				res.Limit.RequestsPerSecond, res.Limit.BurstCapacity)
+			return nil, withDetails(st, retryInfo(res.RetryAfter))
+		}
+
+		// Count the request against the quotas of identified clients
+		if identified {
+			exceeded, err := rl.enforcer.UseQuota(ctx, principal)
+			if err != nil {
+				// Failing open, the enforcer returns Redis errors: allow request to proceed
+				rl.log.Warn("rate limiter quota error, allowing request",
+					zap.String("principal", principal.ID),
+					zap.String("method", info.FullMethod),
+					zap.Error(err),
+				)
+				return handler(ctx, req)
+			}
+			if exceeded != nil {
+				rl.log.Warn("quota exceeded",
+					zap.String("principal", principal.ID),
+					zap.String("tier", string(principal.Tier)),
+					zap.String("method", info.FullMethod),
+					zap.String("period", exceeded.Period),
+					zap.Int64("limit", exceeded.Limit),
+					zap.Bool("unchecked", exceeded.Unchecked),
+				)
+				setRetryTrailer(ctx, trailer, exceeded.RetryAfter)
+				st := status.New(codes.ResourceExhausted, "quota exceeded: "+exceeded.Description())
+				return nil, withDetails(st, &errdetails.QuotaFailure{
+					Violations: []*errdetails.QuotaFailure_Violation{{
+						Subject:     exceeded.Principal,
+						Description: exceeded.Description(),
+					}},
+				}, retryInfo(exceeded.RetryAfter))
+			}
 		}
 
 		// Allow request
+		_ = grpc.SetTrailer(ctx, trailer)
 		return handler(ctx, req)
 	}
 }
 
+// setRetryTrailer adds the Retry-After seconds to trailer and sets it on the
+// call. Calls outside a gRPC server, as in tests, have no trailer to set.
+func setRetryTrailer(ctx context.Context, trailer metadata.MD, retryAfter time.Duration) {
+	trailer.Set(ratelimit.HeaderRetryAfter, strconv.Itoa(ratelimit.RetryAfterSeconds(retryAfter)))
+	_ = grpc.SetTrailer(ctx, trailer)
+}
+
+// retryInfo returns the RetryInfo detail telling clients when to retry.
+func retryInfo(retryAfter time.Duration) *errdetails.RetryInfo {
+	return &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}
+}
+
+// withDetails returns the error of st carrying details, or without them if
+// they cannot be encoded.
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// getAPIKey returns the API key sent in the x-api-key metadata, if any.
+func (rl *RateLimiter) getAPIKey(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if keys := md.Get("x-api-key"); len(keys) > 0 {
+			return keys[0]
+		}
+	}
+	return ""
+}
+
 // getClientIP extracts the client IP address from the gRPC context.
 func (rl *RateLimiter) getClientIP(ctx context.Context) string {
 	// Try to get IP from X-Forwarded-For header (for requests through gateway)
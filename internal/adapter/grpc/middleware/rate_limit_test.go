@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"testing"
+	"time"
 
 	"net"
 
@@ -11,11 +12,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+
+	"grpc-user-service/pkg/ratelimit"
 )
 
 // setupTestRedis creates a miniredis instance for testing
@@ -269,3 +273,262 @@ func TestRateLimiter_WindowExpiry(t *testing.T) {
 	assert.Greater(t, ttl.Seconds(), 0.0)
 	assert.LessOrEqual(t, ttl.Seconds(), 60.0) // TTL should be ~60 seconds
 }
+
+func TestRateLimiter_WithoutRedis(t *testing.T) {
+	rl := NewRateLimiter(nil, RateLimiterConfig{RequestsPerSecond: 1, BurstCapacity: 2, Enabled: true}, zaptest.NewLogger(t))
+	interceptor := rl.UnaryInterceptor()
+
+	addr, _ := net.ResolveTCPAddr("tcp", "127.0.0.1:12345")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+
+	for range 2 {
+		_, err := interceptor(ctx, nil, info, mockHandler)
+		require.NoError(t, err)
+	}
+	_, err := interceptor(ctx, nil, info, mockHandler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimiter_Policies(t *testing.T) {
+	client, _ := setupTestRedis(t)
+
+	policy, err := ratelimit.NewPolicy([]ratelimit.Rule{
+		{Match: "/user.UserService/DeleteUser", RequestsPerSecond: 1, BurstCapacity: 1},
+	})
+	require.NoError(t, err)
+	config := RateLimiterConfig{
+		RequestsPerSecond: 10,
+		BurstCapacity:     10,
+		Enabled:           true,
+		Policies:          ratelimit.Policies{GRPC: policy},
+	}
+
+	rl := NewRateLimiter(client, config, zaptest.NewLogger(t))
+	interceptor := rl.UnaryInterceptor()
+
+	addr, _ := net.ResolveTCPAddr("tcp", "127.0.0.1:12345")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+
+	// DeleteUser has its own limit of one request
+	deleteInfo := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/DeleteUser"}
+	_, err = interceptor(ctx, nil, deleteInfo, mockHandler)
+	require.NoError(t, err)
+	_, err = interceptor(ctx, nil, deleteInfo, mockHandler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// Other methods get the global limit
+	listInfo := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/ListUsers"}
+	for range 10 {
+		_, err = interceptor(ctx, nil, listInfo, mockHandler)
+		require.NoError(t, err)
+	}
+}
+
+func TestRateLimiter_APIKeys(t *testing.T) {
+	client, _ := setupTestRedis(t)
+
+	config := RateLimiterConfig{
+		RequestsPerSecond: 1,
+		BurstCapacity:     1,
+		Enabled:           true,
+		Policies: ratelimit.Policies{Tiers: map[ratelimit.Tier]ratelimit.TierLimits{
+			ratelimit.TierPartner: {RequestsPerSecond: 10, BurstCapacity: 10, DailyQuota: 3},
+		}},
+		APIKeys: ratelimit.NewAPIKeys([]ratelimit.APIKey{{ID: "acme", Tier: ratelimit.TierPartner, Secret: "s3cret"}}),
+	}
+
+	rl := NewRateLimiter(client, config, zaptest.NewLogger(t))
+	interceptor := rl.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+
+	// Requests with the key from different IPs share the partner bucket and quota
+	for i, ip := range []string{"10.0.0.1:1", "10.0.0.2:1", "10.0.0.3:1"} {
+		addr, _ := net.ResolveTCPAddr("tcp", ip)
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", "s3cret"))
+		_, err := interceptor(ctx, nil, info, mockHandler)
+		require.NoError(t, err, "request %d", i)
+	}
+
+	addr, _ := net.ResolveTCPAddr("tcp", "10.0.0.4:1")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	_, err := interceptor(metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", "s3cret")), nil, info, mockHandler)
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 2)
+	failure, ok := st.Details()[0].(*errdetails.QuotaFailure)
+	require.True(t, ok)
+	require.Len(t, failure.Violations, 1)
+	assert.Equal(t, "apikey:acme", failure.Violations[0].Subject)
+	assert.Contains(t, failure.Violations[0].Description, "daily quota of 3 requests exhausted")
+
+	// Anonymous requests from that IP are limited by IP
+	_, err = interceptor(ctx, nil, info, mockHandler)
+	require.NoError(t, err)
+	_, err = interceptor(ctx, nil, info, mockHandler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimiter_TierUnderWildcardRule(t *testing.T) {
+	client, _ := setupTestRedis(t)
+
+	policy, err := ratelimit.NewPolicy([]ratelimit.Rule{
+		{Match: "/user.UserService/*", RequestsPerSecond: 1, BurstCapacity: 1, Tiers: map[ratelimit.Tier]ratelimit.TierOverride{
+			ratelimit.TierPartner: {},
+		}},
+	})
+	require.NoError(t, err)
+	config := RateLimiterConfig{
+		RequestsPerSecond: 10,
+		BurstCapacity:     10,
+		Enabled:           true,
+		Policies: ratelimit.Policies{GRPC: policy, Tiers: map[ratelimit.Tier]ratelimit.TierLimits{
+			ratelimit.TierPartner: {RequestsPerSecond: 5, BurstCapacity: 5},
+		}},
+		APIKeys: ratelimit.NewAPIKeys([]ratelimit.APIKey{{ID: "acme", Tier: ratelimit.TierPartner, Secret: "s3cret"}}),
+	}
+
+	rl := NewRateLimiter(client, config, zaptest.NewLogger(t))
+	interceptor := rl.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+	addr, _ := net.ResolveTCPAddr("tcp", "10.0.0.1:1")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+
+	// The partner key gets the burst of its tier on a method the rule covers
+	partnerCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", "s3cret"))
+	for i := range 5 {
+		_, err := interceptor(partnerCtx, nil, info, mockHandler)
+		require.NoError(t, err, "request %d", i)
+	}
+	_, err = interceptor(partnerCtx, nil, info, mockHandler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// Anonymous clients are held to the rule
+	_, err = interceptor(ctx, nil, info, mockHandler)
+	require.NoError(t, err)
+	_, err = interceptor(ctx, nil, info, mockHandler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+// trailerStream records the trailers set by an interceptor
+type trailerStream struct {
+	grpc.ServerTransportStream
+	trailer metadata.MD
+}
+
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestRateLimiter_RetryHints(t *testing.T) {
+	client, _ := setupTestRedis(t)
+
+	config := RateLimiterConfig{
+		RequestsPerSecond: 0.5,
+		BurstCapacity:     1,
+		Enabled:           true,
+	}
+	rl := NewRateLimiter(client, config, zaptest.NewLogger(t))
+	interceptor := rl.UnaryInterceptor()
+
+	addr, _ := net.ResolveTCPAddr("tcp", "127.0.0.1:12345")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+
+	stream := &trailerStream{}
+	_, err := interceptor(grpc.NewContextWithServerTransportStream(ctx, stream), nil, info, mockHandler)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, stream.trailer.Get("x-ratelimit-limit"))
+	assert.Equal(t, []string{"0"}, stream.trailer.Get("x-ratelimit-remaining"))
+	assert.Empty(t, stream.trailer.Get("retry-after"))
+
+	// A token takes two seconds to refill at half a token per second
+	stream = &trailerStream{}
+	_, err = interceptor(grpc.NewContextWithServerTransportStream(ctx, stream), nil, info, mockHandler)
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Equal(t, []string{"0"}, stream.trailer.Get("x-ratelimit-remaining"))
+	assert.Equal(t, []string{"2"}, stream.trailer.Get("retry-after"))
+
+	require.Len(t, st.Details(), 1)
+	retry, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.InDelta(t, 2*time.Second, retry.RetryDelay.AsDuration(), float64(100*time.Millisecond))
+}
+
+func TestRateLimiter_RedisFailure(t *testing.T) {
+	addr, _ := net.ResolveTCPAddr("tcp", "127.0.0.1:12345")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+
+	tests := []struct {
+		mode    ratelimit.FailureMode
+		allowed int // Requests allowed out of 3 while Redis fails
+	}{
+		{ratelimit.FailOpen, 3},
+		{ratelimit.FailClosed, 0},
+		{ratelimit.FailLocal, 1}, // Half of the burst of 2
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			client, mr := setupTestRedis(t)
+			rl := NewRateLimiter(client, RateLimiterConfig{
+				RequestsPerSecond: 1,
+				BurstCapacity:     2,
+				FailureMode:       tt.mode,
+				LocalShare:        0.5,
+				Enabled:           true,
+			}, zaptest.NewLogger(t))
+			interceptor := rl.UnaryInterceptor()
+			mr.SetError("ERR injected failure")
+
+			allowed := 0
+			for range 3 {
+				_, err := interceptor(ctx, nil, info, mockHandler)
+				if err == nil {
+					allowed++
+					continue
+				}
+				assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+			}
+			assert.Equal(t, tt.allowed, allowed)
+		})
+	}
+}
+
+func TestRateLimiter_QuotaRedisFailure(t *testing.T) {
+	client, mr := setupTestRedis(t)
+	rl := NewRateLimiter(client, RateLimiterConfig{
+		RequestsPerSecond: 10,
+		BurstCapacity:     10,
+		FailureMode:       ratelimit.FailLocal,
+		LocalShare:        0.5,
+		Enabled:           true,
+		Policies: ratelimit.Policies{Tiers: map[ratelimit.Tier]ratelimit.TierLimits{
+			ratelimit.TierFree: {DailyQuota: 4},
+		}},
+		APIKeys: ratelimit.NewAPIKeys([]ratelimit.APIKey{{ID: "hobby", Tier: ratelimit.TierFree, Secret: "s3cret"}}),
+	}, zaptest.NewLogger(t))
+	interceptor := rl.UnaryInterceptor()
+	mr.SetError("ERR injected failure")
+
+	addr, _ := net.ResolveTCPAddr("tcp", "127.0.0.1:12345")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", "s3cret"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+
+	// Failing local, quotas are counted in memory at half their limit, and
+	// the trailer reports the burst actually enforced
+	for i := range 2 {
+		stream := &trailerStream{}
+		_, err := interceptor(grpc.NewContextWithServerTransportStream(ctx, stream), nil, info, mockHandler)
+		require.NoError(t, err, "request %d", i)
+		assert.Equal(t, []string{"5"}, stream.trailer.Get("x-ratelimit-limit"))
+	}
+	_, err := interceptor(ctx, nil, info, mockHandler)
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Contains(t, st.Message(), "quota exceeded")
+}
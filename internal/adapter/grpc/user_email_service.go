@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "grpc-user-service/api/gen/go/user"
+	"grpc-user-service/internal/usecase/user"
+)
+
+// ListUserEmails handles the gRPC ListUserEmails request.
+func (s *UserServiceServer) ListUserEmails(ctx context.Context, req *pb.ListUserEmailsRequest) (*pb.UserEmailsResponse, error) {
+	s.log.Info("gRPC ListUserEmails request", zap.Int64("user_id", req.UserId))
+	ucRequest := user.ListUserEmailsRequest{
+		UserID: req.UserId,
+	}
+	resp, err := s.uc.ListUserEmails(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC ListUserEmails failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	return toPBUserEmails(resp), nil
+}
+
+// AddUserEmail handles the gRPC AddUserEmail request.
+func (s *UserServiceServer) AddUserEmail(ctx context.Context, req *pb.AddUserEmailRequest) (*pb.UserEmailsResponse, error) {
+	s.log.Info("gRPC AddUserEmail request", zap.Int64("user_id", req.UserId), zap.String("email", req.Email))
+	ucRequest := user.AddUserEmailRequest{
+		UserID: req.UserId,
+		Email:  req.Email,
+	}
+	resp, err := s.uc.AddUserEmail(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC AddUserEmail failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	return toPBUserEmails(resp), nil
+}
+
+// RemoveUserEmail handles the gRPC RemoveUserEmail request.
+func (s *UserServiceServer) RemoveUserEmail(ctx context.Context, req *pb.RemoveUserEmailRequest) (*pb.UserEmailsResponse, error) {
+	s.log.Info("gRPC RemoveUserEmail request", zap.Int64("user_id", req.UserId), zap.String("email", req.Email))
+	ucRequest := user.RemoveUserEmailRequest{
+		UserID: req.UserId,
+		Email:  req.Email,
+	}
+	resp, err := s.uc.RemoveUserEmail(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC RemoveUserEmail failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	return toPBUserEmails(resp), nil
+}
+
+// SetPrimaryUserEmail handles the gRPC SetPrimaryUserEmail request.
+func (s *UserServiceServer) SetPrimaryUserEmail(ctx context.Context, req *pb.SetPrimaryUserEmailRequest) (*pb.UserEmailsResponse, error) {
+	s.log.Info("gRPC SetPrimaryUserEmail request", zap.Int64("user_id", req.UserId), zap.String("email", req.Email))
+	ucRequest := user.SetPrimaryUserEmailRequest{
+		UserID: req.UserId,
+		Email:  req.Email,
+	}
+	resp, err := s.uc.SetPrimaryUserEmail(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC SetPrimaryUserEmail failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	return toPBUserEmails(resp), nil
+}
+
+// IssueUserEmailVerification handles the gRPC IssueUserEmailVerification request.
+func (s *UserServiceServer) IssueUserEmailVerification(ctx context.Context, req *pb.IssueUserEmailVerificationRequest) (*pb.EmailVerificationResponse, error) {
+	s.log.Info("gRPC IssueUserEmailVerification request", zap.Int64("user_id", req.UserId), zap.String("email", req.Email))
+	ucRequest := user.IssueUserEmailVerificationRequest{
+		UserID: req.UserId,
+		Email:  req.Email,
+	}
+	resp, err := s.uc.IssueUserEmailVerification(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC IssueUserEmailVerification failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	return &pb.EmailVerificationResponse{
+		UserId:    resp.UserID,
+		Email:     resp.Email,
+		Token:     resp.Token,
+		ExpiresAt: timestamppb.New(resp.ExpiresAt),
+	}, nil
+}
+
+// VerifyUserEmail handles the gRPC VerifyUserEmail request.
+func (s *UserServiceServer) VerifyUserEmail(ctx context.Context, req *pb.VerifyUserEmailRequest) (*pb.UserEmailsResponse, error) {
+	s.log.Info("gRPC VerifyUserEmail request", zap.Int64("user_id", req.UserId), zap.String("email", req.Email))
+	ucRequest := user.VerifyUserEmailRequest{
+		UserID: req.UserId,
+		Email:  req.Email,
+		Token:  req.Token,
+	}
+	resp, err := s.uc.VerifyUserEmail(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC VerifyUserEmail failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	return toPBUserEmails(resp), nil
+}
+
+// toPBUserEmails converts a usecase UserEmailsResponse into its protobuf representation.
+func toPBUserEmails(resp *user.UserEmailsResponse) *pb.UserEmailsResponse {
+	emails := make([]*pb.UserEmail, len(resp.Emails))
+	for i, e := range resp.Emails {
+		emails[i] = &pb.UserEmail{
+			Email:    e.Email,
+			Primary:  e.Primary,
+			Verified: e.Verified,
+		}
+		if !e.VerifiedAt.IsZero() {
+			emails[i].VerifiedAt = timestamppb.New(e.VerifiedAt)
+		}
+	}
+
+	return &pb.UserEmailsResponse{
+		UserId: resp.UserID,
+		Emails: emails,
+	}
+}
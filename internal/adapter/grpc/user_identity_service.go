@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	pb "grpc-user-service/api/gen/go/user"
+	"grpc-user-service/internal/usecase/user"
+)
+
+// LinkIdentity handles the gRPC LinkIdentity request.
+func (s *UserServiceServer) LinkIdentity(ctx context.Context, req *pb.LinkIdentityRequest) (*pb.LinkIdentityResponse, error) {
+	s.log.Info("gRPC LinkIdentity request", zap.Int64("user_id", req.UserId), zap.String("provider", req.Provider))
+	ucRequest := user.LinkIdentityRequest{
+		UserID:   req.UserId,
+		Provider: req.Provider,
+		Subject:  req.Subject,
+	}
+	resp, err := s.uc.LinkIdentity(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC LinkIdentity failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	return &pb.LinkIdentityResponse{
+		UserId:   resp.UserID,
+		Provider: resp.Provider,
+		Subject:  resp.Subject,
+	}, nil
+}
+
+// UnlinkIdentity handles the gRPC UnlinkIdentity request.
+func (s *UserServiceServer) UnlinkIdentity(ctx context.Context, req *pb.UnlinkIdentityRequest) (*pb.UnlinkIdentityResponse, error) {
+	s.log.Info("gRPC UnlinkIdentity request", zap.Int64("user_id", req.UserId), zap.String("provider", req.Provider))
+	ucRequest := user.UnlinkIdentityRequest{
+		UserID:   req.UserId,
+		Provider: req.Provider,
+		Subject:  req.Subject,
+	}
+	resp, err := s.uc.UnlinkIdentity(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC UnlinkIdentity failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	return &pb.UnlinkIdentityResponse{
+		UserId: resp.UserID,
+	}, nil
+}
+
+// GetUserByIdentity handles the gRPC GetUserByIdentity request.
+func (s *UserServiceServer) GetUserByIdentity(ctx context.Context, req *pb.GetUserByIdentityRequest) (*pb.GetUserResponse, error) {
+	s.log.Info("gRPC GetUserByIdentity request", zap.String("provider", req.Provider))
+	ucRequest := user.GetUserByIdentityRequest{
+		Provider: req.Provider,
+		Subject:  req.Subject,
+	}
+	u, err := s.uc.GetUserByIdentity(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC GetUserByIdentity failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	return toPBUser(u), nil
+}
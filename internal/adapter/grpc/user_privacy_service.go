@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "grpc-user-service/api/gen/go/user"
+	"grpc-user-service/internal/usecase/user"
+)
+
+// ExportUserData handles the gRPC ExportUserData request.
+func (s *UserServiceServer) ExportUserData(ctx context.Context, req *pb.ExportUserDataRequest) (*pb.ExportUserDataResponse, error) {
+	s.log.Info("gRPC ExportUserData request", zap.Int64("user_id", req.UserId))
+	ucRequest := user.ExportUserDataRequest{
+		UserID: req.UserId,
+	}
+	resp, err := s.uc.ExportUserData(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC ExportUserData failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	identities := make([]*pb.LinkedIdentity, len(resp.Identities))
+	for i, id := range resp.Identities {
+		identities[i] = &pb.LinkedIdentity{
+			Provider:  id.Provider,
+			Subject:   id.Subject,
+			CreatedAt: timestamppb.New(id.CreatedAt),
+		}
+	}
+	entries := make([]*pb.AuditEntry, len(resp.AuditEntries))
+	for i, e := range resp.AuditEntries {
+		entries[i] = &pb.AuditEntry{
+			Action:    e.Action,
+			Details:   e.Details,
+			CreatedAt: timestamppb.New(e.CreatedAt),
+		}
+	}
+
+	return &pb.ExportUserDataResponse{
+		FormatVersion: int32(resp.FormatVersion),
+		ExportedAt:    timestamppb.New(resp.ExportedAt),
+		Profile:       toPBUser(&resp.Profile),
+		Emails:        toPBUserEmails(&user.UserEmailsResponse{UserID: req.UserId, Emails: resp.Emails}).Emails,
+		Identities:    identities,
+		AuditEntries:  entries,
+	}, nil
+}
+
+// EraseUser handles the gRPC EraseUser request.
+func (s *UserServiceServer) EraseUser(ctx context.Context, req *pb.EraseUserRequest) (*pb.EraseUserResponse, error) {
+	s.log.Info("gRPC EraseUser request", zap.Int64("user_id", req.UserId))
+	ucRequest := user.EraseUserRequest{
+		UserID: req.UserId,
+		Reason: req.Reason,
+	}
+	resp, err := s.uc.EraseUser(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC EraseUser failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	return &pb.EraseUserResponse{
+		UserId:   resp.UserID,
+		ErasedAt: timestamppb.New(resp.ErasedAt),
+	}, nil
+}
@@ -47,8 +47,15 @@ func mapError(err error) error {
 func (s *UserServiceServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
 	s.log.Info("gRPC CreateUser request", zap.String("name", req.Name), zap.String("email", req.Email))
 	ucRequest := user.CreateUserRequest{
-		Name:  req.GetName(),
-		Email: req.GetEmail(),
+		Username:    req.GetUsername(),
+		Name:        req.GetName(),
+		GivenName:   req.GetGivenName(),
+		FamilyName:  req.GetFamilyName(),
+		DisplayName: req.GetDisplayName(),
+		Email:       req.GetEmail(),
+		Phone:    req.GetPhone(),
+		Locale:   req.GetLocale(),
+		Timezone: req.GetTimezone(),
 	}
 	id, err := s.uc.CreateUser(ctx, ucRequest)
 	if err != nil {
@@ -65,9 +72,17 @@ func (s *UserServiceServer) CreateUser(ctx context.Context, req *pb.CreateUserRe
 func (s *UserServiceServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
 	s.log.Info("gRPC UpdateUser request", zap.Int64("id", req.Id), zap.String("name", req.Name), zap.String("email", req.Email))
 	ucRequest := user.UpdateUserRequest{
-		ID:    req.Id,
-		Name:  req.GetName(),
-		Email: req.GetEmail(),
+		ID:          req.Id,
+		Username:    req.GetUsername(),
+		Name:        req.GetName(),
+		GivenName:   req.GetGivenName(),
+		FamilyName:  req.GetFamilyName(),
+		DisplayName: req.GetDisplayName(),
+		Email:       req.GetEmail(),
+		Phone:    req.GetPhone(),
+		Locale:   req.GetLocale(),
+		Timezone: req.GetTimezone(),
+		Clear:    req.GetClear(),
 	}
 	id, err := s.uc.UpdateUser(ctx, ucRequest)
 	if err != nil {
@@ -109,20 +124,32 @@ func (s *UserServiceServer) GetUser(ctx context.Context, req *pb.GetUserRequest)
 		return nil, mapError(err)
 	}
 
-	return &pb.GetUserResponse{
-		Id:    u.ID,
-		Name:  u.Name,
-		Email: u.Email,
-	}, nil
+	return toPBUser(u), nil
+}
+
+// GetUserByUsername handles the gRPC GetUserByUsername request.
+func (s *UserServiceServer) GetUserByUsername(ctx context.Context, req *pb.GetUserByUsernameRequest) (*pb.GetUserResponse, error) {
+	s.log.Info("gRPC GetUserByUsername request", zap.String("username", req.Username))
+	ucRequest := user.GetUserByUsernameRequest{
+		Username: req.Username,
+	}
+	u, err := s.uc.GetUserByUsername(ctx, ucRequest)
+	if err != nil {
+		s.log.Error("gRPC GetUserByUsername failed", zap.Error(err))
+		return nil, mapError(err)
+	}
+
+	return toPBUser(u), nil
 }
 
 // ListUsers handles the gRPC ListUsers request.
 func (s *UserServiceServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
-	s.log.Info("gRPC ListUsers request", zap.String("query", req.Query), zap.Int64("page", req.Page), zap.Int64("limit", req.Limit))
+	s.log.Info("gRPC ListUsers request", zap.String("query", req.Query), zap.String("locale", req.Locale), zap.Int64("page", req.Page), zap.Int64("limit", req.Limit))
 	ucRequest := user.ListUsersRequest{
-		Query: req.Query,
-		Page:  req.Page,
-		Limit: req.Limit,
+		Query:  req.Query,
+		Locale: req.Locale,
+		Page:   req.Page,
+		Limit:  req.Limit,
 	}
 	usersResponse, err := s.uc.ListUsers(ctx, ucRequest)
 	if err != nil {
@@ -133,9 +160,16 @@ func (s *UserServiceServer) ListUsers(ctx context.Context, req *pb.ListUsersRequ
 	pbUsers := make([]*pb.GetUserResponse, len(usersResponse.Users))
 	for i, u := range usersResponse.Users {
 		pbUsers[i] = &pb.GetUserResponse{
-			Id:    u.ID,
-			Name:  u.Name,
-			Email: u.Email,
+			Id:          u.ID,
+			Username:    u.Username,
+			Name:        u.Name,
+			GivenName:   u.GivenName,
+			FamilyName:  u.FamilyName,
+			DisplayName: u.DisplayName,
+			Email:       u.Email,
+			Phone:    u.Phone,
+			Locale:   u.Locale,
+			Timezone: u.Timezone,
 		}
 	}
 
@@ -154,3 +188,19 @@ func (s *UserServiceServer) ListUsers(ctx context.Context, req *pb.ListUsersRequ
 		Pagination: pbPagination,
 	}, nil
 }
+
+// toPBUser converts a usecase user response into its protobuf representation.
+func toPBUser(u *user.GetUserResponse) *pb.GetUserResponse {
+	return &pb.GetUserResponse{
+		Id:          u.ID,
+		Username:    u.Username,
+		Name:        u.Name,
+		GivenName:   u.GivenName,
+		FamilyName:  u.FamilyName,
+		DisplayName: u.DisplayName,
+		Email:       u.Email,
+		Phone:       u.Phone,
+		Locale:      u.Locale,
+		Timezone:    u.Timezone,
+	}
+}
@@ -0,0 +1,51 @@
+// Package batched coalesces concurrent lookups of single users into batched
+// queries, so that many cache misses arriving together cost one database
+// round trip instead of one each.
+package batched
+
+import (
+	"context"
+	"fmt"
+
+	domain "grpc-user-service/internal/domain/user"
+	"grpc-user-service/internal/usecase/user"
+	"grpc-user-service/pkg/dataloader"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// BatchRepository is a user.Repository that can also load many users at once.
+type BatchRepository interface {
+	user.Repository
+	GetByIDs(ctx context.Context, ids []int64) (map[int64]*domain.User, error)
+}
+
+// UserRepository implements user.Repository by serving GetByID through a
+// dataloader over the GetByIDs of a wrapped repository. Every other call goes
+// straight to the wrapped repository.
+type UserRepository struct {
+	BatchRepository
+	loader *dataloader.Loader[int64, *domain.User]
+}
+
+// NewUserRepository creates a UserRepository around repo.
+func NewUserRepository(repo BatchRepository, cfg dataloader.Config) user.Repository {
+	return &UserRepository{
+		BatchRepository: repo,
+		loader:          dataloader.New(repo.GetByIDs, cfg),
+	}
+}
+
+// GetByID retrieves a user by ID, batched with concurrent lookups.
+func (r *UserRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	u, ok, err := r.loader.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, pkgerrors.NewNotFoundError("user", fmt.Sprintf("user not found: id=%d", id))
+	}
+
+	// Callers may modify the user; each gets its own copy
+	clone := *u
+	return &clone, nil
+}
@@ -0,0 +1,71 @@
+package batched
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"grpc-user-service/internal/adapter/repository/postgres"
+	domain "grpc-user-service/internal/domain/user"
+	"grpc-user-service/pkg/dataloader"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// countingRepo counts the batched queries reaching the database
+type countingRepo struct {
+	*postgres.UserRepoPG
+	batches atomic.Int64
+}
+
+func (r *countingRepo) GetByIDs(ctx context.Context, ids []int64) (map[int64]*domain.User, error) {
+	r.batches.Add(1)
+	return r.UserRepoPG.GetByIDs(ctx, ids)
+}
+
+func TestUserRepository_BatchesGetByID(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&postgres.UserSchema{}, &postgres.UserEmailSchema{}))
+
+	// Every connection to :memory: opens its own database
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	counting := &countingRepo{UserRepoPG: postgres.NewUserRepoPG(db, zaptest.NewLogger(t))}
+	repo := NewUserRepository(counting, dataloader.Config{Wait: 20 * time.Millisecond, MaxBatch: 100})
+	ctx := context.Background()
+
+	var ids []int64
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		id, err := repo.Create(ctx, &domain.User{Name: "User", Email: email})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		id := ids[i%len(ids)]
+		wg.Go(func() {
+			u, err := repo.GetByID(ctx, id)
+			if assert.NoError(t, err) {
+				assert.Equal(t, id, u.ID)
+			}
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, int64(1), counting.batches.Load())
+
+	_, err = repo.GetByID(ctx, 999)
+	var notFoundErr *pkgerrors.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
@@ -0,0 +1,14 @@
+package cached
+
+import "expvar"
+
+// metrics holds the cache counters, published through expvar under "user_cache".
+var metrics = expvar.NewMap("user_cache")
+
+// Counter names in metrics.
+const (
+	metricNegativeHits      = "negative_hits"       // GetByID answered NotFound from a tombstone
+	metricEmailNegativeHits = "email_negative_hits" // GetByEmail answered "free" from a negative entry
+	metricRefreshes         = "refreshes"           // Background refreshes of stale or expiring users
+	metricDegradedHits      = "degraded_hits"       // GetByID served a last known good copy while the DB failed
+)
@@ -2,7 +2,9 @@ package cached
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 	"golang.org/x/sync/singleflight"
@@ -10,120 +12,587 @@ import (
 	"grpc-user-service/internal/adapter/cache"
 	domain "grpc-user-service/internal/domain/user"
 	"grpc-user-service/internal/usecase/user"
+	"grpc-user-service/pkg/degraded"
+	pkgerrors "grpc-user-service/pkg/errors"
 )
 
 // CachedUserRepository implements user.Repository with caching support.
 // It wraps a persistent repository (DB) and a cache implementation.
 //nolint:revive // Acceptable naming pattern in Go
 type CachedUserRepository struct {
-	dbRepo user.Repository
-	cache  cache.UserCache
-	log    *zap.Logger
-	group  singleflight.Group
+	dbRepo   user.Repository
+	cache    cache.UserCache
+	log      *zap.Logger
+	group    singleflight.Group
+	degraded bool
+}
+
+// Option configures a CachedUserRepository.
+type Option func(*CachedUserRepository)
+
+// WithDegradedMode makes GetByID serve the cache's last known good copy of a
+// user when the database fails. Such responses are reported through
+// degraded.Report so that transports can mark them.
+func WithDegradedMode() Option {
+	return func(r *CachedUserRepository) {
+		r.degraded = true
+	}
 }
 
 // NewCachedUserRepository creates a new instance of CachedUserRepository.
-func NewCachedUserRepository(dbRepo user.Repository, cache cache.UserCache, log *zap.Logger) user.Repository {
-	return &CachedUserRepository{
+func NewCachedUserRepository(dbRepo user.Repository, cache cache.UserCache, log *zap.Logger, opts ...Option) user.Repository {
+	r := &CachedUserRepository{
 		dbRepo: dbRepo,
 		cache:  cache,
 		log:    log,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
-// Create delegates to the DB repository.
+// Create inserts the user in DB and invalidates cached list pages and the
+// negative entry the uniqueness check left for the new email.
 func (r *CachedUserRepository) Create(ctx context.Context, u *domain.User) (int64, error) {
-	return r.dbRepo.Create(ctx, u)
+	id, err := r.dbRepo.Create(ctx, u)
+	if err != nil {
+		return 0, err
+	}
+
+	r.bumpGeneration(ctx, "create")
+	r.invalidateEmails(ctx, "create", u.Email)
+
+	// Probes for IDs that did not exist yet may have left a tombstone for this one
+	if r.cache != nil {
+		if err := r.cache.Delete(ctx, id); err != nil {
+			r.log.Warn("failed to clear cache tombstone after create", zap.Int64("id", id), zap.Error(err))
+		}
+	}
+
+	return id, nil
+}
+
+// refreshTimeout bounds a background refresh of a cached user.
+const refreshTimeout = 5 * time.Second
+
+// loaded is the result of a single-flight load of a user; stale marks a last
+// known good copy served while the database failed.
+type loaded struct {
+	user  *domain.User
+	stale bool
 }
 
-// GetByID retrieves a user by ID using Cache-Aside pattern.
+// GetByID retrieves a user by ID using Cache-Aside pattern. NotFound results
+// are cached as tombstones with the negative TTL, so repeated lookups of
+// missing IDs do not reach the database. Entries the cache marks for refresh
+// (stale or picked for early refresh) are returned right away and reloaded in
+// the background. In degraded mode, database failures are answered with the
+// last known good copy of the user if the cache holds one.
 func (r *CachedUserRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
 	// Try to get from cache first
 	if r.cache != nil {
-		cachedUser, err := r.cache.Get(ctx, id)
+		cachedUser, refresh, err := r.cache.Lookup(ctx, id)
+		if errors.Is(err, cache.ErrMissing) {
+			metrics.Add(metricNegativeHits, 1)
+			return nil, notFound(id)
+		}
 		if err != nil {
 			r.log.Warn("cache get error, falling back to database", zap.Int64("id", id), zap.Error(err))
 		} else if cachedUser != nil {
+			if refresh {
+				r.refresh(ctx, id)
+			}
 			r.log.Debug("user retrieved from cache", zap.Int64("id", id))
 			return cachedUser, nil
 		}
 	}
 
 	// Cache miss or cache disabled - use single-flight to prevent stampede
-	key := fmt.Sprintf("user:%d", id)
-	result, err, _ := r.group.Do(key, func() (any, error) {
+	result, err, _ := r.group.Do(userFlightKey(id), func() (any, error) {
 		// Double-check cache in case another request populated it while we were waiting
 		if r.cache != nil {
 			cachedUser, err := r.cache.Get(ctx, id)
+			if errors.Is(err, cache.ErrMissing) {
+				metrics.Add(metricNegativeHits, 1)
+				return nil, notFound(id)
+			}
 			if err == nil && cachedUser != nil {
 				r.log.Debug("user retrieved from cache after single-flight wait", zap.Int64("id", id))
-				return cachedUser, nil
+				return loaded{user: cachedUser}, nil
 			}
 		}
 
 		// Only one request hits database
-		u, err := r.dbRepo.GetByID(ctx, id)
+		u, err := r.load(ctx, id)
 		if err != nil {
+			if stale := r.lastKnownGood(ctx, id, err); stale != nil {
+				return loaded{user: stale, stale: true}, nil
+			}
 			return nil, err
 		}
+		return loaded{user: u}, nil
+	})
 
-		// Store in cache for future requests
-		if r.cache != nil {
-			if err := r.cache.Set(ctx, u); err != nil {
-				r.log.Warn("failed to cache user", zap.Int64("id", id), zap.Error(err))
+	if err != nil {
+		return nil, err
+	}
+
+	res := result.(loaded)
+	if res.stale {
+		// Reported per caller: callers sharing the flight each get a degraded response
+		metrics.Add(metricDegradedHits, 1)
+		degraded.Report(ctx, degraded.ReasonStaleCache)
+	}
+	return res.user, nil
+}
+
+// lastKnownGood returns the last known good copy of a user when degraded mode
+// is enabled and dbErr is a database failure, or nil.
+func (r *CachedUserRepository) lastKnownGood(ctx context.Context, id int64, dbErr error) *domain.User {
+	var internalErr *pkgerrors.InternalError
+	if !r.degraded || r.cache == nil || !errors.As(dbErr, &internalErr) {
+		return nil
+	}
+
+	u, err := r.cache.GetLastKnownGood(ctx, id)
+	if err != nil || u == nil {
+		return nil
+	}
+
+	r.log.Warn("database unavailable, serving last known good user", zap.Int64("id", id), zap.Error(dbErr))
+	return u
+}
+
+// refresh reloads a cached user in the background. It shares the single-flight
+// key of GetByID, so a refresh and concurrent misses cost one query.
+func (r *CachedUserRepository) refresh(ctx context.Context, id int64) {
+	metrics.Add(metricRefreshes, 1)
+
+	// Detach from the request, which may finish before the refresh does
+	refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+	go func() {
+		defer cancel()
+		res := <-r.group.DoChan(userFlightKey(id), func() (any, error) {
+			u, err := r.load(refreshCtx, id)
+			if err != nil {
+				return nil, err
 			}
+			return loaded{user: u}, nil
+		})
+		if res.Err != nil {
+			r.log.Warn("background cache refresh failed", zap.Int64("id", id), zap.Error(res.Err))
 		}
+	}()
+}
 
-		return u, nil
-	})
-
+// load reads a user from the DB and caches the result, a tombstone if the
+// user does not exist.
+func (r *CachedUserRepository) load(ctx context.Context, id int64) (*domain.User, error) {
+	u, err := r.dbRepo.GetByID(ctx, id)
+	var notFoundErr *pkgerrors.NotFoundError
+	if errors.As(err, &notFoundErr) && r.cache != nil {
+		if err := r.cache.SetMissing(ctx, id); err != nil {
+			r.log.Warn("failed to cache missing user", zap.Int64("id", id), zap.Error(err))
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return result.(*domain.User), nil
+	// Store in cache for future requests
+	if r.cache != nil {
+		if err := r.cache.Set(ctx, u); err != nil {
+			r.log.Warn("failed to cache user", zap.Int64("id", id), zap.Error(err))
+		}
+	}
+
+	return u, nil
 }
 
-// GetByEmail delegates to the DB repository.
+// userFlightKey is the single-flight key of loads of a user.
+func userFlightKey(id int64) string {
+	return fmt.Sprintf("user:%d", id)
+}
+
+// notFound returns the error the DB repository reports for a missing user.
+func notFound(id int64) error {
+	return pkgerrors.NewNotFoundError("user", fmt.Sprintf("user not found: id=%d", id))
+}
+
+// GetByEmail resolves the email through the cached email→ID index and then
+// loads the user via GetByID. Only primary addresses are indexed, and a cached
+// mapping is only trusted while the user still has that primary address, so a
+// missed invalidation cannot return the wrong user. Unknown emails are cached
+// as negative entries with a short TTL, so repeated uniqueness checks for free
+// addresses skip the DB.
 func (r *CachedUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	return r.dbRepo.GetByEmail(ctx, email)
+	if r.cache != nil {
+		id, found, err := r.cache.GetIDByEmail(ctx, email)
+		switch {
+		case err != nil:
+			r.log.Warn("email cache get error, falling back to database", zap.String("email", email), zap.Error(err))
+		case found && id == 0:
+			metrics.Add(metricEmailNegativeHits, 1)
+			r.log.Debug("email known to be free from cache", zap.String("email", email))
+			return nil, nil
+		case found:
+			u, err := r.GetByID(ctx, id)
+			if err == nil && u.Email == email {
+				r.log.Debug("user retrieved by email from cache", zap.String("email", email), zap.Int64("id", id))
+				return u, nil
+			}
+
+			// Stale mapping: the address moved or the user was deleted since it was cached
+			if err := r.cache.DeleteEmails(ctx, email); err != nil {
+				r.log.Warn("failed to drop stale email mapping", zap.String("email", email), zap.Error(err))
+			}
+		}
+	}
+
+	u, err := r.dbRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	// Secondary addresses are looked up in the DB every time: they are not part
+	// of the cached user, so a mapping to them could not be checked
+	if r.cache != nil && email != "" && (u == nil || u.Email == email) {
+		var id int64
+		if u != nil {
+			id = u.ID
+		}
+		if err := r.cache.SetEmail(ctx, email, id); err != nil {
+			r.log.Warn("failed to cache email", zap.String("email", email), zap.Error(err))
+		}
+	}
+
+	return u, nil
+}
+
+// GetByUsername resolves the username through the cached username→ID index and
+// then loads the user via GetByID, so both lookups share the user cache.
+func (r *CachedUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	if r.cache != nil {
+		id, err := r.cache.GetIDByUsername(ctx, username)
+		if err != nil {
+			r.log.Warn("username cache get error, falling back to database", zap.String("username", username), zap.Error(err))
+		} else if id != 0 {
+			u, err := r.GetByID(ctx, id)
+			if err == nil && u.Username == username {
+				r.log.Debug("user retrieved by username from cache", zap.String("username", username), zap.Int64("id", id))
+				return u, nil
+			}
+
+			// Stale mapping: the user was renamed or deleted since it was cached
+			if err := r.cache.DeleteUsername(ctx, username); err != nil {
+				r.log.Warn("failed to drop stale username mapping", zap.String("username", username), zap.Error(err))
+			}
+		}
+	}
+
+	u, err := r.dbRepo.GetByUsername(ctx, username)
+	if err != nil || u == nil {
+		return u, err
+	}
+
+	if r.cache != nil {
+		if err := r.cache.SetUsername(ctx, username, u.ID); err != nil {
+			r.log.Warn("failed to cache username", zap.String("username", username), zap.Error(err))
+		}
+		if err := r.cache.Set(ctx, u); err != nil {
+			r.log.Warn("failed to cache user", zap.Int64("id", u.ID), zap.Error(err))
+		}
+	}
+
+	return u, nil
 }
 
-// Update updates the user in DB and invalidates the cache.
-func (r *CachedUserRepository) Update(ctx context.Context, u *domain.User) (int64, error) {
-	id, err := r.dbRepo.Update(ctx, u)
+// Update updates the user in DB and invalidates the cache. When the email
+// changes, the mappings of both the old and the new address are dropped.
+func (r *CachedUserRepository) Update(ctx context.Context, u *domain.User, cleared ...string) (int64, error) {
+	oldUsername := r.cachedUsername(ctx, u.ID)
+
+	// Read the old email from the DB: the cached copy may already have expired
+	var oldEmail string
+	if u.Email != "" && r.cache != nil {
+		if old, err := r.dbRepo.GetByID(ctx, u.ID); err == nil {
+			oldEmail = old.Email
+		}
+	}
+
+	id, err := r.dbRepo.Update(ctx, u, cleared...)
 	if err != nil {
 		return 0, err
 	}
 
 	// Invalidate cache after successful update
-	if r.cache != nil {
-		if err := r.cache.Delete(ctx, u.ID); err != nil {
-			r.log.Warn("failed to invalidate cache after update", zap.Int64("id", u.ID), zap.Error(err))
-		}
+	r.invalidate(ctx, u.ID, oldUsername, "update")
+	if u.Email != "" {
+		r.invalidateEmails(ctx, "update", oldEmail, u.Email)
 	}
 
 	return id, nil
 }
 
-// Delete deletes the user from DB and invalidates the cache.
+// Delete deletes the user from DB and invalidates the cache, including the
+// mappings of every address the deletion releases.
 func (r *CachedUserRepository) Delete(ctx context.Context, id int64) (int64, error) {
+	oldUsername := r.cachedUsername(ctx, id)
+	emails := r.userEmails(ctx, id)
+
 	deletedID, err := r.dbRepo.Delete(ctx, id)
 	if err != nil {
 		return 0, err
 	}
 
 	// Invalidate cache after successful deletion
-	if r.cache != nil {
-		if err := r.cache.Delete(ctx, id); err != nil {
-			r.log.Warn("failed to invalidate cache after delete", zap.Int64("id", id), zap.Error(err))
+	r.invalidate(ctx, id, oldUsername, "delete")
+	r.invalidateEmails(ctx, "delete", emails...)
+
+	return deletedID, nil
+}
+
+// cachedUsername returns the username of the cached copy of a user, if any.
+// It only consults the cache: mappings that are not found here are caught by
+// the consistency check in GetByUsername.
+func (r *CachedUserRepository) cachedUsername(ctx context.Context, id int64) string {
+	if r.cache == nil {
+		return ""
+	}
+
+	u, err := r.cache.Get(ctx, id)
+	if err != nil || u == nil {
+		return ""
+	}
+	return u.Username
+}
+
+// invalidate removes a user and its username mapping from the cache after a
+// write and invalidates cached list pages.
+func (r *CachedUserRepository) invalidate(ctx context.Context, id int64, username, op string) {
+	if r.cache == nil {
+		return
+	}
+
+	r.bumpGeneration(ctx, op)
+
+	if err := r.cache.Delete(ctx, id); err != nil {
+		r.log.Warn("failed to invalidate cache after "+op, zap.Int64("id", id), zap.Error(err))
+	}
+	if username != "" {
+		if err := r.cache.DeleteUsername(ctx, username); err != nil {
+			r.log.Warn("failed to invalidate username cache after "+op, zap.String("username", username), zap.Error(err))
 		}
 	}
+}
 
-	return deletedID, nil
+// userEmails returns every address of a user from the DB, so that their
+// mappings can be dropped after a write releases them.
+func (r *CachedUserRepository) userEmails(ctx context.Context, id int64) []string {
+	if r.cache == nil {
+		return nil
+	}
+
+	emails, err := r.dbRepo.ListEmails(ctx, id)
+	if err != nil {
+		r.log.Warn("failed to list user emails for cache invalidation", zap.Int64("id", id), zap.Error(err))
+		return nil
+	}
+
+	addresses := make([]string, len(emails))
+	for i, e := range emails {
+		addresses[i] = e.Address
+	}
+	return addresses
 }
 
-// List delegates to the DB repository.
-func (r *CachedUserRepository) List(ctx context.Context, query string, page, limit int64) ([]domain.User, int64, error) {
-	return r.dbRepo.List(ctx, query, page, limit)
+// invalidateEmails removes email mappings, positive or negative, from the
+// cache after a write.
+func (r *CachedUserRepository) invalidateEmails(ctx context.Context, op string, emails ...string) {
+	if r.cache == nil {
+		return
+	}
+
+	keep := emails[:0:0]
+	for _, email := range emails {
+		if email != "" {
+			keep = append(keep, email)
+		}
+	}
+	if len(keep) == 0 {
+		return
+	}
+
+	if err := r.cache.DeleteEmails(ctx, keep...); err != nil {
+		r.log.Warn("failed to invalidate email cache after "+op, zap.Strings("emails", keep), zap.Error(err))
+	}
+}
+
+// bumpGeneration advances the users generation so that list pages cached
+// before a write are no longer read.
+func (r *CachedUserRepository) bumpGeneration(ctx context.Context, op string) {
+	if r.cache == nil {
+		return
+	}
+
+	if err := r.cache.BumpGeneration(ctx); err != nil {
+		r.log.Warn("failed to invalidate list cache after "+op, zap.Error(err))
+	}
+}
+
+// List retrieves a page of users using Cache-Aside pattern. Pages are cached
+// under the current users generation, which every write bumps.
+func (r *CachedUserRepository) List(ctx context.Context, query, locale string, page, limit int64) ([]domain.User, int64, error) {
+	if r.cache == nil {
+		return r.dbRepo.List(ctx, query, locale, page, limit)
+	}
+
+	generation, err := r.cache.Generation(ctx)
+	if err != nil {
+		r.log.Warn("list cache generation error, falling back to database", zap.Error(err))
+		return r.dbRepo.List(ctx, query, locale, page, limit)
+	}
+
+	if list, err := r.cache.GetList(ctx, generation, query, locale, page, limit); err != nil {
+		r.log.Warn("list cache get error, falling back to database", zap.Error(err))
+	} else if list != nil {
+		r.log.Debug("user list retrieved from cache", zap.Int64("generation", generation), zap.Int64("page", page))
+		return list.Users, list.Total, nil
+	}
+
+	// Cache miss - use single-flight so that concurrent identical queries hit the database once
+	key := fmt.Sprintf("list:%d:%d:%d:%s:%s", generation, page, limit, locale, query)
+	result, err, _ := r.group.Do(key, func() (any, error) {
+		users, total, err := r.dbRepo.List(ctx, query, locale, page, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		list := &cache.UserList{Users: users, Total: total}
+		if err := r.cache.SetList(ctx, generation, query, locale, page, limit, list); err != nil {
+			r.log.Warn("failed to cache user list", zap.Int64("generation", generation), zap.Error(err))
+		}
+
+		return list, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	list := result.(*cache.UserList)
+	return list.Users, list.Total, nil
+}
+
+// ListEmails delegates to the DB repository.
+func (r *CachedUserRepository) ListEmails(ctx context.Context, userID int64) ([]domain.Email, error) {
+	return r.dbRepo.ListEmails(ctx, userID)
+}
+
+// AddEmail adds the address in DB and drops its email mapping, which is
+// likely a negative entry left by the uniqueness check. Secondary addresses
+// are not part of the cached user, so it stays valid.
+func (r *CachedUserRepository) AddEmail(ctx context.Context, userID int64, address string) error {
+	if err := r.dbRepo.AddEmail(ctx, userID, address); err != nil {
+		return err
+	}
+
+	r.invalidateEmails(ctx, "add email", address)
+
+	return nil
+}
+
+// RemoveEmail removes the address in DB and drops its email mapping.
+func (r *CachedUserRepository) RemoveEmail(ctx context.Context, userID int64, address string) error {
+	if err := r.dbRepo.RemoveEmail(ctx, userID, address); err != nil {
+		return err
+	}
+
+	r.invalidateEmails(ctx, "remove email", address)
+
+	return nil
+}
+
+// SetPrimaryEmail changes the primary address in DB and invalidates the cached
+// user, whose Email mirrors the primary address.
+func (r *CachedUserRepository) SetPrimaryEmail(ctx context.Context, userID int64, address string) error {
+	if err := r.dbRepo.SetPrimaryEmail(ctx, userID, address); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, userID, "", "primary email change")
+
+	return nil
+}
+
+// SetEmailVerification delegates to the DB repository.
+func (r *CachedUserRepository) SetEmailVerification(ctx context.Context, userID int64, address, tokenHash string, expiresAt time.Time) error {
+	return r.dbRepo.SetEmailVerification(ctx, userID, address, tokenHash, expiresAt)
+}
+
+// VerifyEmail delegates to the DB repository.
+func (r *CachedUserRepository) VerifyEmail(ctx context.Context, userID int64, address, tokenHash string) error {
+	return r.dbRepo.VerifyEmail(ctx, userID, address, tokenHash)
+}
+
+// LinkIdentity delegates to the DB repository.
+func (r *CachedUserRepository) LinkIdentity(ctx context.Context, identity *domain.Identity) error {
+	return r.dbRepo.LinkIdentity(ctx, identity)
+}
+
+// UnlinkIdentity delegates to the DB repository.
+func (r *CachedUserRepository) UnlinkIdentity(ctx context.Context, userID int64, provider, subject string) error {
+	return r.dbRepo.UnlinkIdentity(ctx, userID, provider, subject)
+}
+
+// GetByIdentity delegates to the DB repository.
+func (r *CachedUserRepository) GetByIdentity(ctx context.Context, provider, subject string) (*domain.User, error) {
+	return r.dbRepo.GetByIdentity(ctx, provider, subject)
+}
+
+// ListIdentities delegates to the DB repository.
+func (r *CachedUserRepository) ListIdentities(ctx context.Context, userID int64) ([]domain.Identity, error) {
+	return r.dbRepo.ListIdentities(ctx, userID)
+}
+
+// Erase anonymizes the user in DB and purges its cache entries. Unlike other
+// writes, a failed purge is returned as an error: erased data must not remain
+// readable from the cache. Erase is idempotent, so callers can retry.
+func (r *CachedUserRepository) Erase(ctx context.Context, id int64, reason string) (*domain.Erasure, error) {
+	// Read the username from the DB: the cached copy may already have expired
+	var username string
+	if u, err := r.dbRepo.GetByID(ctx, id); err == nil {
+		username = u.Username
+	}
+	emails := r.userEmails(ctx, id)
+
+	erasure, err := r.dbRepo.Erase(ctx, id, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.cache != nil {
+		if err := r.cache.Delete(ctx, id); err != nil {
+			r.log.Error("failed to purge cache after erase", zap.Int64("id", id), zap.Error(err))
+			return nil, pkgerrors.NewInternalError("failed to purge user cache", err)
+		}
+		if username != "" {
+			if err := r.cache.DeleteUsername(ctx, username); err != nil {
+				r.log.Error("failed to purge username cache after erase", zap.Int64("id", id), zap.Error(err))
+				return nil, pkgerrors.NewInternalError("failed to purge user cache", err)
+			}
+		}
+		if len(emails) > 0 {
+			if err := r.cache.DeleteEmails(ctx, emails...); err != nil {
+				r.log.Error("failed to purge email cache after erase", zap.Int64("id", id), zap.Error(err))
+				return nil, pkgerrors.NewInternalError("failed to purge user cache", err)
+			}
+		}
+		if err := r.cache.BumpGeneration(ctx); err != nil {
+			r.log.Error("failed to purge list cache after erase", zap.Int64("id", id), zap.Error(err))
+			return nil, pkgerrors.NewInternalError("failed to purge user cache", err)
+		}
+	}
+
+	return erasure, nil
 }
@@ -0,0 +1,460 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"grpc-user-service/internal/adapter/cache"
+	"grpc-user-service/internal/adapter/repository/postgres"
+	domain "grpc-user-service/internal/domain/user"
+	"grpc-user-service/internal/usecase/user"
+	"grpc-user-service/pkg/degraded"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// setupTestRepo wires a CachedUserRepository over SQLite and miniredis
+func setupTestRepo(t *testing.T) (user.Repository, cache.UserCache, *miniredis.Miniredis) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&postgres.UserSchema{},
+		&postgres.UserEmailSchema{},
+		&postgres.ExternalIdentitySchema{},
+		&postgres.ErasureSchema{},
+	))
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	logger := zaptest.NewLogger(t)
+	userCache := cache.NewRedisUserCache(client, 5*time.Minute, logger)
+	repo := NewCachedUserRepository(postgres.NewUserRepoPG(db, logger), userCache, logger)
+	return repo, userCache, mr
+}
+
+func TestCachedUserRepository_Erase_PurgesCache(t *testing.T) {
+	repo, userCache, _ := setupTestRepo(t)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &domain.User{Username: "johndoe", Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	// Populate the ID and username cache entries
+	_, err = repo.GetByUsername(ctx, "johndoe")
+	require.NoError(t, err)
+	cached, err := userCache.Get(ctx, id)
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+
+	_, err = repo.Erase(ctx, id, "")
+	require.NoError(t, err)
+
+	cached, err = userCache.Get(ctx, id)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+	cachedID, err := userCache.GetIDByUsername(ctx, "johndoe")
+	require.NoError(t, err)
+	assert.Zero(t, cachedID)
+
+	got, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, postgres.ErasedUserName, got.Name)
+}
+
+func TestCachedUserRepository_Erase_CacheUnavailable(t *testing.T) {
+	repo, _, mr := setupTestRepo(t)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	mr.Close()
+
+	// The erasure is applied but reported as failed so that it is retried
+	_, err = repo.Erase(ctx, id, "")
+	assert.Error(t, err)
+}
+
+// countingRepo counts List calls reaching the database
+type countingRepo struct {
+	user.Repository
+	gets    atomic.Int32
+	down    atomic.Bool // GetByID fails as if the database were unavailable
+	lists   atomic.Int32
+	emails  atomic.Int32
+	release chan struct{}
+}
+
+func (r *countingRepo) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	r.gets.Add(1)
+	if r.down.Load() {
+		return nil, pkgerrors.NewInternalError("failed to get user", errors.New("connection refused"))
+	}
+	return r.Repository.GetByID(ctx, id)
+}
+
+func (r *countingRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.emails.Add(1)
+	return r.Repository.GetByEmail(ctx, email)
+}
+
+func (r *countingRepo) List(ctx context.Context, query, locale string, page, limit int64) ([]domain.User, int64, error) {
+	r.lists.Add(1)
+	if r.release != nil {
+		<-r.release
+	}
+	return r.Repository.List(ctx, query, locale, page, limit)
+}
+
+// setupCountingRepo wires a CachedUserRepository over a countingRepo
+func setupCountingRepo(t *testing.T, ttl time.Duration, opts ...cache.Option) (user.Repository, *countingRepo, *miniredis.Miniredis) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&postgres.UserSchema{}, &postgres.UserEmailSchema{}, &postgres.ExternalIdentitySchema{}))
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	logger := zaptest.NewLogger(t)
+	dbRepo := &countingRepo{Repository: postgres.NewUserRepoPG(db, logger)}
+	repo := NewCachedUserRepository(dbRepo, cache.NewRedisUserCache(client, ttl, logger, opts...), logger)
+	return repo, dbRepo, mr
+}
+
+func TestCachedUserRepository_List_CachedUntilWrite(t *testing.T) {
+	repo, dbRepo, _ := setupCountingRepo(t, 5*time.Minute)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	users, total, err := repo.List(ctx, "", "", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, users, 1)
+
+	// Served from cache
+	_, _, err = repo.List(ctx, "", "", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), dbRepo.lists.Load())
+
+	// Every kind of write invalidates cached pages
+	writes := []func() error{
+		func() error {
+			_, err := repo.Create(ctx, &domain.User{Name: "Jane Smith", Email: "jane@example.com"})
+			return err
+		},
+		func() error {
+			_, err := repo.Update(ctx, &domain.User{ID: id, Name: "John Updated"})
+			return err
+		},
+		func() error {
+			_, err := repo.Delete(ctx, id)
+			return err
+		},
+	}
+	for i, write := range writes {
+		require.NoError(t, write())
+		_, _, err := repo.List(ctx, "", "", 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int32(i+2), dbRepo.lists.Load())
+	}
+
+	users, total, err = repo.List(ctx, "", "", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "Jane Smith", users[0].Name)
+}
+
+func TestCachedUserRepository_List_SingleFlight(t *testing.T) {
+	repo, dbRepo, _ := setupCountingRepo(t, 5*time.Minute)
+	dbRepo.release = make(chan struct{})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := repo.List(ctx, "john", "", 1, 10)
+			assert.NoError(t, err)
+		}()
+	}
+
+	// Let the callers pile up on the in-flight query before it completes
+	require.Eventually(t, func() bool { return dbRepo.lists.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	close(dbRepo.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), dbRepo.lists.Load())
+}
+
+func TestCachedUserRepository_GetByEmail_Cached(t *testing.T) {
+	repo, dbRepo, _ := setupCountingRepo(t, 5*time.Minute)
+	ctx := context.Background()
+
+	// Unknown emails are cached as negative entries
+	u, err := repo.GetByEmail(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, u)
+	u, err = repo.GetByEmail(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, u)
+	assert.Equal(t, int32(1), dbRepo.emails.Load())
+
+	// Creating the user drops the negative entry
+	id, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	u, err = repo.GetByEmail(ctx, "john@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, u)
+	assert.Equal(t, id, u.ID)
+
+	// Served from the email index
+	_, err = repo.GetByEmail(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), dbRepo.emails.Load())
+}
+
+func TestCachedUserRepository_GetByEmail_InvalidatedOnWrite(t *testing.T) {
+	repo, _, _ := setupCountingRepo(t, 5*time.Minute)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	// owner looks up the email, populating the index, and returns the owner ID or 0
+	owner := func(email string) int64 {
+		u, err := repo.GetByEmail(ctx, email)
+		require.NoError(t, err)
+		if u == nil {
+			return 0
+		}
+		return u.ID
+	}
+
+	assert.Equal(t, id, owner("john@example.com"))
+	assert.Zero(t, owner("new@example.com"))
+
+	// Changing the email drops the mappings of both addresses
+	_, err = repo.Update(ctx, &domain.User{ID: id, Email: "new@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, id, owner("new@example.com"))
+	assert.Equal(t, id, owner("john@example.com"))
+
+	// Removing a secondary address frees it
+	require.NoError(t, repo.RemoveEmail(ctx, id, "john@example.com"))
+	assert.Zero(t, owner("john@example.com"))
+
+	// Adding an address replaces its negative entry
+	require.NoError(t, repo.AddEmail(ctx, id, "john@example.com"))
+	assert.Equal(t, id, owner("john@example.com"))
+
+	// Deleting the user releases all of its addresses
+	_, err = repo.Delete(ctx, id)
+	require.NoError(t, err)
+	assert.Zero(t, owner("new@example.com"))
+	assert.Zero(t, owner("john@example.com"))
+}
+
+func TestCachedUserRepository_GetByEmail_ChecksMappedOwner(t *testing.T) {
+	repo, userCache, _ := setupTestRepo(t)
+	ctx := context.Background()
+
+	johnID, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+	janeID, err := repo.Create(ctx, &domain.User{Name: "Jane Smith", Email: "jane@example.com"})
+	require.NoError(t, err)
+
+	// A mapping left behind by a missed invalidation points at the wrong user
+	require.NoError(t, userCache.SetEmail(ctx, "jane@example.com", johnID))
+
+	u, err := repo.GetByEmail(ctx, "jane@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, u)
+	assert.Equal(t, janeID, u.ID)
+
+	id, found, err := userCache.GetIDByEmail(ctx, "jane@example.com")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, janeID, id, "the mapping is replaced")
+
+	// Secondary addresses are resolved by the database and not indexed
+	require.NoError(t, repo.AddEmail(ctx, johnID, "john@work.example.com"))
+	u, err = repo.GetByEmail(ctx, "john@work.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, u)
+	assert.Equal(t, johnID, u.ID)
+
+	_, found, err = userCache.GetIDByEmail(ctx, "john@work.example.com")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestCachedUserRepository_Create_TakenEmailDespiteNegativeEntry(t *testing.T) {
+	repo, userCache, _ := setupTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	// A lookup that raced the create cached the address as free
+	require.NoError(t, userCache.DeleteEmails(ctx, "john@example.com"))
+	require.NoError(t, userCache.SetEmail(ctx, "john@example.com", 0))
+
+	u, err := repo.GetByEmail(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, u, "the uniqueness check is answered from the stale entry")
+
+	// The unique index still rejects the duplicate as a conflict
+	_, err = repo.Create(ctx, &domain.User{Name: "John Again", Email: "john@example.com"})
+	assert.IsType(t, &pkgerrors.AlreadyExistsError{}, err)
+}
+
+func TestCachedUserRepository_GetByID_NegativeCache(t *testing.T) {
+	repo, dbRepo, _ := setupCountingRepo(t, 5*time.Minute)
+	ctx := context.Background()
+	hits := func() int64 {
+		if v, ok := metrics.Get(metricNegativeHits).(interface{ Value() int64 }); ok {
+			return v.Value()
+		}
+		return 0
+	}
+	before := hits()
+
+	// The first lookup caches a tombstone; the second is answered from it
+	for i := 0; i < 2; i++ {
+		_, err := repo.GetByID(ctx, 1)
+		var notFoundErr *pkgerrors.NotFoundError
+		assert.True(t, errors.As(err, &notFoundErr))
+	}
+	assert.Equal(t, int32(1), dbRepo.gets.Load())
+	assert.Equal(t, before+1, hits())
+
+	// Creating the user clears the tombstone of its ID
+	id, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), id)
+
+	u, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", u.Name)
+}
+
+func TestCachedUserRepository_GetByID_StaleWhileRevalidate(t *testing.T) {
+	repo, dbRepo, _ := setupCountingRepo(t, 50*time.Millisecond, cache.WithStaleWindow(time.Minute))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), dbRepo.gets.Load())
+
+	// Change the user behind the cache's back and let the entry go stale
+	_, err = dbRepo.Repository.Update(ctx, &domain.User{ID: id, Name: "John Updated"})
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	// The cached value is returned right away and reloaded in the background
+	u, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", u.Name)
+
+	require.Eventually(t, func() bool {
+		u, err := repo.GetByID(ctx, id)
+		return err == nil && u.Name == "John Updated"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestCachedUserRepository_GetByID_DegradedMode(t *testing.T) {
+	repo, dbRepo, mr := setupCountingRepo(t, 5*time.Minute, cache.WithLastKnownGood(time.Hour))
+	WithDegradedMode()(repo.(*CachedUserRepository))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+
+	// The regular entry expired and the database is down
+	mr.FastForward(10 * time.Minute)
+	dbRepo.down.Store(true)
+
+	var reasons []string
+	degradedCtx := degraded.WithReporter(ctx, func(reason string) { reasons = append(reasons, reason) })
+
+	u, err := repo.GetByID(degradedCtx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", u.Name)
+	assert.Equal(t, []string{degraded.ReasonStaleCache}, reasons)
+
+	// Unknown users still fail
+	_, err = repo.GetByID(ctx, id+1)
+	var internalErr *pkgerrors.InternalError
+	assert.True(t, errors.As(err, &internalErr))
+}
+
+func TestCachedUserRepository_GetByID_DegradedModeDisabled(t *testing.T) {
+	repo, dbRepo, mr := setupCountingRepo(t, 5*time.Minute, cache.WithLastKnownGood(time.Hour))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+
+	mr.FastForward(10 * time.Minute)
+	dbRepo.down.Store(true)
+
+	_, err = repo.GetByID(ctx, id)
+	assert.Error(t, err)
+}
+
+func TestCachedUserRepository_MemoryCache(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&postgres.UserSchema{}, &postgres.UserEmailSchema{}, &postgres.ExternalIdentitySchema{}))
+
+	logger := zaptest.NewLogger(t)
+	dbRepo := &countingRepo{Repository: postgres.NewUserRepoPG(db, logger)}
+	repo := NewCachedUserRepository(dbRepo, cache.NewMemoryUserCache(100, 5*time.Minute, logger), logger)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		u, err := repo.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "John Doe", u.Name)
+	}
+	assert.Equal(t, int32(1), dbRepo.gets.Load())
+
+	// Writes invalidate the in-memory entries like the Redis ones
+	_, err = repo.Update(ctx, &domain.User{ID: id, Name: "John Updated"})
+	require.NoError(t, err)
+	u, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "John Updated", u.Name)
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// AuditRepoPG implements the audit log using PostgreSQL and GORM.
+type AuditRepoPG struct {
+	db  *gorm.DB    // GORM database connection
+	log *zap.Logger // Structured logger for database operations
+}
+
+// NewAuditRepoPG creates a new instance of AuditRepoPG.
+func NewAuditRepoPG(db *gorm.DB, log *zap.Logger) *AuditRepoPG {
+	return &AuditRepoPG{db: db, log: log}
+}
+
+// AuditEntrySchema represents the database schema for the audit_log table.
+type AuditEntrySchema struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"` // Unique identifier with auto-increment
+	UserID    int64     `gorm:"not null;index"`           // User the operation was performed on
+	Action    string    `gorm:"size:64;not null"`         // Operation name (e.g. "user.updated")
+	Details   string    `gorm:"size:255"`                 // Non-personal context such as changed field names
+	CreatedAt time.Time `gorm:"not null"`                 // When the operation was performed
+}
+
+// TableName specifies the table name for the AuditEntrySchema model.
+func (AuditEntrySchema) TableName() string {
+	return "audit_log"
+}
+
+// Record appends an audit entry.
+func (r *AuditRepoPG) Record(ctx context.Context, entry *user.AuditEntry) error {
+	if entry == nil {
+		return pkgerrors.NewValidationError("entry", "audit entry cannot be nil")
+	}
+
+	model := AuditEntrySchema{UserID: entry.UserID, Action: entry.Action, Details: entry.Details}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		r.log.Error("failed to record audit entry in db", zap.Error(err), zap.Int64("user_id", entry.UserID), zap.String("action", entry.Action))
+		return pkgerrors.NewInternalError("failed to record audit entry", err)
+	}
+
+	entry.ID = model.ID
+	entry.CreatedAt = model.CreatedAt
+	return nil
+}
+
+// ListByUser retrieves the audit entries of a user, oldest first.
+func (r *AuditRepoPG) ListByUser(ctx context.Context, userID int64) ([]user.AuditEntry, error) {
+	var models []AuditEntrySchema
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&models).Error; err != nil {
+		r.log.Error("failed to list audit entries from db", zap.Error(err), zap.Int64("user_id", userID))
+		return nil, pkgerrors.NewInternalError("failed to list audit entries", err)
+	}
+
+	entries := make([]user.AuditEntry, len(models))
+	for i, m := range models {
+		entries[i] = user.AuditEntry{
+			ID:        m.ID,
+			UserID:    m.UserID,
+			Action:    m.Action,
+			Details:   m.Details,
+			CreatedAt: m.CreatedAt,
+		}
+	}
+
+	return entries, nil
+}
+
+// ListRecentUserIDs returns the IDs of the users with the most recent audit
+// entries, most recent first. Cache administration entries are not about a
+// user and are ignored.
+func (r *AuditRepoPG) ListRecentUserIDs(ctx context.Context, limit int) ([]int64, error) {
+	var ids []int64
+	err := r.db.WithContext(ctx).
+		Model(&AuditEntrySchema{}).
+		Where("user_id <> 0 AND action NOT LIKE ?", "cache.%").
+		Group("user_id").
+		Order("MAX(id) DESC").
+		Limit(limit).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		r.log.Error("failed to list recent audit users from db", zap.Error(err), zap.Int("limit", limit))
+		return nil, pkgerrors.NewInternalError("failed to list recent audit users", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"grpc-user-service/internal/domain/user"
+)
+
+func TestAuditRepoPG_RecordAndList(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAuditRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	entry := &user.AuditEntry{UserID: 1, Action: user.AuditUserCreated}
+	require.NoError(t, repo.Record(ctx, entry))
+	assert.NotZero(t, entry.ID)
+	assert.False(t, entry.CreatedAt.IsZero())
+
+	require.NoError(t, repo.Record(ctx, &user.AuditEntry{UserID: 1, Action: user.AuditUserUpdated, Details: "name,email"}))
+	require.NoError(t, repo.Record(ctx, &user.AuditEntry{UserID: 2, Action: user.AuditUserCreated}))
+
+	entries, err := repo.ListByUser(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, user.AuditUserCreated, entries[0].Action)
+	assert.Equal(t, "name,email", entries[1].Details)
+}
+
+func TestAuditRepoPG_ListRecentUserIDs(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewAuditRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	for _, e := range []user.AuditEntry{
+		{UserID: 1, Action: user.AuditUserCreated},
+		{UserID: 2, Action: user.AuditUserCreated},
+		{UserID: 3, Action: user.AuditUserCreated},
+		{UserID: 1, Action: user.AuditUserUpdated},
+		{UserID: 3, Action: user.AuditCacheEvicted},
+		{Action: user.AuditCacheFlushed},
+	} {
+		require.NoError(t, repo.Record(ctx, &e))
+	}
+
+	ids, err := repo.ListRecentUserIDs(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, ids, "most recent first, ignoring cache entries")
+}
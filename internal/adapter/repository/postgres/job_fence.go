@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStaleFencingToken is returned by fenced writes whose fencing token is
+// lower than one a newer holder of the job lock already wrote with.
+var ErrStaleFencingToken = errors.New("stale fencing token: the job lock was taken over")
+
+// retentionFence is the fence guarding the user retention batches. It is
+// named after the scheduler job, whose lock issues the fencing tokens.
+const retentionFence = "purge-deleted-users"
+
+// staleFenceAlertRuns is the number of consecutive stale rejections after which
+// they are logged as errors. A takeover rejects the old holder once; rejections
+// that keep coming mean the token counter went backwards.
+const staleFenceAlertRuns = 3
+
+// JobFenceSchema represents the database schema for the job_fences table.
+type JobFenceSchema struct {
+	Name      string    `gorm:"primaryKey;size:100"` // Name of the fenced job
+	Token     int64     `gorm:"not null"`            // Highest fencing token seen
+	UpdatedAt time.Time `gorm:"not null"`            // When the token was last written
+}
+
+// TableName specifies the table name for the JobFenceSchema model.
+func (JobFenceSchema) TableName() string {
+	return "job_fences"
+}
+
+// checkFence records token as the latest fencing token of name within tx, or
+// returns ErrStaleFencingToken if a higher token was recorded before. The row
+// stays locked until tx ends, so a batch and a takeover by a newer holder
+// cannot interleave. A token of 0 means the caller holds no fenced lock and
+// skips the check.
+func checkFence(tx *gorm.DB, name string, token int64) error {
+	if token == 0 {
+		return nil
+	}
+
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "job_fences.token <= excluded.token"},
+		}},
+	}).Create(&JobFenceSchema{Name: name, Token: token, UpdatedAt: time.Now().UTC()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleFencingToken
+	}
+	return nil
+}
+
+// LastFencingToken returns the highest fencing token recorded for job, or 0 if
+// none was. It lets the scheduler issue tokens above it, even after Redis lost
+// its token counters.
+func (r *UserRepoPG) LastFencingToken(ctx context.Context, job string) (int64, error) {
+	var token int64
+	err := r.db.WithContext(ctx).Model(&JobFenceSchema{}).
+		Select("COALESCE(MAX(token), 0)").
+		Where("name = ?", job).
+		Scan(&token).Error
+	if err != nil {
+		r.log.Error("failed to get last fencing token from db", zap.String("job", job), zap.Error(err))
+		return 0, err
+	}
+	return token, nil
+}
+
+// recordFence tracks the outcome of a fenced batch and logs rejections.
+// Repeated rejections are logged as errors, since no run can make progress
+// until the token counter is back above the recorded token.
+func (r *UserRepoPG) recordFence(name string, token int64, err error) {
+	if token == 0 {
+		return
+	}
+	if !errors.Is(err, ErrStaleFencingToken) {
+		if err == nil {
+			r.staleFences.Store(0)
+		}
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("fence", name),
+		zap.Int64("fencing_token", token),
+	}
+	if n := r.staleFences.Add(1); n >= staleFenceAlertRuns {
+		r.log.Error("fenced batches keep being rejected as stale, the fencing token counter may have been lost",
+			append(fields, zap.Int64("consecutive_rejections", n))...)
+		return
+	}
+	r.log.Warn("fenced batch rejected, lock taken over", fields...)
+}
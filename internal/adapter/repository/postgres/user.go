@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"sync/atomic"
 
 	"go.uber.org/zap"
+	"golang.org/x/text/language"
 	"gorm.io/gorm"
 
 	"grpc-user-service/internal/domain/user"
@@ -15,8 +19,9 @@ import (
 
 // UserRepoPG implements the Repository interface using PostgreSQL and GORM.
 type UserRepoPG struct {
-	db  *gorm.DB    // GORM database connection
-	log *zap.Logger // Structured logger for database operations
+	db          *gorm.DB     // GORM database connection
+	log         *zap.Logger  // Structured logger for database operations
+	staleFences atomic.Int64 // Consecutive fenced batches rejected as stale
 }
 
 // NewUserRepoPG creates a new instance of UserRepoPG.
@@ -26,9 +31,17 @@ func NewUserRepoPG(db *gorm.DB, log *zap.Logger) *UserRepoPG {
 
 // UserSchema represents the database schema for the users table.
 type UserSchema struct {
-	ID    int64  `gorm:"primaryKey;autoIncrement"` // Unique identifier with auto-increment
-	Name  string `gorm:"not null"`                 // User's full name (required)
-	Email string `gorm:"not null;unique"`          // User's unique email address (required, unique)
+	ID          int64          `gorm:"primaryKey;autoIncrement"`                                                                                                   // Unique identifier with auto-increment
+	Username    *string        `gorm:"size:100;uniqueIndex:idx_users_username_lower,expression:LOWER(username),where:username IS NOT NULL AND deleted_at IS NULL"` // Optional lowercase handle (NULL when unset), unique among active users
+	Name        string         `gorm:"not null"`                                                                                                                   // Computed full name kept for v1 clients (required)
+	GivenName   string         `gorm:"size:100"`                                                                                                                   // User's given (first) name
+	FamilyName  string         `gorm:"size:100"`                                                                                                                   // User's family (last) name
+	DisplayName string         `gorm:"size:100"`                                                                                                                   // Name the user prefers to be shown with
+	Email       string         `gorm:"not null;uniqueIndex:idx_users_email_active,where:deleted_at IS NULL"`                                                       // User's email address (required), unique among active users
+	Phone       string         `gorm:"size:16"`                                                                                                                    // Optional contact number in E.164 format
+	Locale      string         `gorm:"size:35"`                                                                                                                    // Optional BCP 47 language tag
+	Timezone    string         `gorm:"size:64"`                                                                                                                    // Optional IANA time zone name
+	DeletedAt   gorm.DeletedAt `gorm:"index:idx_users_deleted_at,where:deleted_at IS NOT NULL"`                                                                    // Soft-delete marker; purged after the retention period
 }
 
 // TableName specifies the table name for the UserSchema model.
@@ -36,18 +49,68 @@ func (UserSchema) TableName() string {
 	return "users"
 }
 
+// newUserSchema maps a domain user to its database model.
+func newUserSchema(u *user.User) UserSchema {
+	var username *string
+	if u.Username != "" {
+		username = &u.Username
+	}
+
+	return UserSchema{
+		ID:          u.ID,
+		Username:    username,
+		Name:        u.Name,
+		GivenName:   u.GivenName,
+		FamilyName:  u.FamilyName,
+		DisplayName: u.DisplayName,
+		Email:       u.Email,
+		Phone:       u.Phone,
+		Locale:      u.Locale,
+		Timezone:    u.Timezone,
+	}
+}
+
+// toDomain maps the database model to a domain user.
+func (m UserSchema) toDomain() user.User {
+	var username string
+	if m.Username != nil {
+		username = *m.Username
+	}
+
+	return user.User{
+		ID:          m.ID,
+		Username:    username,
+		Name:        m.Name,
+		GivenName:   m.GivenName,
+		FamilyName:  m.FamilyName,
+		DisplayName: m.DisplayName,
+		Email:       m.Email,
+		Phone:       m.Phone,
+		Locale:      m.Locale,
+		Timezone:    m.Timezone,
+	}
+}
+
 // Create inserts a new user into the database.
 func (r *UserRepoPG) Create(ctx context.Context, u *user.User) (int64, error) {
 	if u == nil {
 		return 0, pkgerrors.NewValidationError("user", "user cannot be nil")
 	}
 
-	model := UserSchema{
-		Name:  u.Name,
-		Email: u.Email,
-	}
+	model := newUserSchema(u)
+	model.ID = 0 // ID is assigned by the database
 
-	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model).Error; err != nil {
+			return err
+		}
+		return tx.Create(&UserEmailSchema{UserID: model.ID, Address: model.Email, Primary: true}).Error
+	})
+	if r.isDuplicateKey(err) {
+		r.log.Warn("user email or username already taken", zap.String("email", u.Email))
+		return 0, pkgerrors.NewAlreadyExistsError("user", "email or username already exists")
+	}
+	if err != nil {
 		r.log.Error("failed to create user in db", zap.Error(err), zap.String("email", u.Email))
 		return 0, pkgerrors.NewInternalError("failed to create user", err)
 	}
@@ -57,18 +120,42 @@ func (r *UserRepoPG) Create(ctx context.Context, u *user.User) (int64, error) {
 }
 
 // Update updates an existing user in the database.
-func (r *UserRepoPG) Update(ctx context.Context, u *user.User) (int64, error) {
+// Only non-empty fields and the optional fields named in cleared are written, so
+// attributes left blank in a partial update keep their stored values. A new
+// email becomes the primary address; the previous one is kept as a secondary
+// address.
+func (r *UserRepoPG) Update(ctx context.Context, u *user.User, cleared ...string) (int64, error) {
 	if u == nil {
 		return 0, pkgerrors.NewValidationError("user", "user cannot be nil")
 	}
 
-	model := UserSchema{
-		ID:    u.ID,
-		Name:  u.Name,
-		Email: u.Email,
+	model := newUserSchema(u)
+	columns, err := updatedColumns(model, cleared)
+	if err != nil {
+		return 0, err
 	}
 
-	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(columns) > 0 {
+			// Select writes the listed columns even when empty
+			if err := tx.Model(&UserSchema{ID: u.ID}).Select(columns).Updates(&model).Error; err != nil {
+				return err
+			}
+		}
+		if model.Email == "" {
+			return nil
+		}
+		return promoteEmail(tx, u.ID, model.Email)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Warn("user not found", zap.Int64("id", u.ID))
+		return 0, pkgerrors.NewNotFoundError("user", fmt.Sprintf("user not found: id=%d", u.ID))
+	}
+	if r.isDuplicateKey(err) {
+		r.log.Warn("user email or username already taken", zap.Int64("id", u.ID))
+		return 0, pkgerrors.NewAlreadyExistsError("user", "email or username already exists")
+	}
+	if err != nil {
 		r.log.Error("failed to update user in db", zap.Error(err), zap.Int64("id", u.ID))
 		return 0, pkgerrors.NewInternalError("failed to update user", err)
 	}
@@ -77,13 +164,60 @@ func (r *UserRepoPG) Update(ctx context.Context, u *user.User) (int64, error) {
 	return model.ID, nil
 }
 
-// Delete removes a user from the database by ID.
+// updatedColumns returns the columns an update writes: those with a value in
+// model, except email, and the clearable columns named in cleared.
+func updatedColumns(model UserSchema, cleared []string) ([]string, error) {
+	values := []struct {
+		column string
+		set    bool
+	}{
+		{"username", model.Username != nil},
+		{"name", model.Name != ""},
+		{"given_name", model.GivenName != ""},
+		{"family_name", model.FamilyName != ""},
+		{"display_name", model.DisplayName != ""},
+		{"phone", model.Phone != ""},
+		{"locale", model.Locale != ""},
+		{"timezone", model.Timezone != ""},
+	}
+
+	var columns []string
+	for _, v := range values {
+		if v.set {
+			columns = append(columns, v.column)
+		}
+	}
+	for _, field := range cleared {
+		switch field {
+		case user.FieldPhone, user.FieldLocale, user.FieldTimezone:
+			if !slices.Contains(columns, field) {
+				columns = append(columns, field)
+			}
+		default:
+			return nil, pkgerrors.NewValidationError(field, "field cannot be cleared")
+		}
+	}
+	return columns, nil
+}
+
+// Delete soft-deletes a user by ID. The user row is kept until the retention
+// job purges it; email addresses and linked identities are released right away
+// so they can be claimed by another account.
 func (r *UserRepoPG) Delete(ctx context.Context, id int64) (int64, error) {
 	if id <= 0 {
 		return 0, pkgerrors.NewValidationError("id", "invalid user id")
 	}
 
-	if err := r.db.WithContext(ctx).Delete(&UserSchema{}, id).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", id).Delete(&UserEmailSchema{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&ExternalIdentitySchema{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&UserSchema{}, id).Error
+	})
+	if err != nil {
 		r.log.Error("failed to delete user in db", zap.Error(err), zap.Int64("id", id))
 		return 0, pkgerrors.NewInternalError("failed to delete user", err)
 	}
@@ -104,17 +238,32 @@ func (r *UserRepoPG) GetByID(ctx context.Context, id int64) (*user.User, error)
 		return nil, pkgerrors.NewInternalError("failed to get user", err)
 	}
 
-	return &user.User{
-		ID:    model.ID,
-		Name:  model.Name,
-		Email: model.Email,
-	}, nil
+	u := model.toDomain()
+	return &u, nil
+}
+
+// GetByIDs retrieves the users with the given IDs in a single IN query.
+// IDs without a user are left out of the result.
+func (r *UserRepoPG) GetByIDs(ctx context.Context, ids []int64) (map[int64]*user.User, error) {
+	var models []UserSchema
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&models).Error; err != nil {
+		r.log.Error("failed to get users from db", zap.Error(err), zap.Int("count", len(ids)))
+		return nil, pkgerrors.NewInternalError("failed to get users", err)
+	}
+
+	users := make(map[int64]*user.User, len(models))
+	for _, m := range models {
+		u := m.toDomain()
+		users[u.ID] = &u
+	}
+	return users, nil
 }
 
-// GetByEmail retrieves a user from the database by their email address.
+// GetByEmail retrieves the user owning an email address, primary or secondary.
 func (r *UserRepoPG) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	var model UserSchema
-	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&model).Error; err != nil {
+	owners := r.db.Model(&UserEmailSchema{}).Select("user_id").Where("address = ?", email)
+	if err := r.db.WithContext(ctx).Where("email = ? OR id IN (?)", email, owners).First(&model).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			r.log.Debug("user not found by email", zap.String("email", email))
 			return nil, nil // Return nil for not found case (no error)
@@ -123,15 +272,29 @@ func (r *UserRepoPG) GetByEmail(ctx context.Context, email string) (*user.User,
 		return nil, pkgerrors.NewInternalError("failed to get user by email", err)
 	}
 
-	return &user.User{
-		ID:    model.ID,
-		Name:  model.Name,
-		Email: model.Email,
-	}, nil
+	u := model.toDomain()
+	return &u, nil
+}
+
+// GetByUsername retrieves a user from the database by their lowercase username.
+// It returns nil without an error when no user has that username.
+func (r *UserRepoPG) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	var model UserSchema
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Debug("user not found by username", zap.String("username", username))
+			return nil, nil
+		}
+		r.log.Error("failed to get user by username from db", zap.Error(err), zap.String("username", username))
+		return nil, pkgerrors.NewInternalError("failed to get user by username", err)
+	}
+
+	u := model.toDomain()
+	return &u, nil
 }
 
 // List retrieves users from the database with pagination and search functionality.
-func (r *UserRepoPG) List(ctx context.Context, query string, page, limit int64) ([]user.User, int64, error) {
+func (r *UserRepoPG) List(ctx context.Context, query, locale string, page, limit int64) ([]user.User, int64, error) {
 	// Validate and sanitize search query
 	validatedQuery, err := security.ValidateSearchQuery(query)
 	if err != nil {
@@ -165,20 +328,66 @@ func (r *UserRepoPG) List(ctx context.Context, query string, page, limit int64)
 		return nil, 0, pkgerrors.NewInternalError("failed to count users", err)
 	}
 
-	// Get paginated results
-	if err := dbQuery.Offset(int((page - 1) * limit)).Limit(int(limit)).Find(&models).Error; err != nil {
+	// Get paginated results sorted by family name, then given name.
+	// Users without a family name (legacy single-name records) sort by their full name.
+	if err := dbQuery.Order(r.nameOrder(locale)).Offset(int((page - 1) * limit)).Limit(int(limit)).Find(&models).Error; err != nil {
 		r.log.Error("failed to list users from db", zap.Error(err), zap.String("query", validatedQuery), zap.Int64("page", page), zap.Int64("limit", limit))
 		return nil, 0, pkgerrors.NewInternalError("failed to list users", err)
 	}
 
 	users := make([]user.User, len(models))
 	for i, model := range models {
-		users[i] = user.User{
-			ID:    model.ID,
-			Name:  model.Name,
-			Email: model.Email,
-		}
+		users[i] = model.toDomain()
 	}
 
 	return users, total, nil
 }
+
+// nameOrder returns the ORDER BY clause used for listing users.
+// PostgreSQL sorts with the ICU collation for locale, so that accented and
+// non-Latin names sort the way speakers of that language expect; SQLite (tests)
+// falls back to case-insensitive ordering.
+func (r *UserRepoPG) nameOrder(locale string) string {
+	collation := "NOCASE"
+	if r.db.Name() == "postgres" {
+		collation = strconv.Quote(icuCollation(locale))
+	}
+
+	return fmt.Sprintf("COALESCE(NULLIF(family_name, ''), name) COLLATE %[1]s, given_name COLLATE %[1]s, id", collation)
+}
+
+// icuCollations lists the languages with tailored ICU collation rules that
+// PostgreSQL creates at initdb time as "<language>-x-icu".
+var icuCollations = map[string]bool{
+	"cs": true, "da": true, "de": true, "es": true, "et": true, "fi": true,
+	"fr": true, "hr": true, "hu": true, "is": true, "it": true, "ja": true,
+	"ko": true, "lt": true, "lv": true, "nb": true, "nl": true, "pl": true,
+	"pt": true, "ro": true, "ru": true, "sk": true, "sl": true, "sv": true,
+	"tr": true, "uk": true, "vi": true, "zh": true,
+}
+
+// icuCollation returns the ICU collation for the language of locale. Locales
+// without tailored rules, and an empty locale, use the ICU root collation,
+// which matches the index from migration 000003.
+func icuCollation(locale string) string {
+	if tag, err := language.Parse(locale); err == nil {
+		if base, _ := tag.Base(); icuCollations[base.String()] {
+			return base.String() + "-x-icu"
+		}
+	}
+
+	return "und-x-icu"
+}
+
+// isDuplicateKey reports whether err is a unique constraint violation. The
+// uniqueness checks of the usecase run before the write and can be answered
+// from a stale cache, so the constraint has the final say.
+func (r *UserRepoPG) isDuplicateKey(err error) bool {
+	if err == nil {
+		return false
+	}
+	if translator, ok := r.db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
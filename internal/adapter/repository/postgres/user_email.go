@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// UserEmailSchema represents the database schema for the user_emails table.
+// users.email mirrors the address flagged as primary.
+type UserEmailSchema struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement"`      // Unique identifier with auto-increment
+	UserID     int64      `gorm:"not null;index"`                // Owning user
+	Address    string     `gorm:"size:254;not null;uniqueIndex"` // Email address, unique across all users
+	Primary    bool       `gorm:"column:is_primary;not null"`    // Whether this is the user's primary address
+	Verified   bool       `gorm:"not null"`                      // Whether ownership of the address was confirmed
+	VerifiedAt *time.Time `gorm:"column:verified_at"`            // When the address was verified (NULL when unverified)
+	CreatedAt  time.Time  `gorm:"not null"`                      // When the address was added
+
+	VerificationTokenHash *string    `gorm:"size:64"` // SHA-256 of the pending verification token (NULL when none)
+	VerificationExpiresAt *time.Time // When the pending verification token expires
+}
+
+// TableName specifies the table name for the UserEmailSchema model.
+func (UserEmailSchema) TableName() string {
+	return "user_emails"
+}
+
+// toDomain maps the database model to a domain email.
+func (m UserEmailSchema) toDomain() user.Email {
+	var verifiedAt time.Time
+	if m.VerifiedAt != nil {
+		verifiedAt = *m.VerifiedAt
+	}
+
+	return user.Email{
+		UserID:     m.UserID,
+		Address:    m.Address,
+		Primary:    m.Primary,
+		Verified:   m.Verified,
+		VerifiedAt: verifiedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+// ListEmails retrieves all email addresses of a user, primary first, then in the order they were added.
+func (r *UserRepoPG) ListEmails(ctx context.Context, userID int64) ([]user.Email, error) {
+	var models []UserEmailSchema
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("is_primary DESC, id").Find(&models).Error; err != nil {
+		r.log.Error("failed to list user emails from db", zap.Error(err), zap.Int64("user_id", userID))
+		return nil, pkgerrors.NewInternalError("failed to list user emails", err)
+	}
+
+	emails := make([]user.Email, len(models))
+	for i, model := range models {
+		emails[i] = model.toDomain()
+	}
+
+	return emails, nil
+}
+
+// AddEmail adds a secondary, unverified email address to a user.
+func (r *UserRepoPG) AddEmail(ctx context.Context, userID int64, address string) error {
+	model := UserEmailSchema{UserID: userID, Address: address}
+	err := r.db.WithContext(ctx).Create(&model).Error
+	if r.isDuplicateKey(err) {
+		r.log.Warn("user email already taken", zap.Int64("user_id", userID))
+		return pkgerrors.NewAlreadyExistsError("user", "email already exists")
+	}
+	if err != nil {
+		r.log.Error("failed to add user email in db", zap.Error(err), zap.Int64("user_id", userID))
+		return pkgerrors.NewInternalError("failed to add user email", err)
+	}
+
+	r.log.Info("user email added in db", zap.Int64("user_id", userID))
+	return nil
+}
+
+// RemoveEmail removes a secondary email address from a user.
+// The primary address is never removed.
+func (r *UserRepoPG) RemoveEmail(ctx context.Context, userID int64, address string) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND address = ? AND is_primary = ?", userID, address, false).
+		Delete(&UserEmailSchema{})
+	if result.Error != nil {
+		r.log.Error("failed to remove user email in db", zap.Error(result.Error), zap.Int64("user_id", userID))
+		return pkgerrors.NewInternalError("failed to remove user email", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return pkgerrors.NewNotFoundError("email", fmt.Sprintf("email not found: user_id=%d", userID))
+	}
+
+	r.log.Info("user email removed in db", zap.Int64("user_id", userID))
+	return nil
+}
+
+// SetPrimaryEmail promotes one of the user's addresses to primary and copies it to users.email.
+func (r *UserRepoPG) SetPrimaryEmail(ctx context.Context, userID int64, address string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var model UserEmailSchema
+		if err := tx.Where("user_id = ? AND address = ?", userID, address).First(&model).Error; err != nil {
+			return err
+		}
+		return promoteEmail(tx, userID, address)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return pkgerrors.NewNotFoundError("email", fmt.Sprintf("email not found: user_id=%d", userID))
+	}
+	if err != nil {
+		r.log.Error("failed to set primary user email in db", zap.Error(err), zap.Int64("user_id", userID))
+		return pkgerrors.NewInternalError("failed to set primary user email", err)
+	}
+
+	r.log.Info("user primary email changed in db", zap.Int64("user_id", userID))
+	return nil
+}
+
+// SetEmailVerification stores the hash of a verification token for one of the
+// user's unverified addresses, replacing any pending token.
+func (r *UserRepoPG) SetEmailVerification(ctx context.Context, userID int64, address, tokenHash string, expiresAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&UserEmailSchema{}).
+		Where("user_id = ? AND address = ? AND verified = ?", userID, address, false).
+		Updates(map[string]any{"verification_token_hash": tokenHash, "verification_expires_at": expiresAt})
+	if result.Error != nil {
+		r.log.Error("failed to store email verification token in db", zap.Error(result.Error), zap.Int64("user_id", userID))
+		return pkgerrors.NewInternalError("failed to store email verification token", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return pkgerrors.NewNotFoundError("email", fmt.Sprintf("unverified email not found: user_id=%d", userID))
+	}
+
+	return nil
+}
+
+// VerifyEmail marks one of the user's addresses as verified when tokenHash
+// matches its pending, unexpired verification token, and consumes the token.
+func (r *UserRepoPG) VerifyEmail(ctx context.Context, userID int64, address, tokenHash string) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&UserEmailSchema{}).
+		Where("user_id = ? AND address = ? AND verification_token_hash = ? AND verification_expires_at > ?",
+			userID, address, tokenHash, now).
+		Updates(map[string]any{
+			"verified":                true,
+			"verified_at":             now,
+			"verification_token_hash": nil,
+			"verification_expires_at": nil,
+		})
+	if result.Error != nil {
+		r.log.Error("failed to verify user email in db", zap.Error(result.Error), zap.Int64("user_id", userID))
+		return pkgerrors.NewInternalError("failed to verify user email", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		r.log.Warn("invalid email verification token", zap.Int64("user_id", userID))
+		return pkgerrors.NewValidationError("token", "invalid or expired verification token")
+	}
+
+	r.log.Info("user email verified in db", zap.Int64("user_id", userID))
+	return nil
+}
+
+// promoteEmail makes address the primary email of a user within tx, adding it
+// when the user does not own it yet. The previous primary address is kept as a
+// secondary address and users.email is kept in sync.
+func promoteEmail(tx *gorm.DB, userID int64, address string) error {
+	if err := tx.Model(&UserEmailSchema{}).
+		Where("user_id = ? AND address <> ?", userID, address).
+		Update("is_primary", false).Error; err != nil {
+		return err
+	}
+
+	result := tx.Model(&UserEmailSchema{}).
+		Where("user_id = ? AND address = ?", userID, address).
+		Update("is_primary", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if err := tx.Create(&UserEmailSchema{UserID: userID, Address: address, Primary: true}).Error; err != nil {
+			return err
+		}
+	}
+
+	result = tx.Model(&UserSchema{ID: userID}).Update("email", address)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+func TestUserRepoPG_Create_AddsPrimaryEmail(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	emails, err := repo.ListEmails(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, emails, 1)
+	assert.Equal(t, "john@example.com", emails[0].Address)
+	assert.True(t, emails[0].Primary)
+	assert.False(t, emails[0].Verified)
+}
+
+func TestUserRepoPG_Update_NewEmailKeepsOldAddress(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{Name: "John Doe", Email: "john@old.example.com"})
+	require.NoError(t, err)
+
+	_, err = repo.Update(ctx, &user.User{ID: id, Email: "john@new.example.com"})
+	require.NoError(t, err)
+
+	got, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "john@new.example.com", got.Email)
+
+	emails, err := repo.ListEmails(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, emails, 2)
+	assert.Equal(t, "john@new.example.com", emails[0].Address)
+	assert.True(t, emails[0].Primary)
+	assert.Equal(t, "john@old.example.com", emails[1].Address)
+	assert.False(t, emails[1].Primary)
+
+	// The previous address still resolves to the user
+	owner, err := repo.GetByEmail(ctx, "john@old.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, owner)
+	assert.Equal(t, id, owner.ID)
+}
+
+func TestUserRepoPG_Update_UnknownUserWithEmail(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+
+	_, err := repo.Update(context.Background(), &user.User{ID: 42, Email: "ghost@example.com"})
+	require.Error(t, err)
+	assert.IsType(t, &pkgerrors.NotFoundError{}, err)
+
+	owner, err := repo.GetByEmail(context.Background(), "ghost@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, owner)
+}
+
+func TestUserRepoPG_Emails_Lifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.AddEmail(ctx, id, "john@work.example.com"))
+
+	// Addresses are unique across users
+	otherID, err := repo.Create(ctx, &user.User{Name: "Jane Smith", Email: "jane@example.com"})
+	require.NoError(t, err)
+	assert.IsType(t, &pkgerrors.AlreadyExistsError{}, repo.AddEmail(ctx, otherID, "john@work.example.com"))
+
+	owner, err := repo.GetByEmail(ctx, "john@work.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, owner)
+	assert.Equal(t, id, owner.ID)
+
+	require.NoError(t, repo.SetEmailVerification(ctx, id, "john@work.example.com", "hash", time.Now().Add(time.Hour)))
+	require.NoError(t, repo.VerifyEmail(ctx, id, "john@work.example.com", "hash"))
+	require.NoError(t, repo.SetPrimaryEmail(ctx, id, "john@work.example.com"))
+
+	got, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "john@work.example.com", got.Email)
+
+	emails, err := repo.ListEmails(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, emails, 2)
+	assert.Equal(t, "john@work.example.com", emails[0].Address)
+	assert.True(t, emails[0].Primary)
+	assert.True(t, emails[0].Verified)
+	assert.False(t, emails[0].VerifiedAt.IsZero())
+
+	// The primary address cannot be removed
+	err = repo.RemoveEmail(ctx, id, "john@work.example.com")
+	assert.IsType(t, &pkgerrors.NotFoundError{}, err)
+
+	require.NoError(t, repo.RemoveEmail(ctx, id, "john@example.com"))
+	owner, err = repo.GetByEmail(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, owner)
+
+	err = repo.SetPrimaryEmail(ctx, id, "jane@example.com")
+	assert.IsType(t, &pkgerrors.NotFoundError{}, err)
+}
+
+func TestUserRepoPG_Delete_RemovesEmails(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+	require.NoError(t, repo.AddEmail(ctx, id, "john@work.example.com"))
+
+	_, err = repo.Delete(ctx, id)
+	require.NoError(t, err)
+
+	emails, err := repo.ListEmails(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, emails)
+
+	// Released addresses can be claimed again
+	_, err = repo.Create(ctx, &user.User{Name: "John Again", Email: "john@work.example.com"})
+	assert.NoError(t, err)
+}
+
+func TestUserRepoPG_VerifyEmail_ConsumesToken(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	// Without a pending token nothing can be verified
+	assert.IsType(t, &pkgerrors.ValidationError{}, repo.VerifyEmail(ctx, id, "john@example.com", "hash"))
+
+	// Expired tokens are rejected
+	require.NoError(t, repo.SetEmailVerification(ctx, id, "john@example.com", "hash", time.Now().Add(-time.Minute)))
+	assert.IsType(t, &pkgerrors.ValidationError{}, repo.VerifyEmail(ctx, id, "john@example.com", "hash"))
+
+	// A new token replaces the pending one
+	require.NoError(t, repo.SetEmailVerification(ctx, id, "john@example.com", "new-hash", time.Now().Add(time.Hour)))
+	assert.IsType(t, &pkgerrors.ValidationError{}, repo.VerifyEmail(ctx, id, "john@example.com", "hash"))
+	require.NoError(t, repo.VerifyEmail(ctx, id, "john@example.com", "new-hash"))
+
+	emails, err := repo.ListEmails(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, emails, 1)
+	assert.True(t, emails[0].Verified)
+
+	// Tokens are single-use, and verified addresses get no new ones
+	var model UserEmailSchema
+	require.NoError(t, db.Where("user_id = ?", id).First(&model).Error)
+	assert.Nil(t, model.VerificationTokenHash)
+	assert.Nil(t, model.VerificationExpiresAt)
+	assert.IsType(t, &pkgerrors.ValidationError{}, repo.VerifyEmail(ctx, id, "john@example.com", "new-hash"))
+	assert.IsType(t, &pkgerrors.NotFoundError{},
+		repo.SetEmailVerification(ctx, id, "john@example.com", "hash", time.Now().Add(time.Hour)))
+}
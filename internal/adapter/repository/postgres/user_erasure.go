@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// ErasedUserName replaces the name of an erased user.
+const ErasedUserName = "Erased user"
+
+// ErasureSchema represents the database schema for the erasure_tombstones table.
+type ErasureSchema struct {
+	UserID   int64     `gorm:"primaryKey;autoIncrement:false"` // Erased user
+	Reason   string    `gorm:"size:255"`                       // Optional reference for the request
+	ErasedAt time.Time `gorm:"not null"`                       // When the erasure ran
+}
+
+// TableName specifies the table name for the ErasureSchema model.
+func (ErasureSchema) TableName() string {
+	return "erasure_tombstones"
+}
+
+// erasedEmail returns the placeholder address of an erased user. It keeps
+// users.email unique and non-null while using a reserved, undeliverable domain.
+func erasedEmail(id int64) string {
+	return fmt.Sprintf("erased-%d@erased.invalid", id)
+}
+
+// Erase irreversibly anonymizes a user in a single transaction: personal
+// columns are overwritten, email addresses and linked identities are deleted
+// and a tombstone is written. Audit entries hold no personal data and are kept.
+// If the user was already erased, the existing tombstone is returned.
+func (r *UserRepoPG) Erase(ctx context.Context, id int64, reason string) (*user.Erasure, error) {
+	var tombstone ErasureSchema
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.First(&tombstone, "user_id = ?", id).Error
+		if err == nil {
+			return nil // Already erased
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		tombstone, err = anonymizeUser(tx, id, reason, false)
+		return err
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Warn("user not found", zap.Int64("id", id))
+		return nil, pkgerrors.NewNotFoundError("user", fmt.Sprintf("user not found: id=%d", id))
+	}
+	if err != nil {
+		r.log.Error("failed to erase user in db", zap.Error(err), zap.Int64("id", id))
+		return nil, pkgerrors.NewInternalError("failed to erase user", err)
+	}
+
+	r.log.Info("user erased in db", zap.Int64("id", id))
+	return &user.Erasure{UserID: tombstone.UserID, Reason: tombstone.Reason, ErasedAt: tombstone.ErasedAt}, nil
+}
+
+// anonymizeUser overwrites the personal columns of a user, deletes their email
+// addresses and linked identities and writes a tombstone. It returns
+// gorm.ErrRecordNotFound when no user row matches. Soft-deleted users are
+// only matched when includeDeleted is set.
+func anonymizeUser(tx *gorm.DB, id int64, reason string, includeDeleted bool) (ErasureSchema, error) {
+	users := tx
+	if includeDeleted {
+		users = tx.Unscoped()
+	}
+
+	result := users.Model(&UserSchema{ID: id}).Updates(map[string]any{
+		"username":     nil,
+		"name":         ErasedUserName,
+		"given_name":   "",
+		"family_name":  "",
+		"display_name": "",
+		"email":        erasedEmail(id),
+		"phone":        "",
+		"locale":       "",
+		"timezone":     "",
+	})
+	if result.Error != nil {
+		return ErasureSchema{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErasureSchema{}, gorm.ErrRecordNotFound
+	}
+
+	if err := tx.Where("user_id = ?", id).Delete(&UserEmailSchema{}).Error; err != nil {
+		return ErasureSchema{}, err
+	}
+	if err := tx.Where("user_id = ?", id).Delete(&ExternalIdentitySchema{}).Error; err != nil {
+		return ErasureSchema{}, err
+	}
+
+	tombstone := ErasureSchema{UserID: id, Reason: reason, ErasedAt: time.Now().UTC()}
+	return tombstone, tx.Create(&tombstone).Error
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+func TestUserRepoPG_Erase(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{
+		Username:   "johndoe",
+		Name:       "John Doe",
+		GivenName:  "John",
+		FamilyName: "Doe",
+		Email:      "john@example.com",
+		Phone:      "+84901234567",
+		Locale:     "vi-VN",
+		Timezone:   "Asia/Ho_Chi_Minh",
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.AddEmail(ctx, id, "john@work.example.com"))
+	require.NoError(t, repo.LinkIdentity(ctx, &user.Identity{UserID: id, Provider: "google", Subject: "abc123"}))
+
+	erasure, err := repo.Erase(ctx, id, "ticket-42")
+	require.NoError(t, err)
+	assert.Equal(t, id, erasure.UserID)
+	assert.Equal(t, "ticket-42", erasure.Reason)
+	assert.False(t, erasure.ErasedAt.IsZero())
+
+	got, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, user.User{ID: id, Name: ErasedUserName, Email: erasedEmail(id)}, *got)
+
+	// Nothing personal remains reachable
+	for _, email := range []string{"john@example.com", "john@work.example.com"} {
+		owner, err := repo.GetByEmail(ctx, email)
+		require.NoError(t, err)
+		assert.Nil(t, owner, email)
+	}
+	owner, err := repo.GetByUsername(ctx, "johndoe")
+	require.NoError(t, err)
+	assert.Nil(t, owner)
+	owner, err = repo.GetByIdentity(ctx, "google", "abc123")
+	require.NoError(t, err)
+	assert.Nil(t, owner)
+
+	// Erasing again returns the original tombstone
+	again, err := repo.Erase(ctx, id, "ticket-43")
+	require.NoError(t, err)
+	assert.Equal(t, "ticket-42", again.Reason)
+	assert.True(t, erasure.ErasedAt.Equal(again.ErasedAt))
+}
+
+func TestUserRepoPG_Erase_NotFound(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+
+	_, err := repo.Erase(context.Background(), 42, "")
+	assert.IsType(t, &pkgerrors.NotFoundError{}, err)
+
+	var count int64
+	require.NoError(t, db.Model(&ErasureSchema{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// ExternalIdentitySchema represents the database schema for the external_identities table.
+type ExternalIdentitySchema struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`                                               // Unique identifier with auto-increment
+	UserID    int64     `gorm:"not null;index"`                                                         // Linked user
+	Provider  string    `gorm:"size:64;not null;uniqueIndex:idx_external_identities_provider_subject"`  // Lowercase identity provider name
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_external_identities_provider_subject"` // Provider's identifier for the account
+	CreatedAt time.Time `gorm:"not null"`                                                               // When the identity was linked
+}
+
+// TableName specifies the table name for the ExternalIdentitySchema model.
+func (ExternalIdentitySchema) TableName() string {
+	return "external_identities"
+}
+
+// toDomain maps the database model to a domain identity.
+func (m ExternalIdentitySchema) toDomain() user.Identity {
+	return user.Identity{
+		UserID:    m.UserID,
+		Provider:  m.Provider,
+		Subject:   m.Subject,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// LinkIdentity links an external identity to a user.
+func (r *UserRepoPG) LinkIdentity(ctx context.Context, identity *user.Identity) error {
+	if identity == nil {
+		return pkgerrors.NewValidationError("identity", "identity cannot be nil")
+	}
+
+	model := ExternalIdentitySchema{UserID: identity.UserID, Provider: identity.Provider, Subject: identity.Subject}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		r.log.Error("failed to link identity in db", zap.Error(err), zap.Int64("user_id", identity.UserID), zap.String("provider", identity.Provider))
+		return pkgerrors.NewInternalError("failed to link identity", err)
+	}
+
+	r.log.Info("identity linked in db", zap.Int64("user_id", identity.UserID), zap.String("provider", identity.Provider))
+	return nil
+}
+
+// UnlinkIdentity removes an external identity from a user.
+func (r *UserRepoPG) UnlinkIdentity(ctx context.Context, userID int64, provider, subject string) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND provider = ? AND subject = ?", userID, provider, subject).
+		Delete(&ExternalIdentitySchema{})
+	if result.Error != nil {
+		r.log.Error("failed to unlink identity in db", zap.Error(result.Error), zap.Int64("user_id", userID), zap.String("provider", provider))
+		return pkgerrors.NewInternalError("failed to unlink identity", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return pkgerrors.NewNotFoundError("identity", fmt.Sprintf("identity not found: user_id=%d provider=%s", userID, provider))
+	}
+
+	r.log.Info("identity unlinked in db", zap.Int64("user_id", userID), zap.String("provider", provider))
+	return nil
+}
+
+// GetByIdentity retrieves the user an external identity is linked to.
+// It returns nil without an error when the identity is not linked.
+func (r *UserRepoPG) GetByIdentity(ctx context.Context, provider, subject string) (*user.User, error) {
+	var identity ExternalIdentitySchema
+	if err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Debug("identity not linked", zap.String("provider", provider))
+			return nil, nil
+		}
+		r.log.Error("failed to get identity from db", zap.Error(err), zap.String("provider", provider))
+		return nil, pkgerrors.NewInternalError("failed to get user by identity", err)
+	}
+
+	return r.GetByID(ctx, identity.UserID)
+}
+
+// ListIdentities retrieves the external identities linked to a user in the order they were linked.
+func (r *UserRepoPG) ListIdentities(ctx context.Context, userID int64) ([]user.Identity, error) {
+	var models []ExternalIdentitySchema
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&models).Error; err != nil {
+		r.log.Error("failed to list identities from db", zap.Error(err), zap.Int64("user_id", userID))
+		return nil, pkgerrors.NewInternalError("failed to list identities", err)
+	}
+
+	identities := make([]user.Identity, len(models))
+	for i, model := range models {
+		identities[i] = model.toDomain()
+	}
+
+	return identities, nil
+}
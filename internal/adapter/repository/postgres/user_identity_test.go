@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+func TestUserRepoPG_Identities_Lifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+	otherID, err := repo.Create(ctx, &user.User{Name: "Jane Smith", Email: "jane@example.com"})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.LinkIdentity(ctx, &user.Identity{UserID: id, Provider: "google", Subject: "1234567890"}))
+	require.NoError(t, repo.LinkIdentity(ctx, &user.Identity{UserID: id, Provider: "github", Subject: "1234567890"}))
+
+	// Each (provider, subject) pair belongs to one user
+	assert.Error(t, repo.LinkIdentity(ctx, &user.Identity{UserID: otherID, Provider: "google", Subject: "1234567890"}))
+
+	got, err := repo.GetByIdentity(ctx, "google", "1234567890")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, id, got.ID)
+
+	missing, err := repo.GetByIdentity(ctx, "google", "unknown")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	identities, err := repo.ListIdentities(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, identities, 2)
+	assert.Equal(t, "google", identities[0].Provider)
+	assert.False(t, identities[0].CreatedAt.IsZero())
+
+	// Another user's identity cannot be unlinked
+	err = repo.UnlinkIdentity(ctx, otherID, "google", "1234567890")
+	assert.IsType(t, &pkgerrors.NotFoundError{}, err)
+
+	require.NoError(t, repo.UnlinkIdentity(ctx, id, "google", "1234567890"))
+	missing, err = repo.GetByIdentity(ctx, "google", "1234567890")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	_, err = repo.Delete(ctx, id)
+	require.NoError(t, err)
+	identities, err = repo.ListIdentities(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, identities)
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// RetentionReason is recorded on tombstones written by AnonymizeDeleted.
+const RetentionReason = "retention"
+
+// PurgeDeleted permanently removes up to limit users that were soft-deleted
+// before cutoff and returns how many were removed. Each call runs in its own
+// short transaction so callers can work through a backlog in batches without
+// holding long locks. Erasure tombstones and audit entries are kept.
+// A non-zero fencing token guards the batch; see checkFence.
+func (r *UserRepoPG) PurgeDeleted(ctx context.Context, cutoff time.Time, limit int, token int64) (int64, error) {
+	var purged int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkFence(tx, retentionFence, token); err != nil {
+			return err
+		}
+
+		ids, err := deletedUserIDs(tx, cutoff, limit, false)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		if err := tx.Where("user_id IN ?", ids).Delete(&UserEmailSchema{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id IN ?", ids).Delete(&ExternalIdentitySchema{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&UserSchema{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	r.recordFence(retentionFence, token, err)
+	if errors.Is(err, ErrStaleFencingToken) {
+		return 0, err
+	}
+	if err != nil {
+		r.log.Error("failed to purge deleted users in db", zap.Error(err), zap.Time("cutoff", cutoff))
+		return 0, pkgerrors.NewInternalError("failed to purge deleted users", err)
+	}
+
+	if purged > 0 {
+		r.log.Info("deleted users purged from db", zap.Int64("count", purged), zap.Time("cutoff", cutoff))
+	}
+	return purged, nil
+}
+
+// AnonymizeDeleted erases up to limit users that were soft-deleted before
+// cutoff and have not been erased yet, and returns how many were erased.
+// The anonymized rows stay soft-deleted, keeping their IDs reserved.
+// A non-zero fencing token guards the batch; see checkFence.
+func (r *UserRepoPG) AnonymizeDeleted(ctx context.Context, cutoff time.Time, limit int, token int64) (int64, error) {
+	var erased int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkFence(tx, retentionFence, token); err != nil {
+			return err
+		}
+
+		ids, err := deletedUserIDs(tx, cutoff, limit, true)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if _, err := anonymizeUser(tx, id, RetentionReason, true); err != nil {
+				return err
+			}
+			erased++
+		}
+		return nil
+	})
+	r.recordFence(retentionFence, token, err)
+	if errors.Is(err, ErrStaleFencingToken) {
+		return 0, err
+	}
+	if err != nil {
+		r.log.Error("failed to anonymize deleted users in db", zap.Error(err), zap.Time("cutoff", cutoff))
+		return 0, pkgerrors.NewInternalError("failed to anonymize deleted users", err)
+	}
+
+	if erased > 0 {
+		r.log.Info("deleted users anonymized in db", zap.Int64("count", erased), zap.Time("cutoff", cutoff))
+	}
+	return erased, nil
+}
+
+// deletedUserIDs returns the IDs of up to limit users soft-deleted before
+// cutoff, oldest first. With skipErased set, already erased users are excluded.
+func deletedUserIDs(tx *gorm.DB, cutoff time.Time, limit int, skipErased bool) ([]int64, error) {
+	query := tx.Unscoped().Model(&UserSchema{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+	if skipErased {
+		query = query.Where("id NOT IN (?)", tx.Model(&ErasureSchema{}).Select("user_id"))
+	}
+
+	var ids []int64
+	err := query.Order("deleted_at, id").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
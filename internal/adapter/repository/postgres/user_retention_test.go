@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// createDeletedUser creates a user and soft-deletes it at deletedAt.
+func createDeletedUser(t *testing.T, db *gorm.DB, repo *UserRepoPG, n int, deletedAt time.Time) int64 {
+	ctx := context.Background()
+	id, err := repo.Create(ctx, &user.User{Name: fmt.Sprintf("User %d", n), Email: fmt.Sprintf("user%d@example.com", n)})
+	require.NoError(t, err)
+	_, err = repo.Delete(ctx, id)
+	require.NoError(t, err)
+	require.NoError(t, db.Unscoped().Model(&UserSchema{}).Where("id = ?", id).Update("deleted_at", deletedAt).Error)
+	return id
+}
+
+func TestUserRepoPG_Delete_IsSoft(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+	_, err = repo.Delete(ctx, id)
+	require.NoError(t, err)
+
+	_, err = repo.GetByID(ctx, id)
+	assert.IsType(t, &pkgerrors.NotFoundError{}, err)
+
+	var model UserSchema
+	require.NoError(t, db.Unscoped().First(&model, id).Error)
+	assert.True(t, model.DeletedAt.Valid)
+}
+
+func TestUserRepoPG_Create_ReusesDeletedEmailAndUsername(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{Username: "john", Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	// Email and username stay unique among active users
+	_, err = repo.Create(ctx, &user.User{Name: "Other", Email: "john@example.com"})
+	assert.IsType(t, &pkgerrors.AlreadyExistsError{}, err)
+	_, err = repo.Create(ctx, &user.User{Username: "john", Name: "Other", Email: "other@example.com"})
+	assert.IsType(t, &pkgerrors.AlreadyExistsError{}, err)
+
+	_, err = repo.Delete(ctx, id)
+	require.NoError(t, err)
+
+	newID, err := repo.Create(ctx, &user.User{Username: "john", Name: "John Again", Email: "john@example.com"})
+	require.NoError(t, err)
+	assert.NotEqual(t, id, newID)
+
+	got, err := repo.GetByUsername(ctx, "john")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, newID, got.ID)
+}
+
+func TestUserRepoPG_PurgeDeleted(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	for i := 1; i <= 3; i++ {
+		createDeletedUser(t, db, repo, i, now.AddDate(0, 0, -40))
+	}
+	recent := createDeletedUser(t, db, repo, 4, now.AddDate(0, 0, -1))
+	active, err := repo.Create(ctx, &user.User{Name: "Active", Email: "active@example.com"})
+	require.NoError(t, err)
+
+	cutoff := now.AddDate(0, 0, -30)
+
+	// Batches are bounded by the limit
+	n, err := repo.PurgeDeleted(ctx, cutoff, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	n, err = repo.PurgeDeleted(ctx, cutoff, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = repo.PurgeDeleted(ctx, cutoff, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	var remaining []int64
+	require.NoError(t, db.Unscoped().Model(&UserSchema{}).Order("id").Pluck("id", &remaining).Error)
+	assert.Equal(t, []int64{recent, active}, remaining)
+}
+
+func TestUserRepoPG_AnonymizeDeleted(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	id := createDeletedUser(t, db, repo, 1, now.AddDate(0, 0, -40))
+	cutoff := now.AddDate(0, 0, -30)
+
+	n, err := repo.AnonymizeDeleted(ctx, cutoff, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	var model UserSchema
+	require.NoError(t, db.Unscoped().First(&model, id).Error)
+	assert.Equal(t, ErasedUserName, model.Name)
+	assert.Equal(t, erasedEmail(id), model.Email)
+	assert.True(t, model.DeletedAt.Valid)
+
+	var tombstone ErasureSchema
+	require.NoError(t, db.First(&tombstone, "user_id = ?", id).Error)
+	assert.Equal(t, RetentionReason, tombstone.Reason)
+
+	// Already anonymized users are not processed again
+	n, err = repo.AnonymizeDeleted(ctx, cutoff, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
+
+func TestUserRepoPG_PurgeDeleted_RejectsStaleFencingToken(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	for i := 1; i <= 3; i++ {
+		createDeletedUser(t, db, repo, i, now.AddDate(0, 0, -40))
+	}
+	cutoff := now.AddDate(0, 0, -30)
+
+	// The holder of token 1 runs a batch, then loses its lock to token 2
+	n, err := repo.PurgeDeleted(ctx, cutoff, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = repo.PurgeDeleted(ctx, cutoff, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	// Further batches of the old holder are rejected without writing
+	n, err = repo.PurgeDeleted(ctx, cutoff, 1, 1)
+	assert.ErrorIs(t, err, ErrStaleFencingToken)
+	assert.Zero(t, n)
+	_, err = repo.AnonymizeDeleted(ctx, cutoff, 1, 1)
+	assert.ErrorIs(t, err, ErrStaleFencingToken)
+
+	var remaining int64
+	require.NoError(t, db.Unscoped().Model(&UserSchema{}).Count(&remaining).Error)
+	assert.Equal(t, int64(1), remaining)
+
+	// The new holder keeps going with the same token
+	n, err = repo.PurgeDeleted(ctx, cutoff, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	var fence JobFenceSchema
+	require.NoError(t, db.First(&fence, "name = ?", retentionFence).Error)
+	assert.Equal(t, int64(2), fence.Token)
+}
+
+func TestUserRepoPG_LastFencingToken(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	token, err := repo.LastFencingToken(ctx, retentionFence)
+	require.NoError(t, err)
+	assert.Zero(t, token)
+
+	now := time.Now().UTC()
+	createDeletedUser(t, db, repo, 1, now.AddDate(0, 0, -40))
+	_, err = repo.PurgeDeleted(ctx, now.AddDate(0, 0, -30), 10, 7)
+	require.NoError(t, err)
+
+	token, err = repo.LastFencingToken(ctx, retentionFence)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), token)
+
+	// A counter restarted from 1 is rejected until it is reseeded above 7
+	_, err = repo.PurgeDeleted(ctx, now.AddDate(0, 0, -30), 10, 1)
+	assert.ErrorIs(t, err, ErrStaleFencingToken)
+	_, err = repo.PurgeDeleted(ctx, now.AddDate(0, 0, -30), 10, token+1)
+	require.NoError(t, err)
+}
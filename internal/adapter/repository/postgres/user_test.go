@@ -18,7 +18,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	require.NoError(t, err)
 
 	// Migrate the schema
-	err = db.AutoMigrate(&UserSchema{})
+	err = db.AutoMigrate(&UserSchema{}, &UserEmailSchema{}, &ExternalIdentitySchema{}, &ErasureSchema{}, &AuditEntrySchema{}, &JobFenceSchema{})
 	require.NoError(t, err)
 
 	return db
@@ -119,7 +119,7 @@ func TestUserRepoPG_List_SQLInjectionProtection(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
-			users, total, err := repo.List(ctx, tt.query, 1, 10)
+			users, total, err := repo.List(ctx, tt.query, "", 1, 10)
 
 			if tt.expectError {
 				require.Error(t, err)
@@ -183,7 +183,7 @@ func TestUserRepoPG_List_WildcardEscaping(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
-			users, total, err := repo.List(ctx, tt.query, 1, 10)
+			users, total, err := repo.List(ctx, tt.query, "", 1, 10)
 
 			require.NoError(t, err)
 			assert.NotNil(t, users)
@@ -235,7 +235,7 @@ func TestUserRepoPG_List_CaseInsensitiveSearch(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
-			users, total, err := repo.List(ctx, tt.query, 1, 10)
+			users, total, err := repo.List(ctx, tt.query, "", 1, 10)
 
 			require.NoError(t, err)
 			assert.NotNil(t, users)
@@ -244,3 +244,156 @@ func TestUserRepoPG_List_CaseInsensitiveSearch(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepoPG_Update_PartialKeepsContactFields(t *testing.T) {
+	db := setupTestDB(t)
+	logger := zaptest.NewLogger(t)
+	repo := NewUserRepoPG(db, logger)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Phone:    "+84901234567",
+		Locale:   "vi-VN",
+		Timezone: "Asia/Ho_Chi_Minh",
+	})
+	require.NoError(t, err)
+
+	_, err = repo.Update(ctx, &user.User{ID: id, Name: "John Updated"})
+	require.NoError(t, err)
+
+	got, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "John Updated", got.Name)
+	assert.Equal(t, "john@example.com", got.Email)
+	assert.Equal(t, "+84901234567", got.Phone)
+	assert.Equal(t, "vi-VN", got.Locale)
+	assert.Equal(t, "Asia/Ho_Chi_Minh", got.Timezone)
+}
+
+func TestUserRepoPG_Update_ClearsContactFields(t *testing.T) {
+	db := setupTestDB(t)
+	logger := zaptest.NewLogger(t)
+	repo := NewUserRepoPG(db, logger)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{Name: "John Doe", Email: "john@example.com", Locale: "vi-VN"})
+	require.NoError(t, err)
+
+	_, err = repo.Update(ctx, &user.User{ID: id, Phone: "+84901234567"})
+	require.NoError(t, err)
+	got, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "+84901234567", got.Phone)
+
+	_, err = repo.Update(ctx, &user.User{ID: id}, user.FieldPhone)
+	require.NoError(t, err)
+	got, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, got.Phone)
+	assert.Equal(t, "vi-VN", got.Locale, "fields not named in clear are kept")
+	assert.Equal(t, "John Doe", got.Name)
+
+	_, err = repo.Update(ctx, &user.User{ID: id}, "name")
+	assert.Error(t, err, "only optional fields can be cleared")
+}
+
+func TestUserRepoPG_List_SortedByFamilyName(t *testing.T) {
+	db := setupTestDB(t)
+	logger := zaptest.NewLogger(t)
+	repo := NewUserRepoPG(db, logger)
+	ctx := context.Background()
+
+	testUsers := []user.User{
+		{Name: "Zoe Adams", GivenName: "Zoe", FamilyName: "Adams", Email: "zoe@example.com"},
+		{Name: "Li", DisplayName: "Li", Email: "li@example.com"},
+		{Name: "Anna Young", GivenName: "Anna", FamilyName: "young", Email: "anna@example.com"},
+		{Name: "Bob Adams", GivenName: "Bob", FamilyName: "Adams", Email: "bob@example.com"},
+	}
+	for _, u := range testUsers {
+		_, err := repo.Create(ctx, &u)
+		require.NoError(t, err)
+	}
+
+	users, total, err := repo.List(ctx, "", "", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+
+	names := make([]string, len(users))
+	for i, u := range users {
+		names[i] = u.Name
+	}
+	assert.Equal(t, []string{"Bob Adams", "Zoe Adams", "Li", "Anna Young"}, names)
+}
+
+func TestICUCollation(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"", "und-x-icu"},
+		{"sv", "sv-x-icu"},
+		{"sv-SE", "sv-x-icu"},
+		{"de-AT", "de-x-icu"},
+		{"zh-Hant-TW", "zh-x-icu"},
+		{"en-US", "und-x-icu"},
+		{"not a locale", "und-x-icu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			assert.Equal(t, tt.want, icuCollation(tt.locale))
+		})
+	}
+}
+
+func TestUserRepoPG_GetByUsername(t *testing.T) {
+	db := setupTestDB(t)
+	logger := zaptest.NewLogger(t)
+	repo := NewUserRepoPG(db, logger)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &user.User{Username: "johndoe", Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	// Users without a username are stored as NULL and do not collide
+	_, err = repo.Create(ctx, &user.User{Name: "Jane Smith", Email: "jane@example.com"})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &user.User{Name: "Bob Brown", Email: "bob@example.com"})
+	require.NoError(t, err)
+
+	got, err := repo.GetByUsername(ctx, "johndoe")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, id, got.ID)
+	assert.Equal(t, "johndoe", got.Username)
+
+	missing, err := repo.GetByUsername(ctx, "nobody")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	_, err = repo.Create(ctx, &user.User{Username: "johndoe", Name: "John Again", Email: "john2@example.com"})
+	assert.Error(t, err)
+}
+
+func TestUserRepoPG_GetByIDs(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepoPG(db, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	john, err := repo.Create(ctx, &user.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+	jane, err := repo.Create(ctx, &user.User{Name: "Jane Smith", Email: "jane@example.com"})
+	require.NoError(t, err)
+	deleted, err := repo.Create(ctx, &user.User{Name: "Bob Brown", Email: "bob@example.com"})
+	require.NoError(t, err)
+	_, err = repo.Delete(ctx, deleted)
+	require.NoError(t, err)
+
+	users, err := repo.GetByIDs(ctx, []int64{john, jane, deleted, 999})
+	require.NoError(t, err)
+	require.Len(t, users, 2, "missing and deleted users are left out")
+	assert.Equal(t, "john@example.com", users[john].Email)
+	assert.Equal(t, "Jane Smith", users[jane].Name)
+}
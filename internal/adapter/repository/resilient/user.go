@@ -0,0 +1,150 @@
+// Package resilient guards repositories with circuit breakers, so that a
+// failing database is skipped immediately instead of every caller waiting for
+// it to time out.
+package resilient
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domain "grpc-user-service/internal/domain/user"
+	"grpc-user-service/internal/usecase/user"
+	"grpc-user-service/pkg/breaker"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// UserRepository implements user.Repository by calling a wrapped repository
+// through a circuit breaker. Only internal errors count as failures of the
+// database: not found, conflicts and validation errors are answers. While the
+// circuit is open calls return an InternalError wrapping breaker.ErrOpen, so
+// that callers handle it like any other database failure.
+type UserRepository struct {
+	repo    user.Repository
+	breaker *breaker.Breaker
+}
+
+// NewUserRepository creates a UserRepository around repo.
+func NewUserRepository(repo user.Repository, b *breaker.Breaker) user.Repository {
+	return &UserRepository{repo: repo, breaker: b}
+}
+
+// guard calls fn through the breaker of r.
+func guard[T any](r *UserRepository, fn func() (T, error)) (T, error) {
+	var value T
+	var result error
+	err := r.breaker.Do(func() error {
+		value, result = fn()
+		var internalErr *pkgerrors.InternalError
+		if errors.As(result, &internalErr) {
+			return result
+		}
+		return nil
+	})
+	if errors.Is(err, breaker.ErrOpen) {
+		return value, pkgerrors.NewInternalError("database unavailable", err)
+	}
+	return value, result
+}
+
+// guardErr is guard for calls returning only an error.
+func guardErr(r *UserRepository, fn func() error) error {
+	_, err := guard(r, func() (struct{}, error) { return struct{}{}, fn() })
+	return err
+}
+
+// Create inserts a new user.
+func (r *UserRepository) Create(ctx context.Context, u *domain.User) (int64, error) {
+	return guard(r, func() (int64, error) { return r.repo.Create(ctx, u) })
+}
+
+// GetByID retrieves a user by ID.
+func (r *UserRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	return guard(r, func() (*domain.User, error) { return r.repo.GetByID(ctx, id) })
+}
+
+// GetByEmail retrieves a user by email.
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return guard(r, func() (*domain.User, error) { return r.repo.GetByEmail(ctx, email) })
+}
+
+// GetByUsername retrieves a user by username.
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return guard(r, func() (*domain.User, error) { return r.repo.GetByUsername(ctx, username) })
+}
+
+// Update updates an existing user.
+func (r *UserRepository) Update(ctx context.Context, u *domain.User, cleared ...string) (int64, error) {
+	return guard(r, func() (int64, error) { return r.repo.Update(ctx, u, cleared...) })
+}
+
+// Delete deletes a user by ID.
+func (r *UserRepository) Delete(ctx context.Context, id int64) (int64, error) {
+	return guard(r, func() (int64, error) { return r.repo.Delete(ctx, id) })
+}
+
+// List lists users with pagination and search.
+func (r *UserRepository) List(ctx context.Context, query, locale string, page, limit int64) ([]domain.User, int64, error) {
+	var total int64
+	users, err := guard(r, func() ([]domain.User, error) {
+		users, t, err := r.repo.List(ctx, query, locale, page, limit)
+		total = t
+		return users, err
+	})
+	return users, total, err
+}
+
+// ListEmails lists the email addresses of a user.
+func (r *UserRepository) ListEmails(ctx context.Context, userID int64) ([]domain.Email, error) {
+	return guard(r, func() ([]domain.Email, error) { return r.repo.ListEmails(ctx, userID) })
+}
+
+// AddEmail adds a secondary email address.
+func (r *UserRepository) AddEmail(ctx context.Context, userID int64, address string) error {
+	return guardErr(r, func() error { return r.repo.AddEmail(ctx, userID, address) })
+}
+
+// RemoveEmail removes a secondary email address.
+func (r *UserRepository) RemoveEmail(ctx context.Context, userID int64, address string) error {
+	return guardErr(r, func() error { return r.repo.RemoveEmail(ctx, userID, address) })
+}
+
+// SetPrimaryEmail promotes an owned address to primary.
+func (r *UserRepository) SetPrimaryEmail(ctx context.Context, userID int64, address string) error {
+	return guardErr(r, func() error { return r.repo.SetPrimaryEmail(ctx, userID, address) })
+}
+
+// SetEmailVerification stores the hash of a verification token for an owned address.
+func (r *UserRepository) SetEmailVerification(ctx context.Context, userID int64, address, tokenHash string, expiresAt time.Time) error {
+	return guardErr(r, func() error { return r.repo.SetEmailVerification(ctx, userID, address, tokenHash, expiresAt) })
+}
+
+// VerifyEmail marks an owned address as verified, consuming its verification token.
+func (r *UserRepository) VerifyEmail(ctx context.Context, userID int64, address, tokenHash string) error {
+	return guardErr(r, func() error { return r.repo.VerifyEmail(ctx, userID, address, tokenHash) })
+}
+
+// Erase irreversibly anonymizes a user.
+func (r *UserRepository) Erase(ctx context.Context, id int64, reason string) (*domain.Erasure, error) {
+	return guard(r, func() (*domain.Erasure, error) { return r.repo.Erase(ctx, id, reason) })
+}
+
+// LinkIdentity links an external identity to a user.
+func (r *UserRepository) LinkIdentity(ctx context.Context, identity *domain.Identity) error {
+	return guardErr(r, func() error { return r.repo.LinkIdentity(ctx, identity) })
+}
+
+// UnlinkIdentity unlinks an external identity from a user.
+func (r *UserRepository) UnlinkIdentity(ctx context.Context, userID int64, provider, subject string) error {
+	return guardErr(r, func() error { return r.repo.UnlinkIdentity(ctx, userID, provider, subject) })
+}
+
+// GetByIdentity retrieves a user by linked identity.
+func (r *UserRepository) GetByIdentity(ctx context.Context, provider, subject string) (*domain.User, error) {
+	return guard(r, func() (*domain.User, error) { return r.repo.GetByIdentity(ctx, provider, subject) })
+}
+
+// ListIdentities lists the external identities linked to a user.
+func (r *UserRepository) ListIdentities(ctx context.Context, userID int64) ([]domain.Identity, error) {
+	return guard(r, func() ([]domain.Identity, error) { return r.repo.ListIdentities(ctx, userID) })
+}
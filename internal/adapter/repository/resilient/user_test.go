@@ -0,0 +1,58 @@
+package resilient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"gorm.io/gorm"
+
+	"grpc-user-service/internal/adapter/repository/postgres"
+	domain "grpc-user-service/internal/domain/user"
+	"grpc-user-service/pkg/breaker"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+func TestUserRepository_OpensOnDatabaseFailures(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&postgres.UserSchema{}, &postgres.UserEmailSchema{}))
+
+	logger := zaptest.NewLogger(t)
+	b := breaker.New("test-database", breaker.Config{FailureThreshold: 2, OpenTimeout: time.Minute, HalfOpenProbes: 1}, logger)
+	repo := NewUserRepository(postgres.NewUserRepoPG(db, logger), b)
+	ctx := context.Background()
+
+	id, err := repo.Create(ctx, &domain.User{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	// Not found is an answer, not a failure
+	for range 3 {
+		_, err := repo.GetByID(ctx, id+1)
+		var notFoundErr *pkgerrors.NotFoundError
+		require.True(t, errors.As(err, &notFoundErr))
+	}
+	assert.Equal(t, breaker.Closed, b.State())
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	for range 2 {
+		_, err := repo.GetByID(ctx, id)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, breaker.ErrOpen)
+	}
+	assert.Equal(t, breaker.Open, b.State())
+
+	// Rejected calls look like database failures to callers
+	_, _, err = repo.List(ctx, "", "", 1, 10)
+	var internalErr *pkgerrors.InternalError
+	require.True(t, errors.As(err, &internalErr))
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+}
@@ -1,9 +1,14 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/spf13/viper"
+
+	"grpc-user-service/pkg/scheduler"
 )
 
 // Config holds all configuration parameters for the application.
@@ -13,7 +18,13 @@ type Config struct {
 	App       AppConfig       // Application server settings
 	Logger    LoggerConfig    // Logger configuration
 	Redis     RedisConfig     // Redis connection settings
+	Cache     CacheConfig     // User cache tiers
 	RateLimit RateLimitConfig // Rate limiting configuration
+	Username  UsernameConfig  // Username rules
+	Retention RetentionConfig // Retention of deleted records
+	Scheduler SchedulerConfig // Background job scheduling
+	Breaker   BreakerConfig   // Circuit breakers around Redis and PostgreSQL
+	Admin     AdminConfig     // Admin API access
 }
 
 // DatabaseConfig holds configuration parameters for database connection.
@@ -29,6 +40,10 @@ type DatabaseConfig struct {
 	MaxIdleConns    int    `mapstructure:"DB_MAX_IDLE_CONNS"`     // Maximum number of idle connections
 	ConnMaxLifetime int    `mapstructure:"DB_CONN_MAX_LIFETIME"`  // Maximum lifetime of a connection in seconds
 	ConnMaxIdleTime int    `mapstructure:"DB_CONN_MAX_IDLE_TIME"` // Maximum idle time of a connection in seconds
+	BatchEnabled    bool   `mapstructure:"DB_BATCH_ENABLED"`      // Coalesce concurrent lookups by ID into IN queries
+	BatchWaitMs     int    `mapstructure:"DB_BATCH_WAIT_MS"`      // How long a batch collects IDs in milliseconds
+	BatchMaxSize    int    `mapstructure:"DB_BATCH_MAX_SIZE"`     // Number of IDs that dispatches a batch at once
+	BatchTimeoutMs  int    `mapstructure:"DB_BATCH_TIMEOUT_MS"`   // Time limit of a batch query in milliseconds
 }
 
 // AppConfig holds configuration parameters for the application servers.
@@ -55,22 +70,115 @@ type LoggerConfig struct {
 // RedisConfig holds configuration parameters for Redis connection.
 // These settings are used to establish connection with Redis for caching and rate limiting.
 type RedisConfig struct {
-	Host        string `mapstructure:"REDIS_HOST"`              // Redis server host
-	Port        string `mapstructure:"REDIS_PORT"`              // Redis server port
-	Password    string `mapstructure:"REDIS_PASSWORD"`          // Redis password (empty for no auth)
-	DB          int    `mapstructure:"REDIS_DB"`                // Redis database number
-	CacheTTL    int    `mapstructure:"REDIS_CACHE_TTL_SECONDS"` // Cache TTL in seconds
-	MaxRetries  int    `mapstructure:"REDIS_MAX_RETRIES"`       // Maximum number of retries
-	PoolSize    int    `mapstructure:"REDIS_POOL_SIZE"`         // Connection pool size
-	MinIdleConn int    `mapstructure:"REDIS_MIN_IDLE_CONN"`     // Minimum idle connections
-}
-
-// RateLimitConfig holds configuration parameters for Token Bucket rate limiting.
-// It controls the token refill rate and maximum burst capacity.
+	Host         string `mapstructure:"REDIS_HOST"`                     // Redis server host
+	Port         string `mapstructure:"REDIS_PORT"`                     // Redis server port
+	Password     string `mapstructure:"REDIS_PASSWORD"`                 // Redis password (empty for no auth)
+	DB           int    `mapstructure:"REDIS_DB"`                       // Redis database number
+	CacheTTL     int    `mapstructure:"REDIS_CACHE_TTL_SECONDS"`        // Cache TTL in seconds
+	NegativeTTL  int    `mapstructure:"REDIS_NEGATIVE_TTL_SECONDS"`     // TTL of negative (not found) entries in seconds
+	TTLJitter    int    `mapstructure:"REDIS_CACHE_TTL_JITTER_PERCENT"` // Random spread of user TTLs, in percent of the TTL
+	StaleWindow  int    `mapstructure:"REDIS_STALE_WINDOW_SECONDS"`     // Seconds a user is still served past its TTL while it is refreshed
+	EarlyRefresh int    `mapstructure:"REDIS_EARLY_REFRESH_SECONDS"`    // Scale of probabilistic refresh before expiry in seconds (0 disables)
+	MaxRetries   int    `mapstructure:"REDIS_MAX_RETRIES"`              // Maximum number of retries
+	PoolSize     int    `mapstructure:"REDIS_POOL_SIZE"`                // Connection pool size
+	MinIdleConn  int    `mapstructure:"REDIS_MIN_IDLE_CONN"`            // Minimum idle connections
+}
+
+// CacheConfig holds configuration parameters for the cache backend, the
+// in-process cache tier in front of Redis and for serving cached users while
+// the database is down.
+type CacheConfig struct {
+	Backend                 string `mapstructure:"CACHE_BACKEND"`                     // Where users are cached (redis, memory, none)
+	MemoryMaxEntries        int    `mapstructure:"CACHE_MEMORY_MAX_ENTRIES"`          // Maximum number of entries of each kind with the memory backend
+	LocalEnabled            bool   `mapstructure:"CACHE_LOCAL_ENABLED"`               // Enable/disable the in-process tier
+	LocalMaxEntries         int    `mapstructure:"CACHE_LOCAL_MAX_ENTRIES"`           // Maximum number of users held in memory
+	LocalTTLSeconds         int    `mapstructure:"CACHE_LOCAL_TTL_SECONDS"`           // Lifetime of in-memory entries in seconds
+	DegradedModeEnabled     bool   `mapstructure:"CACHE_DEGRADED_MODE_ENABLED"`       // Serve last known good users when the database fails
+	LastKnownGoodTTLSeconds int    `mapstructure:"CACHE_LAST_KNOWN_GOOD_TTL_SECONDS"` // Lifetime of last known good copies in seconds
+	EncryptionKeys          string `mapstructure:"CACHE_ENCRYPTION_KEYS"`             // Comma-separated id:base64-key pairs; the first encrypts, all decrypt
+	AcceptPlaintext         bool   `mapstructure:"CACHE_ACCEPT_PLAINTEXT"`            // Keep reading unencrypted entries while encryption is rolled out
+	WarmupEnabled           bool   `mapstructure:"CACHE_WARMUP_ENABLED"`              // Preload recently active users at startup
+	WarmupUsers             int    `mapstructure:"CACHE_WARMUP_USERS"`                // Maximum number of users preloaded
+	WarmupTimeoutSeconds    int    `mapstructure:"CACHE_WARMUP_TIMEOUT_SECONDS"`      // Time limit of the warmup in seconds
+}
+
+// EncryptionKey is an AES key identified by an ID.
+type EncryptionKey struct {
+	ID     string
+	Secret []byte
+}
+
+// RateLimitConfig holds configuration parameters for rate limiting.
+// It controls the token refill rate, maximum burst capacity and algorithm.
 type RateLimitConfig struct {
 	RequestsPerSecond float64 `mapstructure:"RATE_LIMIT_REQUESTS_PER_SECOND"` // Token refill rate (tokens per second)
 	BurstCapacity     int     `mapstructure:"RATE_LIMIT_BURST_CAPACITY"`      // Maximum tokens in bucket (allows burst traffic)
+	Algorithm         string  `mapstructure:"RATE_LIMIT_ALGORITHM"`           // token_bucket, sliding_window or gcra, unless a policy says otherwise
+	FailureMode       string  `mapstructure:"RATE_LIMIT_FAILURE_MODE"`        // open, closed or local: how requests are limited while Redis fails
+	LocalShare        int     `mapstructure:"RATE_LIMIT_LOCAL_SHARE_PERCENT"` // Percent of each limit enforced per replica while failing local
 	Enabled           bool    `mapstructure:"RATE_LIMIT_ENABLED"`             // Enable/disable rate limiting
+	PolicyFile        string  `mapstructure:"RATE_LIMIT_POLICY_FILE"`         // Optional file of per-method, per-route and per-tier limits
+	APIKeys           string  `mapstructure:"RATE_LIMIT_API_KEYS"`            // Comma-separated id:tier:key triples identifying clients
+}
+
+// APIKey is a client API key with the rate limit tier of its client.
+type APIKey struct {
+	ID     string
+	Tier   string
+	Secret string
+}
+
+// UsernameConfig holds the rules applied to user handles.
+// Usernames are case-insensitive; the charset is matched against the lowercased value.
+type UsernameConfig struct {
+	MinLength int    `mapstructure:"USERNAME_MIN_LENGTH"` // Minimum username length in characters
+	MaxLength int    `mapstructure:"USERNAME_MAX_LENGTH"` // Maximum username length in characters
+	Charset   string `mapstructure:"USERNAME_CHARSET"`    // Allowed characters as a regexp character class body (e.g. a-z0-9._-)
+	Reserved  string `mapstructure:"USERNAME_RESERVED"`   // Comma-separated list of usernames that cannot be claimed
+}
+
+// RetentionConfig holds the retention policy for soft-deleted users and the
+// schedule of the background job enforcing it.
+type RetentionConfig struct {
+	Enabled           bool   `mapstructure:"RETENTION_ENABLED"`             // Enable/disable the retention job
+	DeletedUserDays   int    `mapstructure:"RETENTION_DELETED_USER_DAYS"`   // Days a soft-deleted user is kept before it is purged
+	Mode              string `mapstructure:"RETENTION_MODE"`                // What happens to expired users (delete, anonymize)
+	BatchSize         int    `mapstructure:"RETENTION_BATCH_SIZE"`          // Rows processed per transaction
+	Schedule          string `mapstructure:"RETENTION_SCHEDULE"`            // Cron expression for job runs
+	RunTimeoutSeconds int    `mapstructure:"RETENTION_RUN_TIMEOUT_SECONDS"` // Maximum duration of a run attempt
+}
+
+// SchedulerConfig holds settings shared by all background jobs.
+type SchedulerConfig struct {
+	Enabled             bool `mapstructure:"SCHEDULER_ENABLED"`               // Enable/disable background jobs on this instance
+	JitterSeconds       int  `mapstructure:"SCHEDULER_JITTER_SECONDS"`        // Upper bound of the random delay added to each activation
+	Retries             int  `mapstructure:"SCHEDULER_RETRIES"`               // Additional attempts after a failed run
+	RetryBackoffSeconds int  `mapstructure:"SCHEDULER_RETRY_BACKOFF_SECONDS"` // Delay between attempts
+}
+
+// BreakerConfig holds the thresholds of the circuit breakers around Redis
+// and PostgreSQL calls.
+type BreakerConfig struct {
+	Enabled          bool `mapstructure:"BREAKER_ENABLED"`           // Enable/disable the circuit breakers
+	FailureThreshold int  `mapstructure:"BREAKER_FAILURE_THRESHOLD"` // Consecutive failures that open a circuit
+	OpenSeconds      int  `mapstructure:"BREAKER_OPEN_SECONDS"`      // How long an open circuit skips calls before probing
+	HalfOpenProbes   int  `mapstructure:"BREAKER_HALF_OPEN_PROBES"`  // Successful probe calls that close a circuit again
+}
+
+// AdminConfig holds access settings for the admin API.
+type AdminConfig struct {
+	Token string `mapstructure:"ADMIN_API_TOKEN"` // Bearer token for /admin routes, user export and erase, and issuing email verification tokens; they are disabled when empty
+}
+
+// ReservedList returns the reserved usernames as a slice.
+func (c *UsernameConfig) ReservedList() []string {
+	var reserved []string
+	for _, r := range strings.Split(c.Reserved, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			reserved = append(reserved, r)
+		}
+	}
+	return reserved
 }
 
 // LoadConfig reads configuration from file or environment variables.
@@ -108,6 +216,10 @@ func LoadConfig(path string) (*Config, error) {
 	config.DB.MaxIdleConns = viper.GetInt("DB_MAX_IDLE_CONNS")
 	config.DB.ConnMaxLifetime = viper.GetInt("DB_CONN_MAX_LIFETIME")
 	config.DB.ConnMaxIdleTime = viper.GetInt("DB_CONN_MAX_IDLE_TIME")
+	config.DB.BatchEnabled = viper.GetBool("DB_BATCH_ENABLED")
+	config.DB.BatchWaitMs = viper.GetInt("DB_BATCH_WAIT_MS")
+	config.DB.BatchMaxSize = viper.GetInt("DB_BATCH_MAX_SIZE")
+	config.DB.BatchTimeoutMs = viper.GetInt("DB_BATCH_TIMEOUT_MS")
 
 	config.App.GRPCPort = viper.GetString("GRPC_PORT")
 	config.App.HTTPPort = viper.GetString("HTTP_PORT")
@@ -127,13 +239,59 @@ func LoadConfig(path string) (*Config, error) {
 	config.Redis.Password = viper.GetString("REDIS_PASSWORD")
 	config.Redis.DB = viper.GetInt("REDIS_DB")
 	config.Redis.CacheTTL = viper.GetInt("REDIS_CACHE_TTL_SECONDS")
+	config.Redis.NegativeTTL = viper.GetInt("REDIS_NEGATIVE_TTL_SECONDS")
+	config.Redis.TTLJitter = viper.GetInt("REDIS_CACHE_TTL_JITTER_PERCENT")
+	config.Redis.StaleWindow = viper.GetInt("REDIS_STALE_WINDOW_SECONDS")
+	config.Redis.EarlyRefresh = viper.GetInt("REDIS_EARLY_REFRESH_SECONDS")
 	config.Redis.MaxRetries = viper.GetInt("REDIS_MAX_RETRIES")
 	config.Redis.PoolSize = viper.GetInt("REDIS_POOL_SIZE")
 	config.Redis.MinIdleConn = viper.GetInt("REDIS_MIN_IDLE_CONN")
 
+	config.Cache.Backend = viper.GetString("CACHE_BACKEND")
+	config.Cache.MemoryMaxEntries = viper.GetInt("CACHE_MEMORY_MAX_ENTRIES")
+	config.Cache.LocalEnabled = viper.GetBool("CACHE_LOCAL_ENABLED")
+	config.Cache.LocalMaxEntries = viper.GetInt("CACHE_LOCAL_MAX_ENTRIES")
+	config.Cache.LocalTTLSeconds = viper.GetInt("CACHE_LOCAL_TTL_SECONDS")
+	config.Cache.DegradedModeEnabled = viper.GetBool("CACHE_DEGRADED_MODE_ENABLED")
+	config.Cache.LastKnownGoodTTLSeconds = viper.GetInt("CACHE_LAST_KNOWN_GOOD_TTL_SECONDS")
+	config.Cache.EncryptionKeys = viper.GetString("CACHE_ENCRYPTION_KEYS")
+	config.Cache.AcceptPlaintext = viper.GetBool("CACHE_ACCEPT_PLAINTEXT")
+	config.Cache.WarmupEnabled = viper.GetBool("CACHE_WARMUP_ENABLED")
+	config.Cache.WarmupUsers = viper.GetInt("CACHE_WARMUP_USERS")
+	config.Cache.WarmupTimeoutSeconds = viper.GetInt("CACHE_WARMUP_TIMEOUT_SECONDS")
+
 	config.RateLimit.RequestsPerSecond = viper.GetFloat64("RATE_LIMIT_REQUESTS_PER_SECOND")
 	config.RateLimit.BurstCapacity = viper.GetInt("RATE_LIMIT_BURST_CAPACITY")
+	config.RateLimit.Algorithm = viper.GetString("RATE_LIMIT_ALGORITHM")
+	config.RateLimit.FailureMode = viper.GetString("RATE_LIMIT_FAILURE_MODE")
+	config.RateLimit.LocalShare = viper.GetInt("RATE_LIMIT_LOCAL_SHARE_PERCENT")
 	config.RateLimit.Enabled = viper.GetBool("RATE_LIMIT_ENABLED")
+	config.RateLimit.PolicyFile = viper.GetString("RATE_LIMIT_POLICY_FILE")
+	config.RateLimit.APIKeys = viper.GetString("RATE_LIMIT_API_KEYS")
+
+	config.Username.MinLength = viper.GetInt("USERNAME_MIN_LENGTH")
+	config.Username.MaxLength = viper.GetInt("USERNAME_MAX_LENGTH")
+	config.Username.Charset = viper.GetString("USERNAME_CHARSET")
+	config.Username.Reserved = viper.GetString("USERNAME_RESERVED")
+
+	config.Retention.Enabled = viper.GetBool("RETENTION_ENABLED")
+	config.Retention.DeletedUserDays = viper.GetInt("RETENTION_DELETED_USER_DAYS")
+	config.Retention.Mode = viper.GetString("RETENTION_MODE")
+	config.Retention.BatchSize = viper.GetInt("RETENTION_BATCH_SIZE")
+	config.Retention.Schedule = viper.GetString("RETENTION_SCHEDULE")
+	config.Retention.RunTimeoutSeconds = viper.GetInt("RETENTION_RUN_TIMEOUT_SECONDS")
+
+	config.Scheduler.Enabled = viper.GetBool("SCHEDULER_ENABLED")
+	config.Scheduler.JitterSeconds = viper.GetInt("SCHEDULER_JITTER_SECONDS")
+	config.Scheduler.Retries = viper.GetInt("SCHEDULER_RETRIES")
+	config.Scheduler.RetryBackoffSeconds = viper.GetInt("SCHEDULER_RETRY_BACKOFF_SECONDS")
+
+	config.Breaker.Enabled = viper.GetBool("BREAKER_ENABLED")
+	config.Breaker.FailureThreshold = viper.GetInt("BREAKER_FAILURE_THRESHOLD")
+	config.Breaker.OpenSeconds = viper.GetInt("BREAKER_OPEN_SECONDS")
+	config.Breaker.HalfOpenProbes = viper.GetInt("BREAKER_HALF_OPEN_PROBES")
+
+	config.Admin.Token = viper.GetString("ADMIN_API_TOKEN")
 
 	return &config, nil
 }
@@ -152,6 +310,11 @@ func setDefaults() {
 	viper.SetDefault("DB_MAX_IDLE_CONNS", 5)
 	viper.SetDefault("DB_CONN_MAX_LIFETIME", 300)  // 5 minutes in seconds
 	viper.SetDefault("DB_CONN_MAX_IDLE_TIME", 600) // 10 minutes in seconds
+	// Lookup batching defaults
+	viper.SetDefault("DB_BATCH_ENABLED", true)
+	viper.SetDefault("DB_BATCH_WAIT_MS", 2)
+	viper.SetDefault("DB_BATCH_MAX_SIZE", 100)
+	viper.SetDefault("DB_BATCH_TIMEOUT_MS", 5000)
 
 	viper.SetDefault("GRPC_PORT", "50051")
 	viper.SetDefault("HTTP_PORT", "8080")
@@ -180,14 +343,67 @@ func setDefaults() {
 	viper.SetDefault("REDIS_PASSWORD", "")
 	viper.SetDefault("REDIS_DB", 0)
 	viper.SetDefault("REDIS_CACHE_TTL_SECONDS", 300) // 5 minutes
+	viper.SetDefault("REDIS_NEGATIVE_TTL_SECONDS", 30)
+	viper.SetDefault("REDIS_CACHE_TTL_JITTER_PERCENT", 10)
+	viper.SetDefault("REDIS_STALE_WINDOW_SECONDS", 60)
+	viper.SetDefault("REDIS_EARLY_REFRESH_SECONDS", 10)
 	viper.SetDefault("REDIS_MAX_RETRIES", 3)
 	viper.SetDefault("REDIS_POOL_SIZE", 10)
 	viper.SetDefault("REDIS_MIN_IDLE_CONN", 5)
 
+	// Cache defaults
+	viper.SetDefault("CACHE_BACKEND", "redis")
+	viper.SetDefault("CACHE_MEMORY_MAX_ENTRIES", 100000)
+	viper.SetDefault("CACHE_LOCAL_ENABLED", true)
+	viper.SetDefault("CACHE_LOCAL_MAX_ENTRIES", 10000)
+	viper.SetDefault("CACHE_LOCAL_TTL_SECONDS", 30)
+	viper.SetDefault("CACHE_DEGRADED_MODE_ENABLED", false)
+	viper.SetDefault("CACHE_LAST_KNOWN_GOOD_TTL_SECONDS", 86400) // 24 hours
+	viper.SetDefault("CACHE_ENCRYPTION_KEYS", "")                // Encryption disabled
+	viper.SetDefault("CACHE_ACCEPT_PLAINTEXT", false)            // Unencrypted entries are misses once encryption is enabled
+	viper.SetDefault("CACHE_WARMUP_ENABLED", true)
+	viper.SetDefault("CACHE_WARMUP_USERS", 1000)
+	viper.SetDefault("CACHE_WARMUP_TIMEOUT_SECONDS", 60)
+
 	// Rate limit defaults (Token Bucket)
 	viper.SetDefault("RATE_LIMIT_REQUESTS_PER_SECOND", 10.0)
 	viper.SetDefault("RATE_LIMIT_BURST_CAPACITY", 20) // Allow burst up to 2x the rate
+	viper.SetDefault("RATE_LIMIT_ALGORITHM", "token_bucket")
+	viper.SetDefault("RATE_LIMIT_FAILURE_MODE", "local")   // Keep limiting, per replica, while Redis fails
+	viper.SetDefault("RATE_LIMIT_LOCAL_SHARE_PERCENT", 50) // Suits two replicas; use 100 / replicas
 	viper.SetDefault("RATE_LIMIT_ENABLED", true)
+	viper.SetDefault("RATE_LIMIT_POLICY_FILE", "") // Same limit for every method and route
+	viper.SetDefault("RATE_LIMIT_API_KEYS", "")    // All clients limited by IP
+
+	// Username defaults
+	viper.SetDefault("USERNAME_MIN_LENGTH", 3)
+	viper.SetDefault("USERNAME_MAX_LENGTH", 30)
+	viper.SetDefault("USERNAME_CHARSET", "a-z0-9._-")
+	viper.SetDefault("USERNAME_RESERVED",
+		"admin,administrator,root,system,api,www,mail,support,help,security,staff,moderator,me,self,user,users,by-username,null,undefined")
+
+	// Retention defaults
+	viper.SetDefault("RETENTION_ENABLED", true)
+	viper.SetDefault("RETENTION_DELETED_USER_DAYS", 30)
+	viper.SetDefault("RETENTION_MODE", "delete")
+	viper.SetDefault("RETENTION_BATCH_SIZE", 500)
+	viper.SetDefault("RETENTION_SCHEDULE", "0 * * * *")    // Hourly
+	viper.SetDefault("RETENTION_RUN_TIMEOUT_SECONDS", 600) // 10 minutes
+
+	// Scheduler defaults
+	viper.SetDefault("SCHEDULER_ENABLED", true)
+	viper.SetDefault("SCHEDULER_JITTER_SECONDS", 30)
+	viper.SetDefault("SCHEDULER_RETRIES", 2)
+	viper.SetDefault("SCHEDULER_RETRY_BACKOFF_SECONDS", 10)
+
+	// Circuit breaker defaults
+	viper.SetDefault("BREAKER_ENABLED", true)
+	viper.SetDefault("BREAKER_FAILURE_THRESHOLD", 5)
+	viper.SetDefault("BREAKER_OPEN_SECONDS", 10)
+	viper.SetDefault("BREAKER_HALF_OPEN_PROBES", 1)
+
+	// Admin defaults
+	viper.SetDefault("ADMIN_API_TOKEN", "")
 }
 
 // Validate validates all configuration parameters.
@@ -206,9 +422,24 @@ func (c *Config) Validate() error {
 	if err := c.Redis.Validate(); err != nil {
 		return err
 	}
+	if err := c.Cache.Validate(); err != nil {
+		return err
+	}
 	if err := c.RateLimit.Validate(); err != nil {
 		return err
 	}
+	if err := c.Username.Validate(); err != nil {
+		return err
+	}
+	if err := c.Retention.Validate(); err != nil {
+		return err
+	}
+	if err := c.Scheduler.Validate(); err != nil {
+		return err
+	}
+	if err := c.Breaker.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -245,6 +476,17 @@ func (c *DatabaseConfig) Validate() error {
 	if c.ConnMaxIdleTime <= 0 {
 		return fmt.Errorf("DB_CONN_MAX_IDLE_TIME must be positive, got %d", c.ConnMaxIdleTime)
 	}
+	if c.BatchEnabled {
+		if c.BatchWaitMs <= 0 {
+			return fmt.Errorf("DB_BATCH_WAIT_MS must be positive when batching is enabled, got %d", c.BatchWaitMs)
+		}
+		if c.BatchMaxSize <= 0 {
+			return fmt.Errorf("DB_BATCH_MAX_SIZE must be positive when batching is enabled, got %d", c.BatchMaxSize)
+		}
+		if c.BatchTimeoutMs <= 0 {
+			return fmt.Errorf("DB_BATCH_TIMEOUT_MS must be positive when batching is enabled, got %d", c.BatchTimeoutMs)
+		}
+	}
 	return nil
 }
 
@@ -316,6 +558,18 @@ func (c *RedisConfig) Validate() error {
 	if c.CacheTTL <= 0 {
 		return fmt.Errorf("REDIS_CACHE_TTL_SECONDS must be positive, got %d", c.CacheTTL)
 	}
+	if c.NegativeTTL <= 0 || c.NegativeTTL > c.CacheTTL {
+		return fmt.Errorf("REDIS_NEGATIVE_TTL_SECONDS must be between 1 and REDIS_CACHE_TTL_SECONDS, got %d", c.NegativeTTL)
+	}
+	if c.TTLJitter < 0 || c.TTLJitter > 50 {
+		return fmt.Errorf("REDIS_CACHE_TTL_JITTER_PERCENT must be between 0 and 50, got %d", c.TTLJitter)
+	}
+	if c.StaleWindow < 0 {
+		return fmt.Errorf("REDIS_STALE_WINDOW_SECONDS cannot be negative, got %d", c.StaleWindow)
+	}
+	if c.EarlyRefresh < 0 {
+		return fmt.Errorf("REDIS_EARLY_REFRESH_SECONDS cannot be negative, got %d", c.EarlyRefresh)
+	}
 	if c.MaxRetries < 0 {
 		return fmt.Errorf("REDIS_MAX_RETRIES cannot be negative, got %d", c.MaxRetries)
 	}
@@ -345,6 +599,193 @@ func (c *RateLimitConfig) Validate() error {
 		return fmt.Errorf("RATE_LIMIT_BURST_CAPACITY must be positive when rate limiting is enabled, got %d",
 			c.BurstCapacity)
 	}
+	if c.Algorithm != "token_bucket" && c.Algorithm != "sliding_window" && c.Algorithm != "gcra" {
+		return fmt.Errorf("RATE_LIMIT_ALGORITHM must be one of [token_bucket, sliding_window, gcra], got %s", c.Algorithm)
+	}
+	if c.FailureMode != "open" && c.FailureMode != "closed" && c.FailureMode != "local" {
+		return fmt.Errorf("RATE_LIMIT_FAILURE_MODE must be one of [open, closed, local], got %s", c.FailureMode)
+	}
+	if c.FailureMode == "local" && (c.LocalShare <= 0 || c.LocalShare > 100) {
+		return fmt.Errorf("RATE_LIMIT_LOCAL_SHARE_PERCENT must be between 1 and 100 when RATE_LIMIT_FAILURE_MODE is local, got %d", c.LocalShare)
+	}
+	if _, err := c.APIKeyList(); err != nil {
+		return fmt.Errorf("RATE_LIMIT_API_KEYS is invalid: %w", err)
+	}
+	return nil
+}
+
+// APIKeyList parses the client API keys.
+func (c *RateLimitConfig) APIKeyList() ([]APIKey, error) {
+	var keys []APIKey
+	seenIDs := make(map[string]bool)
+	seenSecrets := make(map[string]bool)
+
+	for _, triple := range strings.Split(c.APIKeys, ",") {
+		triple = strings.TrimSpace(triple)
+		if triple == "" {
+			continue
+		}
+
+		parts := strings.SplitN(triple, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("expected id:tier:key, got an entry with %d fields", len(parts))
+		}
+		id, tier, secret := parts[0], parts[1], parts[2]
+		if tier != "free" && tier != "internal" && tier != "partner" {
+			return nil, fmt.Errorf("key %q: tier must be one of [free, internal, partner], got %q", id, tier)
+		}
+		if seenIDs[id] {
+			return nil, fmt.Errorf("duplicate key id %q", id)
+		}
+		if seenSecrets[secret] {
+			return nil, fmt.Errorf("key %q reuses the secret of another key", id)
+		}
+		seenIDs[id] = true
+		seenSecrets[secret] = true
+
+		keys = append(keys, APIKey{ID: id, Tier: tier, Secret: secret})
+	}
+	return keys, nil
+}
+
+// Validate validates username configuration
+func (c *UsernameConfig) Validate() error {
+	if c.MinLength <= 0 {
+		return fmt.Errorf("USERNAME_MIN_LENGTH must be positive, got %d", c.MinLength)
+	}
+	if c.MaxLength < c.MinLength {
+		return fmt.Errorf("USERNAME_MAX_LENGTH (%d) cannot be less than USERNAME_MIN_LENGTH (%d)",
+			c.MaxLength, c.MinLength)
+	}
+	if c.MaxLength > 100 {
+		return fmt.Errorf("USERNAME_MAX_LENGTH cannot exceed 100, got %d", c.MaxLength)
+	}
+	if c.Charset == "" {
+		return fmt.Errorf("USERNAME_CHARSET is required")
+	}
+	if _, err := regexp.Compile("^[" + c.Charset + "]+$"); err != nil {
+		return fmt.Errorf("USERNAME_CHARSET is invalid: %w", err)
+	}
+	return nil
+}
+
+// Validate validates cache configuration
+func (c *CacheConfig) Validate() error {
+	if c.Backend != "redis" && c.Backend != "memory" && c.Backend != "none" {
+		return fmt.Errorf("CACHE_BACKEND must be one of [redis, memory, none], got %s", c.Backend)
+	}
+	if c.Backend == "memory" && c.MemoryMaxEntries <= 0 {
+		return fmt.Errorf("CACHE_MEMORY_MAX_ENTRIES must be positive with the memory backend, got %d", c.MemoryMaxEntries)
+	}
+	if c.LocalEnabled {
+		if c.LocalMaxEntries <= 0 {
+			return fmt.Errorf("CACHE_LOCAL_MAX_ENTRIES must be positive when the local cache is enabled, got %d", c.LocalMaxEntries)
+		}
+		if c.LocalTTLSeconds <= 0 {
+			return fmt.Errorf("CACHE_LOCAL_TTL_SECONDS must be positive when the local cache is enabled, got %d", c.LocalTTLSeconds)
+		}
+	}
+	if c.DegradedModeEnabled && c.LastKnownGoodTTLSeconds <= 0 {
+		return fmt.Errorf("CACHE_LAST_KNOWN_GOOD_TTL_SECONDS must be positive when degraded mode is enabled, got %d", c.LastKnownGoodTTLSeconds)
+	}
+	if _, err := c.EncryptionKeyList(); err != nil {
+		return fmt.Errorf("CACHE_ENCRYPTION_KEYS is invalid: %w", err)
+	}
+	if c.WarmupEnabled {
+		if c.WarmupUsers <= 0 {
+			return fmt.Errorf("CACHE_WARMUP_USERS must be positive when warmup is enabled, got %d", c.WarmupUsers)
+		}
+		if c.WarmupTimeoutSeconds <= 0 {
+			return fmt.Errorf("CACHE_WARMUP_TIMEOUT_SECONDS must be positive when warmup is enabled, got %d", c.WarmupTimeoutSeconds)
+		}
+	}
+	return nil
+}
+
+// EncryptionKeyList parses the cache encryption keys, primary key first. It
+// returns no keys when encryption is disabled.
+func (c *CacheConfig) EncryptionKeyList() ([]EncryptionKey, error) {
+	var keys []EncryptionKey
+	seen := make(map[string]bool)
+
+	for _, pair := range strings.Split(c.EncryptionKeys, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(pair, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("expected id:base64-key, got %q", pair)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate key id %q", id)
+		}
+		seen[id] = true
+
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not valid base64: %w", id, err)
+		}
+		if n := len(secret); n != 16 && n != 24 && n != 32 {
+			return nil, fmt.Errorf("key %q must be 16, 24 or 32 bytes, got %d", id, n)
+		}
+		keys = append(keys, EncryptionKey{ID: id, Secret: secret})
+	}
+	return keys, nil
+}
+
+// Validate validates retention configuration
+func (c *RetentionConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.DeletedUserDays <= 0 {
+		return fmt.Errorf("RETENTION_DELETED_USER_DAYS must be positive when retention is enabled, got %d", c.DeletedUserDays)
+	}
+	if c.Mode != "delete" && c.Mode != "anonymize" {
+		return fmt.Errorf("RETENTION_MODE must be one of [delete, anonymize], got %s", c.Mode)
+	}
+	if c.BatchSize <= 0 || c.BatchSize > 10000 {
+		return fmt.Errorf("RETENTION_BATCH_SIZE must be between 1 and 10000, got %d", c.BatchSize)
+	}
+	if _, err := scheduler.ParseSchedule(c.Schedule); err != nil {
+		return fmt.Errorf("RETENTION_SCHEDULE is invalid: %w", err)
+	}
+	if c.RunTimeoutSeconds <= 0 {
+		return fmt.Errorf("RETENTION_RUN_TIMEOUT_SECONDS must be positive when retention is enabled, got %d", c.RunTimeoutSeconds)
+	}
+	return nil
+}
+
+// Validate validates scheduler configuration
+func (c *SchedulerConfig) Validate() error {
+	if c.JitterSeconds < 0 {
+		return fmt.Errorf("SCHEDULER_JITTER_SECONDS cannot be negative, got %d", c.JitterSeconds)
+	}
+	if c.Retries < 0 {
+		return fmt.Errorf("SCHEDULER_RETRIES cannot be negative, got %d", c.Retries)
+	}
+	if c.RetryBackoffSeconds < 0 {
+		return fmt.Errorf("SCHEDULER_RETRY_BACKOFF_SECONDS cannot be negative, got %d", c.RetryBackoffSeconds)
+	}
+	return nil
+}
+
+// Validate validates circuit breaker configuration
+func (c *BreakerConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.FailureThreshold <= 0 {
+		return fmt.Errorf("BREAKER_FAILURE_THRESHOLD must be positive when circuit breakers are enabled, got %d", c.FailureThreshold)
+	}
+	if c.OpenSeconds <= 0 {
+		return fmt.Errorf("BREAKER_OPEN_SECONDS must be positive when circuit breakers are enabled, got %d", c.OpenSeconds)
+	}
+	if c.HalfOpenProbes <= 0 {
+		return fmt.Errorf("BREAKER_HALF_OPEN_PROBES must be positive when circuit breakers are enabled, got %d", c.HalfOpenProbes)
+	}
 	return nil
 }
 
@@ -0,0 +1,36 @@
+package user
+
+import "time"
+
+// AuditEntry records an operation performed on a user.
+// Entries never contain personal data, so they survive erasure.
+type AuditEntry struct {
+	ID        int64     // ID is the unique identifier of the entry
+	UserID    int64     // UserID is the ID of the user the operation was performed on
+	Action    string    // Action names the operation (e.g. "user.updated")
+	Details   string    // Details holds non-personal context, such as the names of changed fields
+	CreatedAt time.Time // CreatedAt is when the operation was performed
+}
+
+// Audit actions recorded for users.
+const (
+	AuditUserCreated         = "user.created"
+	AuditUserUpdated         = "user.updated"
+	AuditUserDeleted         = "user.deleted"
+	AuditUserExported        = "user.exported"
+	AuditUserErased          = "user.erased"
+	AuditEmailAdded          = "email.added"
+	AuditEmailRemoved        = "email.removed"
+	AuditEmailPrimaryChanged = "email.primary_changed"
+	AuditEmailVerified       = "email.verified"
+	AuditIdentityLinked      = "identity.linked"
+	AuditIdentityUnlinked    = "identity.unlinked"
+)
+
+// Audit actions recorded for cache administration. Pattern evictions and
+// flushes are not about one user and are recorded with a UserID of 0.
+const (
+	AuditCacheInspected = "cache.inspected"
+	AuditCacheEvicted   = "cache.evicted"
+	AuditCacheFlushed   = "cache.flushed"
+)
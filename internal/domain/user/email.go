@@ -0,0 +1,14 @@
+package user
+
+import "time"
+
+// Email represents one of the email addresses owned by a user.
+// Every user has exactly one primary address, mirrored in User.Email.
+type Email struct {
+	UserID     int64     // UserID is the ID of the user owning the address
+	Address    string    // Address is the email address, unique across all users
+	Primary    bool      // Primary marks the address used for contact and sign-in
+	Verified   bool      // Verified reports whether ownership of the address was confirmed
+	VerifiedAt time.Time // VerifiedAt is when the address was verified, zero if unverified
+	CreatedAt  time.Time // CreatedAt is when the address was added
+}
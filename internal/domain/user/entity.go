@@ -2,7 +2,21 @@ package user
 
 // User represents a user entity in the system.
 type User struct {
-	ID    int64  // ID is the unique identifier for the user
-	Name  string // Name is the full name of the user
-	Email string // Email is the unique email address of the user
+	ID          int64  // ID is the unique identifier for the user
+	Username    string // Username is the optional unique, lowercase handle of the user
+	Name        string // Name is the full name of the user, computed from the structured name fields
+	GivenName   string // GivenName is the user's given (first) name
+	FamilyName  string // FamilyName is the user's family (last) name
+	DisplayName string // DisplayName is the name the user prefers to be shown with
+	Email       string // Email is the unique email address of the user
+	Phone       string // Phone is the optional contact number in E.164 format
+	Locale      string // Locale is the optional preferred BCP 47 language tag
+	Timezone    string // Timezone is the optional IANA time zone name
 }
+
+// Optional fields an update can clear, by their API names.
+const (
+	FieldPhone    = "phone"
+	FieldLocale   = "locale"
+	FieldTimezone = "timezone"
+)
@@ -0,0 +1,11 @@
+package user
+
+import "time"
+
+// Erasure is the tombstone left when a user's personal data is erased.
+// It proves the erasure ran without retaining any of the erased data.
+type Erasure struct {
+	UserID   int64     // UserID is the ID of the erased user
+	Reason   string    // Reason is the optional reference for the request (e.g. a ticket number)
+	ErasedAt time.Time // ErasedAt is when the erasure ran
+}
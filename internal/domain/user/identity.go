@@ -0,0 +1,12 @@
+package user
+
+import "time"
+
+// Identity represents an account at an external identity provider linked to a user.
+// The (Provider, Subject) pair is unique across all users.
+type Identity struct {
+	UserID    int64     // UserID is the ID of the linked user
+	Provider  string    // Provider is the lowercase identifier of the identity provider (e.g. "google")
+	Subject   string    // Subject is the provider's stable, case-sensitive identifier for the account
+	CreatedAt time.Time // CreatedAt is when the identity was linked
+}
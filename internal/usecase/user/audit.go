@@ -0,0 +1,73 @@
+package user
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	domain "grpc-user-service/internal/domain/user"
+)
+
+// AuditLog stores audit entries about operations performed on users.
+type AuditLog interface {
+	Record(ctx context.Context, entry *domain.AuditEntry) error                // Append an audit entry
+	ListByUser(ctx context.Context, userID int64) ([]domain.AuditEntry, error) // List the entries of a user, oldest first
+}
+
+// WithAuditLog enables audit logging. Without it, operations are not audited
+// and data exports contain no audit entries.
+func WithAuditLog(a AuditLog) Option {
+	return func(uc *usecaseImpl) {
+		uc.audit = a
+	}
+}
+
+// recordAudit appends an audit entry. Failures are logged but do not fail the
+// operation, which has already been applied.
+func (uc *usecaseImpl) recordAudit(ctx context.Context, userID int64, action, details string) {
+	if uc.audit == nil {
+		return
+	}
+
+	entry := &domain.AuditEntry{UserID: userID, Action: action, Details: details}
+	if err := uc.audit.Record(ctx, entry); err != nil {
+		uc.log.Warn("failed to record audit entry", zap.Int64("user_id", userID), zap.String("action", action), zap.Error(err))
+	}
+}
+
+// auditEntries lists the audit entries of a user, or none when audit logging is disabled.
+func (uc *usecaseImpl) auditEntries(ctx context.Context, userID int64) ([]domain.AuditEntry, error) {
+	if uc.audit == nil {
+		return nil, nil
+	}
+	return uc.audit.ListByUser(ctx, userID)
+}
+
+// updatedFields returns the comma-separated names of the fields set or cleared
+// in an update request. Only names are returned so audit entries hold no personal data.
+func updatedFields(in UpdateUserRequest) string {
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"username", in.Username},
+		{"name", in.Name},
+		{"given_name", in.GivenName},
+		{"family_name", in.FamilyName},
+		{"display_name", in.DisplayName},
+		{"email", in.Email},
+		{"phone", in.Phone},
+		{"locale", in.Locale},
+		{"timezone", in.Timezone},
+	}
+
+	var names []string
+	for _, f := range fields {
+		if f.value != "" || slices.Contains(in.Clear, f.name) {
+			names = append(names, f.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
@@ -1,9 +1,18 @@
 package user
 
+import "time"
+
 // CreateUserRequest represents the request payload for creating a new user.
 type CreateUserRequest struct {
-	Name  string `validate:"required,min=3,max=100"`
-	Email string `validate:"required,email"`
+	Username    string `validate:"omitempty,max=100"`
+	Name        string `validate:"required_without_all=GivenName FamilyName DisplayName,max=100"`
+	GivenName   string `validate:"omitempty,max=100"`
+	FamilyName  string `validate:"omitempty,max=100"`
+	DisplayName string `validate:"omitempty,max=100"`
+	Email       string `validate:"required,email"`
+	Phone       string `validate:"omitempty,max=32"`
+	Locale      string `validate:"omitempty,max=35"`
+	Timezone    string `validate:"omitempty,max=64"`
 }
 
 // CreateUserResponse represents the response payload after creating a user.
@@ -13,9 +22,19 @@ type CreateUserResponse struct {
 
 // UpdateUserRequest represents the request payload for updating an existing user.
 type UpdateUserRequest struct {
-	ID    int64  `validate:"required"`
-	Name  string `validate:"omitempty,min=3,max=100"`
-	Email string `validate:"omitempty,email"`
+	ID          int64  `validate:"required"`
+	Username    string `validate:"omitempty,max=100"`
+	Name        string `validate:"omitempty,max=100"`
+	GivenName   string `validate:"omitempty,max=100"`
+	FamilyName  string `validate:"omitempty,max=100"`
+	DisplayName string `validate:"omitempty,max=100"`
+	Email       string `validate:"omitempty,email"`
+	Phone       string `validate:"omitempty,max=32"`
+	Locale      string `validate:"omitempty,max=35"`
+	Timezone    string `validate:"omitempty,max=64"`
+	// Clear names optional fields to empty: phone, locale or timezone.
+	// Fields left empty otherwise keep their stored values.
+	Clear []string `validate:"omitempty,dive,oneof=phone locale timezone"`
 }
 
 // UpdateUserResponse represents the response payload after updating a user.
@@ -40,17 +59,31 @@ type GetUserRequest struct {
 
 // GetUserResponse represents the response payload for user details.
 type GetUserResponse struct {
-	ID    int64
-	Name  string
-	Email string
+	ID          int64
+	Username    string
+	Name        string
+	GivenName   string
+	FamilyName  string
+	DisplayName string
+	Email       string
+	Phone       string
+	Locale      string
+	Timezone    string
+}
+
+// GetUserByUsernameRequest represents the request payload for retrieving a user by username.
+type GetUserByUsernameRequest struct {
+	Username string
 }
 
 // ListUsersRequest represents the request payload for listing users.
-// It supports pagination and search functionality.
+// It supports pagination and search functionality. Results are ordered by
+// family name, then given name, using the collation of Locale.
 type ListUsersRequest struct {
-	Query string
-	Page  int64
-	Limit int64
+	Query  string
+	Locale string // Locale is the optional BCP 47 language tag to sort names by
+	Page   int64
+	Limit  int64
 }
 
 // ListUsersResponse represents the response payload for user listing.
@@ -69,7 +102,145 @@ type Pagination struct {
 
 // User represents a user DTO (Data Transfer Object) for API responses.
 type User struct {
-	ID    int64
-	Name  string
-	Email string
+	ID          int64
+	Username    string
+	Name        string
+	GivenName   string
+	FamilyName  string
+	DisplayName string
+	Email       string
+	Phone       string
+	Locale      string
+	Timezone    string
+}
+
+// ListUserEmailsRequest represents the request payload for listing a user's email addresses.
+type ListUserEmailsRequest struct {
+	UserID int64 `validate:"required,gt=0"`
+}
+
+// AddUserEmailRequest represents the request payload for adding a secondary email address.
+type AddUserEmailRequest struct {
+	UserID int64  `validate:"required,gt=0"`
+	Email  string `validate:"required,email"`
+}
+
+// RemoveUserEmailRequest represents the request payload for removing a secondary email address.
+type RemoveUserEmailRequest struct {
+	UserID int64  `validate:"required,gt=0"`
+	Email  string `validate:"required,email"`
+}
+
+// SetPrimaryUserEmailRequest represents the request payload for changing a user's primary email address.
+type SetPrimaryUserEmailRequest struct {
+	UserID int64  `validate:"required,gt=0"`
+	Email  string `validate:"required,email"`
+}
+
+// IssueUserEmailVerificationRequest represents the request payload for issuing a verification token for an email address.
+type IssueUserEmailVerificationRequest struct {
+	UserID int64  `validate:"required,gt=0"`
+	Email  string `validate:"required,email"`
+}
+
+// EmailVerificationResponse represents a newly issued email verification token.
+type EmailVerificationResponse struct {
+	UserID    int64
+	Email     string
+	Token     string // Single-use token, only returned once
+	ExpiresAt time.Time
+}
+
+// VerifyUserEmailRequest represents the request payload for marking an email address as verified.
+type VerifyUserEmailRequest struct {
+	UserID int64  `validate:"required,gt=0"`
+	Email  string `validate:"required,email"`
+	Token  string `validate:"required"` // Token sent to the address
+}
+
+// UserEmailsResponse represents the email addresses of a user after an email operation.
+type UserEmailsResponse struct {
+	UserID int64
+	Emails []UserEmail
+}
+
+// UserEmail represents an email address DTO.
+type UserEmail struct {
+	Email      string
+	Primary    bool
+	Verified   bool
+	VerifiedAt time.Time
+}
+
+// LinkIdentityRequest represents the request payload for linking an external identity to a user.
+type LinkIdentityRequest struct {
+	UserID   int64  `validate:"required,gt=0"`
+	Provider string `validate:"required,max=64"`
+	Subject  string `validate:"required,max=255"`
+}
+
+// LinkIdentityResponse represents the response payload after linking an external identity.
+type LinkIdentityResponse struct {
+	UserID   int64
+	Provider string
+	Subject  string
+}
+
+// UnlinkIdentityRequest represents the request payload for unlinking an external identity from a user.
+type UnlinkIdentityRequest struct {
+	UserID   int64  `validate:"required,gt=0"`
+	Provider string `validate:"required,max=64"`
+	Subject  string `validate:"required,max=255"`
+}
+
+// UnlinkIdentityResponse represents the response payload after unlinking an external identity.
+type UnlinkIdentityResponse struct {
+	UserID int64
+}
+
+// GetUserByIdentityRequest represents the request payload for retrieving a user by a linked identity.
+type GetUserByIdentityRequest struct {
+	Provider string `validate:"required,max=64"`
+	Subject  string `validate:"required,max=255"`
+}
+
+// ExportUserDataRequest represents the request payload for exporting the data held about a user.
+type ExportUserDataRequest struct {
+	UserID int64 `validate:"required,gt=0"`
+}
+
+// ExportUserDataResponse is the machine-readable bundle of everything held about a user.
+type ExportUserDataResponse struct {
+	FormatVersion int
+	ExportedAt    time.Time
+	Profile       GetUserResponse
+	Emails        []UserEmail
+	Identities    []LinkedIdentity
+	AuditEntries  []AuditEntry
+}
+
+// LinkedIdentity represents an external identity DTO.
+type LinkedIdentity struct {
+	Provider  string
+	Subject   string
+	CreatedAt time.Time
+}
+
+// AuditEntry represents an audit entry DTO.
+type AuditEntry struct {
+	Action    string
+	Details   string
+	CreatedAt time.Time
+}
+
+// EraseUserRequest represents the request payload for erasing a user's personal data.
+type EraseUserRequest struct {
+	UserID int64  `validate:"required,gt=0"`
+	Reason string `validate:"omitempty,max=255"`
+}
+
+// EraseUserResponse represents the tombstone left by an erasure.
+type EraseUserResponse struct {
+	UserID   int64
+	ErasedAt time.Time
 }
@@ -0,0 +1,223 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	domain "grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// ListUserEmails returns all email addresses of a user, primary first.
+func (uc *usecaseImpl) ListUserEmails(ctx context.Context, in ListUserEmailsRequest) (*UserEmailsResponse, error) {
+	if err := uc.validate.Struct(in); err != nil {
+		uc.log.Warn("validate failed", zap.Error(err))
+		return nil, formatValidationError(err)
+	}
+
+	// Surface NotFound for unknown users instead of an empty list
+	if _, err := uc.repo.GetByID(ctx, in.UserID); err != nil {
+		uc.log.Error("failed to get user", zap.Int64("user_id", in.UserID), zap.Error(err))
+		return nil, err
+	}
+
+	return uc.userEmails(ctx, in.UserID)
+}
+
+// AddUserEmail adds a secondary, unverified email address to a user, and issues
+// a token to verify it. The address must not belong to any user yet, including
+// as a secondary address.
+func (uc *usecaseImpl) AddUserEmail(ctx context.Context, in AddUserEmailRequest) (*UserEmailsResponse, error) {
+	uc.log.Info("adding user email", zap.Int64("user_id", in.UserID), zap.String("email", in.Email))
+
+	if err := uc.validate.Struct(in); err != nil {
+		uc.log.Warn("validate failed", zap.Error(err))
+		return nil, formatValidationError(err)
+	}
+
+	if _, err := uc.repo.GetByID(ctx, in.UserID); err != nil {
+		uc.log.Error("failed to get user", zap.Int64("user_id", in.UserID), zap.Error(err))
+		return nil, err
+	}
+
+	existingUser, err := uc.repo.GetByEmail(ctx, in.Email)
+	if err != nil {
+		uc.log.Error("failed to check existing email", zap.String("email", in.Email), zap.Error(err))
+		return nil, pkgerrors.NewInternalError("failed to validate email uniqueness", err)
+	}
+	if existingUser != nil {
+		uc.log.Warn("email already exists", zap.String("email", in.Email), zap.Int64("existing_id", existingUser.ID))
+		return nil, pkgerrors.NewAlreadyExistsError("user", "email already exists")
+	}
+
+	if err := uc.repo.AddEmail(ctx, in.UserID, in.Email); err != nil {
+		uc.log.Error("failed to add user email", zap.Int64("user_id", in.UserID), zap.Error(err))
+		return nil, err
+	}
+
+	uc.recordAudit(ctx, in.UserID, domain.AuditEmailAdded, "")
+
+	if _, _, err := uc.issueVerification(ctx, in.UserID, in.Email); err != nil {
+		return nil, err
+	}
+
+	return uc.userEmails(ctx, in.UserID)
+}
+
+// RemoveUserEmail removes a secondary email address from a user.
+// The primary address cannot be removed; another address has to be made primary first.
+func (uc *usecaseImpl) RemoveUserEmail(ctx context.Context, in RemoveUserEmailRequest) (*UserEmailsResponse, error) {
+	uc.log.Info("removing user email", zap.Int64("user_id", in.UserID), zap.String("email", in.Email))
+
+	if err := uc.validate.Struct(in); err != nil {
+		uc.log.Warn("validate failed", zap.Error(err))
+		return nil, formatValidationError(err)
+	}
+
+	email, err := uc.ownedEmail(ctx, in.UserID, in.Email)
+	if err != nil {
+		return nil, err
+	}
+	if email.Primary {
+		uc.log.Warn("cannot remove primary email", zap.Int64("user_id", in.UserID), zap.String("email", in.Email))
+		return nil, pkgerrors.NewValidationError("email", "cannot remove the primary email; set another primary email first")
+	}
+
+	if err := uc.repo.RemoveEmail(ctx, in.UserID, in.Email); err != nil {
+		uc.log.Error("failed to remove user email", zap.Int64("user_id", in.UserID), zap.Error(err))
+		return nil, err
+	}
+
+	uc.recordAudit(ctx, in.UserID, domain.AuditEmailRemoved, "")
+
+	return uc.userEmails(ctx, in.UserID)
+}
+
+// SetPrimaryUserEmail makes one of the user's addresses the primary one.
+// The previous primary address is kept as a secondary address.
+func (uc *usecaseImpl) SetPrimaryUserEmail(ctx context.Context, in SetPrimaryUserEmailRequest) (*UserEmailsResponse, error) {
+	uc.log.Info("setting primary user email", zap.Int64("user_id", in.UserID), zap.String("email", in.Email))
+
+	if err := uc.validate.Struct(in); err != nil {
+		uc.log.Warn("validate failed", zap.Error(err))
+		return nil, formatValidationError(err)
+	}
+
+	email, err := uc.ownedEmail(ctx, in.UserID, in.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if !email.Primary {
+		if err := uc.repo.SetPrimaryEmail(ctx, in.UserID, in.Email); err != nil {
+			uc.log.Error("failed to set primary user email", zap.Int64("user_id", in.UserID), zap.Error(err))
+			return nil, err
+		}
+		uc.recordAudit(ctx, in.UserID, domain.AuditEmailPrimaryChanged, "")
+	}
+
+	return uc.userEmails(ctx, in.UserID)
+}
+
+// IssueUserEmailVerification issues a new verification token for an unverified
+// address of a user, replacing any pending one, and returns it. It is meant
+// for trusted callers delivering the token to the address.
+func (uc *usecaseImpl) IssueUserEmailVerification(ctx context.Context, in IssueUserEmailVerificationRequest) (*EmailVerificationResponse, error) {
+	uc.log.Info("issuing user email verification", zap.Int64("user_id", in.UserID), zap.String("email", in.Email))
+
+	if err := uc.validate.Struct(in); err != nil {
+		uc.log.Warn("validate failed", zap.Error(err))
+		return nil, formatValidationError(err)
+	}
+
+	email, err := uc.ownedEmail(ctx, in.UserID, in.Email)
+	if err != nil {
+		return nil, err
+	}
+	if email.Verified {
+		uc.log.Warn("email already verified", zap.Int64("user_id", in.UserID), zap.String("email", in.Email))
+		return nil, pkgerrors.NewValidationError("email", "email is already verified")
+	}
+
+	token, expiresAt, err := uc.issueVerification(ctx, in.UserID, in.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailVerificationResponse{
+		UserID:    in.UserID,
+		Email:     in.Email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// VerifyUserEmail marks one of the user's addresses as verified, given the
+// token sent to it. The token is single-use and expires after
+// EmailVerificationTTL. Verifying an already verified address keeps its
+// original verification time.
+func (uc *usecaseImpl) VerifyUserEmail(ctx context.Context, in VerifyUserEmailRequest) (*UserEmailsResponse, error) {
+	uc.log.Info("verifying user email", zap.Int64("user_id", in.UserID), zap.String("email", in.Email))
+
+	if err := uc.validate.Struct(in); err != nil {
+		uc.log.Warn("validate failed", zap.Error(err))
+		return nil, formatValidationError(err)
+	}
+
+	email, err := uc.ownedEmail(ctx, in.UserID, in.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if !email.Verified {
+		if err := uc.repo.VerifyEmail(ctx, in.UserID, in.Email, hashVerificationToken(in.Token)); err != nil {
+			uc.log.Error("failed to verify user email", zap.Int64("user_id", in.UserID), zap.Error(err))
+			return nil, err
+		}
+		uc.recordAudit(ctx, in.UserID, domain.AuditEmailVerified, "")
+	}
+
+	return uc.userEmails(ctx, in.UserID)
+}
+
+// ownedEmail returns the given address of a user, or a NotFound error when the
+// user does not own it.
+func (uc *usecaseImpl) ownedEmail(ctx context.Context, userID int64, address string) (*domain.Email, error) {
+	emails, err := uc.repo.ListEmails(ctx, userID)
+	if err != nil {
+		uc.log.Error("failed to list user emails", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	for i := range emails {
+		if emails[i].Address == address {
+			return &emails[i], nil
+		}
+	}
+
+	uc.log.Warn("user email not found", zap.Int64("user_id", userID), zap.String("email", address))
+	return nil, pkgerrors.NewNotFoundError("email", fmt.Sprintf("email not found: user_id=%d", userID))
+}
+
+// userEmails loads the email addresses of a user into a response DTO.
+func (uc *usecaseImpl) userEmails(ctx context.Context, userID int64) (*UserEmailsResponse, error) {
+	emails, err := uc.repo.ListEmails(ctx, userID)
+	if err != nil {
+		uc.log.Error("failed to list user emails", zap.Int64("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	out := make([]UserEmail, len(emails))
+	for i, e := range emails {
+		out[i] = UserEmail{
+			Email:      e.Address,
+			Primary:    e.Primary,
+			Verified:   e.Verified,
+			VerifiedAt: e.VerifiedAt,
+		}
+	}
+
+	return &UserEmailsResponse{UserID: userID, Emails: out}, nil
+}
@@ -0,0 +1,101 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	domain "grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+// normalizeProvider returns the canonical form of an identity provider name.
+// Provider names are case-insensitive; subjects are opaque and kept as sent.
+func normalizeProvider(provider string) string {
+	return strings.ToLower(strings.TrimSpace(provider))
+}
+
+// LinkIdentity links an external identity to a user. Linking an identity that
+// is already linked to the same user succeeds without changes.
+func (uc *usecaseImpl) LinkIdentity(ctx context.Context, in LinkIdentityRequest) (*LinkIdentityResponse, error) {
+	in.Provider = normalizeProvider(in.Provider)
+	in.Subject = strings.TrimSpace(in.Subject)
+
+	uc.log.Info("linking identity", zap.Int64("user_id", in.UserID), zap.String("provider", in.Provider))
+
+	if err := uc.validate.Struct(in); err != nil {
+		uc.log.Warn("validate failed", zap.Error(err))
+		return nil, formatValidationError(err)
+	}
+
+	if _, err := uc.repo.GetByID(ctx, in.UserID); err != nil {
+		uc.log.Error("failed to get user", zap.Int64("user_id", in.UserID), zap.Error(err))
+		return nil, err
+	}
+
+	existingUser, err := uc.repo.GetByIdentity(ctx, in.Provider, in.Subject)
+	if err != nil {
+		uc.log.Error("failed to check existing identity", zap.String("provider", in.Provider), zap.Error(err))
+		return nil, pkgerrors.NewInternalError("failed to validate identity uniqueness", err)
+	}
+	if existingUser != nil && existingUser.ID != in.UserID {
+		uc.log.Warn("identity already linked", zap.String("provider", in.Provider), zap.Int64("existing_id", existingUser.ID))
+		return nil, pkgerrors.NewAlreadyExistsError("identity", "identity already linked to another user")
+	}
+
+	if existingUser == nil {
+		identity := &domain.Identity{UserID: in.UserID, Provider: in.Provider, Subject: in.Subject}
+		if err := uc.repo.LinkIdentity(ctx, identity); err != nil {
+			uc.log.Error("failed to link identity", zap.Int64("user_id", in.UserID), zap.Error(err))
+			return nil, err
+		}
+		uc.recordAudit(ctx, in.UserID, domain.AuditIdentityLinked, in.Provider)
+	}
+
+	return &LinkIdentityResponse{UserID: in.UserID, Provider: in.Provider, Subject: in.Subject}, nil
+}
+
+// UnlinkIdentity removes an external identity from a user.
+func (uc *usecaseImpl) UnlinkIdentity(ctx context.Context, in UnlinkIdentityRequest) (*UnlinkIdentityResponse, error) {
+	in.Provider = normalizeProvider(in.Provider)
+	in.Subject = strings.TrimSpace(in.Subject)
+
+	uc.log.Info("unlinking identity", zap.Int64("user_id", in.UserID), zap.String("provider", in.Provider))
+
+	if err := uc.validate.Struct(in); err != nil {
+		uc.log.Warn("validate failed", zap.Error(err))
+		return nil, formatValidationError(err)
+	}
+
+	if err := uc.repo.UnlinkIdentity(ctx, in.UserID, in.Provider, in.Subject); err != nil {
+		uc.log.Error("failed to unlink identity", zap.Int64("user_id", in.UserID), zap.Error(err))
+		return nil, err
+	}
+
+	uc.recordAudit(ctx, in.UserID, domain.AuditIdentityUnlinked, in.Provider)
+	return &UnlinkIdentityResponse{UserID: in.UserID}, nil
+}
+
+// GetUserByIdentity retrieves the user an external identity is linked to.
+func (uc *usecaseImpl) GetUserByIdentity(ctx context.Context, in GetUserByIdentityRequest) (*GetUserResponse, error) {
+	in.Provider = normalizeProvider(in.Provider)
+	in.Subject = strings.TrimSpace(in.Subject)
+
+	if err := uc.validate.Struct(in); err != nil {
+		uc.log.Warn("validate failed", zap.Error(err))
+		return nil, formatValidationError(err)
+	}
+
+	user, err := uc.repo.GetByIdentity(ctx, in.Provider, in.Subject)
+	if err != nil {
+		uc.log.Error("failed to get user by identity", zap.String("provider", in.Provider), zap.Error(err))
+		return nil, err
+	}
+	if user == nil {
+		return nil, pkgerrors.NewNotFoundError("user", fmt.Sprintf("user not found: provider=%s", in.Provider))
+	}
+
+	return toGetUserResponse(user), nil
+}
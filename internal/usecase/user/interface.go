@@ -8,5 +8,17 @@ type Usecase interface {
 	UpdateUser(ctx context.Context, in UpdateUserRequest) (*UpdateUserResponse, error)
 	DeleteUser(ctx context.Context, in DeleteUserRequest) (*DeleteUserResponse, error)
 	GetUser(ctx context.Context, in GetUserRequest) (*GetUserResponse, error)
+	GetUserByUsername(ctx context.Context, in GetUserByUsernameRequest) (*GetUserResponse, error)
 	ListUsers(ctx context.Context, in ListUsersRequest) (*ListUsersResponse, error)
+	ListUserEmails(ctx context.Context, in ListUserEmailsRequest) (*UserEmailsResponse, error)
+	AddUserEmail(ctx context.Context, in AddUserEmailRequest) (*UserEmailsResponse, error)
+	RemoveUserEmail(ctx context.Context, in RemoveUserEmailRequest) (*UserEmailsResponse, error)
+	SetPrimaryUserEmail(ctx context.Context, in SetPrimaryUserEmailRequest) (*UserEmailsResponse, error)
+	IssueUserEmailVerification(ctx context.Context, in IssueUserEmailVerificationRequest) (*EmailVerificationResponse, error)
+	VerifyUserEmail(ctx context.Context, in VerifyUserEmailRequest) (*UserEmailsResponse, error)
+	LinkIdentity(ctx context.Context, in LinkIdentityRequest) (*LinkIdentityResponse, error)
+	UnlinkIdentity(ctx context.Context, in UnlinkIdentityRequest) (*UnlinkIdentityResponse, error)
+	GetUserByIdentity(ctx context.Context, in GetUserByIdentityRequest) (*GetUserResponse, error)
+	ExportUserData(ctx context.Context, in ExportUserDataRequest) (*ExportUserDataResponse, error)
+	EraseUser(ctx context.Context, in EraseUserRequest) (*EraseUserResponse, error)
 }
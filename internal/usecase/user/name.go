@@ -0,0 +1,83 @@
+package user
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+
+	domain "grpc-user-service/internal/domain/user"
+)
+
+// nameFields holds the name attributes of a create or update request.
+type nameFields struct {
+	Name        string
+	GivenName   string
+	FamilyName  string
+	DisplayName string
+}
+
+// normalizeName trims surrounding whitespace, collapses internal whitespace runs
+// into a single space and converts the result to Unicode NFC, so that visually
+// identical names are stored and compared identically.
+func normalizeName(s string) string {
+	return norm.NFC.String(strings.Join(strings.Fields(s), " "))
+}
+
+// normalize returns a copy of n with every field normalized.
+func (n nameFields) normalize() nameFields {
+	return nameFields{
+		Name:        normalizeName(n.Name),
+		GivenName:   normalizeName(n.GivenName),
+		FamilyName:  normalizeName(n.FamilyName),
+		DisplayName: normalizeName(n.DisplayName),
+	}
+}
+
+// empty reports whether no name field is set.
+func (n nameFields) empty() bool {
+	return n.Name == "" && n.GivenName == "" && n.FamilyName == "" && n.DisplayName == ""
+}
+
+// displayName returns the requested display name. The legacy name sent by v1
+// clients is treated as a display name when no explicit one is given.
+func (n nameFields) displayName() string {
+	if n.DisplayName != "" {
+		return n.DisplayName
+	}
+	return n.Name
+}
+
+// needsStoredNames reports whether applying n to an existing user requires the
+// stored name fields, i.e. the legacy name has to be recomputed from a given or
+// family name without a display name in the request.
+func (n nameFields) needsStoredNames() bool {
+	return (n.GivenName != "" || n.FamilyName != "") && n.displayName() == ""
+}
+
+// composeName computes the legacy single-string name kept for v1 clients.
+// The display name wins; otherwise given and family names are joined.
+func composeName(givenName, familyName, displayName string) string {
+	if displayName != "" {
+		return displayName
+	}
+	return strings.TrimSpace(givenName + " " + familyName)
+}
+
+// apply merges the non-empty name fields onto u and recomputes u.Name.
+// It leaves u untouched when no name field is set.
+func (n nameFields) apply(u *domain.User) {
+	if n.empty() {
+		return
+	}
+
+	if n.GivenName != "" {
+		u.GivenName = n.GivenName
+	}
+	if n.FamilyName != "" {
+		u.FamilyName = n.FamilyName
+	}
+	if display := n.displayName(); display != "" {
+		u.DisplayName = display
+	}
+	u.Name = composeName(u.GivenName, u.FamilyName, u.DisplayName)
+}
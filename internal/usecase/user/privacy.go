@@ -0,0 +1,89 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	domain "grpc-user-service/internal/domain/user"
+)
+
+// ExportFormatVersion is the version of the ExportUserData bundle layout.
+// It is bumped whenever sections are added or changed.
+const ExportFormatVersion = 1
+
+// ExportUserData returns everything held about a user: the profile, all email
+// addresses, linked identities and audit entries.
+func (uc *usecaseImpl) ExportUserData(ctx context.Context, in ExportUserDataRequest) (*ExportUserDataResponse, error) {
+	uc.log.Info("exporting user data", zap.Int64("user_id", in.UserID))
+
+	if err := uc.validate.Struct(in); err != nil {
+		uc.log.Warn("validate failed", zap.Error(err))
+		return nil, formatValidationError(err)
+	}
+
+	user, err := uc.repo.GetByID(ctx, in.UserID)
+	if err != nil {
+		uc.log.Error("failed to get user", zap.Int64("user_id", in.UserID), zap.Error(err))
+		return nil, err
+	}
+
+	emails, err := uc.userEmails(ctx, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := uc.repo.ListIdentities(ctx, in.UserID)
+	if err != nil {
+		uc.log.Error("failed to list identities", zap.Int64("user_id", in.UserID), zap.Error(err))
+		return nil, err
+	}
+
+	// Record the export first so that it is part of the bundle it produces
+	uc.recordAudit(ctx, in.UserID, domain.AuditUserExported, "")
+
+	entries, err := uc.auditEntries(ctx, in.UserID)
+	if err != nil {
+		uc.log.Error("failed to list audit entries", zap.Int64("user_id", in.UserID), zap.Error(err))
+		return nil, err
+	}
+
+	out := &ExportUserDataResponse{
+		FormatVersion: ExportFormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Profile:       *toGetUserResponse(user),
+		Emails:        emails.Emails,
+		Identities:    make([]LinkedIdentity, len(identities)),
+		AuditEntries:  make([]AuditEntry, len(entries)),
+	}
+	for i, id := range identities {
+		out.Identities[i] = LinkedIdentity{Provider: id.Provider, Subject: id.Subject, CreatedAt: id.CreatedAt}
+	}
+	for i, e := range entries {
+		out.AuditEntries[i] = AuditEntry{Action: e.Action, Details: e.Details, CreatedAt: e.CreatedAt}
+	}
+
+	return out, nil
+}
+
+// EraseUser irreversibly anonymizes a user: personal fields are cleared,
+// secondary emails and linked identities are deleted and a tombstone is left.
+// Erasing an already erased user returns the existing tombstone.
+func (uc *usecaseImpl) EraseUser(ctx context.Context, in EraseUserRequest) (*EraseUserResponse, error) {
+	uc.log.Info("erasing user", zap.Int64("user_id", in.UserID))
+
+	if err := uc.validate.Struct(in); err != nil {
+		uc.log.Warn("validate failed", zap.Error(err))
+		return nil, formatValidationError(err)
+	}
+
+	erasure, err := uc.repo.Erase(ctx, in.UserID, in.Reason)
+	if err != nil {
+		uc.log.Error("failed to erase user", zap.Int64("user_id", in.UserID), zap.Error(err))
+		return nil, err
+	}
+
+	uc.recordAudit(ctx, in.UserID, domain.AuditUserErased, "")
+	return &EraseUserResponse{UserID: erasure.UserID, ErasedAt: erasure.ErasedAt}, nil
+}
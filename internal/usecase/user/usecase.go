@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
 	domain "grpc-user-service/internal/domain/user"
+	"grpc-user-service/pkg/contact"
 	pkgerrors "grpc-user-service/pkg/errors"
 
 	"github.com/go-playground/validator/v10"
@@ -17,25 +19,62 @@ import (
 // It abstracts the data layer, allowing different implementations
 // (e.g., PostgreSQL, MongoDB) to be used interchangeably.
 type Repository interface {
-	Create(ctx context.Context, u *domain.User) (int64, error)                               // Create a new user
-	GetByID(ctx context.Context, id int64) (*domain.User, error)                             // Retrieve user by ID
-	GetByEmail(ctx context.Context, email string) (*domain.User, error)                      // Retrieve user by email
-	Update(ctx context.Context, u *domain.User) (int64, error)                               // Update existing user
-	Delete(ctx context.Context, id int64) (int64, error)                                     // Delete user by ID
-	List(ctx context.Context, query string, page, limit int64) ([]domain.User, int64, error) // List users with pagination and search, returns users and total count
+	Create(ctx context.Context, u *domain.User) (int64, error)                                       // Create a new user
+	GetByID(ctx context.Context, id int64) (*domain.User, error)                                     // Retrieve user by ID
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)                              // Retrieve user by email
+	GetByUsername(ctx context.Context, username string) (*domain.User, error)                        // Retrieve user by lowercase username, nil if not found
+	Update(ctx context.Context, u *domain.User, cleared ...string) (int64, error)                    // Update existing user, emptying the optional fields named in cleared
+	Delete(ctx context.Context, id int64) (int64, error)                                             // Delete user by ID
+	List(ctx context.Context, query, locale string, page, limit int64) ([]domain.User, int64, error) // List users with pagination and search, ordered for locale, returns users and total count
+
+	ListEmails(ctx context.Context, userID int64) ([]domain.Email, error)                                         // List all email addresses of a user, primary first
+	AddEmail(ctx context.Context, userID int64, address string) error                                             // Add a secondary, unverified email address
+	RemoveEmail(ctx context.Context, userID int64, address string) error                                          // Remove a secondary email address
+	SetPrimaryEmail(ctx context.Context, userID int64, address string) error                                      // Promote an owned address to primary and sync User.Email
+	SetEmailVerification(ctx context.Context, userID int64, address, tokenHash string, expiresAt time.Time) error // Store the hash of a verification token for an owned, unverified address
+	VerifyEmail(ctx context.Context, userID int64, address, tokenHash string) error                               // Mark an owned address as verified, consuming its unexpired verification token
+
+	Erase(ctx context.Context, id int64, reason string) (*domain.Erasure, error) // Irreversibly anonymize a user and leave a tombstone; returns the existing tombstone if already erased
+
+	LinkIdentity(ctx context.Context, identity *domain.Identity) error                 // Link an external identity to a user
+	UnlinkIdentity(ctx context.Context, userID int64, provider, subject string) error  // Unlink an external identity from a user
+	GetByIdentity(ctx context.Context, provider, subject string) (*domain.User, error) // Retrieve user by linked identity, nil if not found
+	ListIdentities(ctx context.Context, userID int64) ([]domain.Identity, error)       // List the external identities linked to a user
 }
 
 // usecaseImpl implements the business logic for user management operations.
 // It provides a clean separation between the transport layer and data layer.
 type usecaseImpl struct {
-	repo     Repository          // Repository for data access
-	log      *zap.Logger         // Logger for structured logging
-	validate *validator.Validate // Validator for request validation
+	repo          Repository          // Repository for data access
+	log           *zap.Logger         // Logger for structured logging
+	validate      *validator.Validate // Validator for request validation
+	usernames     UsernamePolicy      // Rules for usernames
+	audit         AuditLog            // Optional audit log, nil when auditing is disabled
+	verifications VerificationSender  // Optional sender of email verification tokens, nil when tokens are only issued on request
+}
+
+// Option configures optional behaviour of the user use case.
+type Option func(*usecaseImpl)
+
+// WithUsernamePolicy overrides the default username rules.
+func WithUsernamePolicy(p UsernamePolicy) Option {
+	return func(uc *usecaseImpl) {
+		uc.usernames = p
+	}
 }
 
 // New creates a new instance of Usecase with the provided repository and logger.
-func New(r Repository, log *zap.Logger) Usecase {
-	return &usecaseImpl{repo: r, log: log, validate: validator.New()}
+func New(r Repository, log *zap.Logger, opts ...Option) Usecase {
+	uc := &usecaseImpl{
+		repo:      r,
+		log:       log,
+		validate:  validator.New(),
+		usernames: DefaultUsernamePolicy(),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
 // formatValidationError converts validator.ValidationErrors into a human-readable error message.
@@ -44,7 +83,7 @@ func formatValidationError(err error) error {
 		var messages []string
 		for _, e := range validationErrors {
 			switch e.Tag() {
-			case "required":
+			case "required", "required_without_all":
 				messages = append(messages, fmt.Sprintf("%s is required", e.Field()))
 			case "email":
 				messages = append(messages, fmt.Sprintf("%s must be a valid email", e.Field()))
@@ -61,15 +100,93 @@ func formatValidationError(err error) error {
 	return err
 }
 
+// contactFields holds the optional contact attributes of a user in canonical form.
+type contactFields struct {
+	Phone    string
+	Locale   string
+	Timezone string
+}
+
+// normalizeContact validates the optional contact fields and converts them to canonical form:
+// phone numbers to E.164, locales to BCP 47 tags and timezones to IANA names.
+// Empty fields are left empty.
+func normalizeContact(phone, locale, timezone string) (contactFields, error) {
+	var out contactFields
+	var err error
+
+	if out.Phone, err = contact.NormalizePhone(phone); err != nil {
+		return contactFields{}, err
+	}
+	if out.Locale, err = contact.NormalizeLocale(locale); err != nil {
+		return contactFields{}, err
+	}
+	if out.Timezone, err = contact.ValidateTimezone(timezone); err != nil {
+		return contactFields{}, err
+	}
+
+	return out, nil
+}
+
+// checkClear returns a validation error when an update both sets and clears
+// the same field.
+func checkClear(in UpdateUserRequest) error {
+	set := map[string]string{
+		domain.FieldPhone:    in.Phone,
+		domain.FieldLocale:   in.Locale,
+		domain.FieldTimezone: in.Timezone,
+	}
+	for _, field := range in.Clear {
+		if set[field] != "" {
+			return pkgerrors.NewValidationError(field, "cannot both set and clear "+field)
+		}
+	}
+	return nil
+}
+
+// checkUsernameAvailable returns an AlreadyExists error when username is taken
+// by a user other than ownerID. An empty username is always available.
+func (uc *usecaseImpl) checkUsernameAvailable(ctx context.Context, username string, ownerID int64) error {
+	if username == "" {
+		return nil
+	}
+
+	existingUser, err := uc.repo.GetByUsername(ctx, username)
+	if err != nil {
+		uc.log.Error("failed to check existing username", zap.String("username", username), zap.Error(err))
+		return pkgerrors.NewInternalError("failed to validate username uniqueness", err)
+	}
+	if existingUser != nil && existingUser.ID != ownerID {
+		uc.log.Warn("username already exists", zap.String("username", username), zap.Int64("existing_id", existingUser.ID))
+		return pkgerrors.NewAlreadyExistsError("user", "username already exists")
+	}
+
+	return nil
+}
+
 // CreateUser creates a new user after validating the request and checking email uniqueness.
 func (uc *usecaseImpl) CreateUser(ctx context.Context, in CreateUserRequest) (*CreateUserResponse, error) {
 	uc.log.Info("creating user", zap.String("name", in.Name), zap.String("email", in.Email))
 
+	names := nameFields{Name: in.Name, GivenName: in.GivenName, FamilyName: in.FamilyName, DisplayName: in.DisplayName}.normalize()
+	in.Name, in.GivenName, in.FamilyName, in.DisplayName = names.Name, names.GivenName, names.FamilyName, names.DisplayName
+
 	if err := uc.validate.Struct(in); err != nil {
 		uc.log.Warn("validate failed", zap.Error(err))
 		return nil, formatValidationError(err)
 	}
 
+	contactInfo, err := normalizeContact(in.Phone, in.Locale, in.Timezone)
+	if err != nil {
+		uc.log.Warn("contact validation failed", zap.Error(err))
+		return nil, err
+	}
+
+	username, err := uc.usernames.Normalize(in.Username)
+	if err != nil {
+		uc.log.Warn("username validation failed", zap.String("username", in.Username), zap.Error(err))
+		return nil, err
+	}
+
 	// Check if email already exists
 	existingUser, err := uc.repo.GetByEmail(ctx, in.Email)
 	if err != nil {
@@ -82,15 +199,27 @@ func (uc *usecaseImpl) CreateUser(ctx context.Context, in CreateUserRequest) (*C
 		return nil, pkgerrors.NewAlreadyExistsError("user", "email already exists")
 	}
 
+	if err := uc.checkUsernameAvailable(ctx, username, 0); err != nil {
+		return nil, err
+	}
+
 	// Business logic: create user
-	id, err := uc.repo.Create(ctx, &domain.User{
-		Name:  in.Name,
-		Email: in.Email,
-	})
+	newUser := &domain.User{
+		Username: username,
+		Email:    in.Email,
+		Phone:    contactInfo.Phone,
+		Locale:   contactInfo.Locale,
+		Timezone: contactInfo.Timezone,
+	}
+	names.apply(newUser)
+
+	id, err := uc.repo.Create(ctx, newUser)
 	if err != nil {
 		uc.log.Error("failed to create user", zap.Error(err))
 		return nil, err
 	}
+
+	uc.recordAudit(ctx, id, domain.AuditUserCreated, "")
 	return &CreateUserResponse{ID: id}, nil
 }
 
@@ -98,11 +227,34 @@ func (uc *usecaseImpl) CreateUser(ctx context.Context, in CreateUserRequest) (*C
 func (uc *usecaseImpl) UpdateUser(ctx context.Context, in UpdateUserRequest) (*UpdateUserResponse, error) {
 	uc.log.Info("updating user", zap.Int64("id", in.ID), zap.String("name", in.Name), zap.String("email", in.Email))
 
+	names := nameFields{Name: in.Name, GivenName: in.GivenName, FamilyName: in.FamilyName, DisplayName: in.DisplayName}.normalize()
+	in.Name, in.GivenName, in.FamilyName, in.DisplayName = names.Name, names.GivenName, names.FamilyName, names.DisplayName
+
 	if err := uc.validate.Struct(in); err != nil {
 		uc.log.Warn("validate failed", zap.Error(err))
 		return nil, formatValidationError(err)
 	}
 
+	if err := checkClear(in); err != nil {
+		uc.log.Warn("clear validation failed", zap.Int64("id", in.ID), zap.Error(err))
+		return nil, err
+	}
+
+	contactInfo, err := normalizeContact(in.Phone, in.Locale, in.Timezone)
+	if err != nil {
+		uc.log.Warn("contact validation failed", zap.Int64("id", in.ID), zap.Error(err))
+		return nil, err
+	}
+
+	username, err := uc.usernames.Normalize(in.Username)
+	if err != nil {
+		uc.log.Warn("username validation failed", zap.Int64("id", in.ID), zap.String("username", in.Username), zap.Error(err))
+		return nil, err
+	}
+	if err := uc.checkUsernameAvailable(ctx, username, in.ID); err != nil {
+		return nil, err
+	}
+
 	if in.Email != "" {
 		existingUser, err := uc.repo.GetByEmail(ctx, in.Email)
 		if err != nil {
@@ -116,17 +268,37 @@ func (uc *usecaseImpl) UpdateUser(ctx context.Context, in UpdateUserRequest) (*U
 		}
 	}
 
+	updatedUser := &domain.User{
+		ID:       in.ID,
+		Username: username,
+		Email:    in.Email,
+		Phone:    contactInfo.Phone,
+		Locale:   contactInfo.Locale,
+		Timezone: contactInfo.Timezone,
+	}
+
+	// The computed legacy name depends on the stored name fields when only part of them change
+	if names.needsStoredNames() {
+		current, err := uc.repo.GetByID(ctx, in.ID)
+		if err != nil {
+			uc.log.Error("failed to load user for name update", zap.Int64("id", in.ID), zap.Error(err))
+			return nil, err
+		}
+		updatedUser.GivenName = current.GivenName
+		updatedUser.FamilyName = current.FamilyName
+		updatedUser.DisplayName = current.DisplayName
+	}
+	names.apply(updatedUser)
+
 	// Business logic: update user
-	id, err := uc.repo.Update(ctx, &domain.User{
-		ID:    in.ID,
-		Name:  in.Name,
-		Email: in.Email,
-	})
+	id, err := uc.repo.Update(ctx, updatedUser, in.Clear...)
 	if err != nil {
 		uc.log.Error("failed to update user", zap.Int64("id", in.ID), zap.Error(err))
 		return nil, err
 	}
 
+	uc.recordAudit(ctx, in.ID, domain.AuditUserUpdated, updatedFields(in))
+
 	return &UpdateUserResponse{ID: id}, nil
 }
 
@@ -145,6 +317,8 @@ func (uc *usecaseImpl) DeleteUser(ctx context.Context, in DeleteUserRequest) (*D
 		return nil, err
 	}
 
+	uc.recordAudit(ctx, in.ID, domain.AuditUserDeleted, "")
+
 	return &DeleteUserResponse{ID: id}, nil
 }
 
@@ -161,11 +335,43 @@ func (uc *usecaseImpl) GetUser(ctx context.Context, in GetUserRequest) (*GetUser
 		return nil, err
 	}
 
+	return toGetUserResponse(user), nil
+}
+
+// GetUserByUsername retrieves a user by their case-insensitive username.
+func (uc *usecaseImpl) GetUserByUsername(ctx context.Context, in GetUserByUsernameRequest) (*GetUserResponse, error) {
+	username := strings.ToLower(strings.TrimSpace(in.Username))
+	if username == "" {
+		uc.log.Warn("get user by username validation failed", zap.String("reason", "empty username"))
+		return nil, pkgerrors.NewValidationError("username", "username is required")
+	}
+
+	user, err := uc.repo.GetByUsername(ctx, username)
+	if err != nil {
+		uc.log.Error("failed to get user by username", zap.String("username", username), zap.Error(err))
+		return nil, err
+	}
+	if user == nil {
+		return nil, pkgerrors.NewNotFoundError("user", fmt.Sprintf("user not found: username=%s", username))
+	}
+
+	return toGetUserResponse(user), nil
+}
+
+// toGetUserResponse maps a domain user to the GetUser response DTO.
+func toGetUserResponse(user *domain.User) *GetUserResponse {
 	return &GetUserResponse{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
-	}, nil
+		ID:          user.ID,
+		Username:    user.Username,
+		Name:        user.Name,
+		GivenName:   user.GivenName,
+		FamilyName:  user.FamilyName,
+		DisplayName: user.DisplayName,
+		Email:       user.Email,
+		Phone:       user.Phone,
+		Locale:      user.Locale,
+		Timezone:    user.Timezone,
+	}
 }
 
 // ListUsers retrieves a paginated list of users with optional search functionality.
@@ -180,9 +386,15 @@ func (uc *usecaseImpl) ListUsers(ctx context.Context, in ListUsersRequest) (*Lis
 		in.Limit = 100
 	}
 
-	uc.log.Info("listing users", zap.String("query", in.Query), zap.Int64("page", in.Page), zap.Int64("limit", in.Limit))
+	locale, err := contact.NormalizeLocale(in.Locale)
+	if err != nil {
+		return nil, err
+	}
+	in.Locale = locale
+
+	uc.log.Info("listing users", zap.String("query", in.Query), zap.String("locale", in.Locale), zap.Int64("page", in.Page), zap.Int64("limit", in.Limit))
 
-	domainUsers, total, err := uc.repo.List(ctx, in.Query, in.Page, in.Limit)
+	domainUsers, total, err := uc.repo.List(ctx, in.Query, in.Locale, in.Page, in.Limit)
 	if err != nil {
 		// Repo already returns custom errors (e.g. ValidationError for invalid query)
 		uc.log.Error("failed to list users", zap.String("query", in.Query), zap.Int64("page", in.Page), zap.Int64("limit", in.Limit), zap.Error(err))
@@ -192,9 +404,16 @@ func (uc *usecaseImpl) ListUsers(ctx context.Context, in ListUsersRequest) (*Lis
 	users := make([]User, len(domainUsers))
 	for i, du := range domainUsers {
 		users[i] = User{
-			ID:    du.ID,
-			Name:  du.Name,
-			Email: du.Email,
+			ID:          du.ID,
+			Username:    du.Username,
+			Name:        du.Name,
+			GivenName:   du.GivenName,
+			FamilyName:  du.FamilyName,
+			DisplayName: du.DisplayName,
+			Email:       du.Email,
+			Phone:       du.Phone,
+			Locale:      du.Locale,
+			Timezone:    du.Timezone,
 		}
 	}
 
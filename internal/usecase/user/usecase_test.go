@@ -3,14 +3,18 @@ package user
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
 
 	domain "grpc-user-service/internal/domain/user"
+	pkgerrors "grpc-user-service/pkg/errors"
 )
 
 // MockRepository là mock implementation của Repository interface
@@ -39,7 +43,15 @@ func (m *MockRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *MockRepository) Update(ctx context.Context, u *domain.User) (int64, error) {
+func (m *MockRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, u *domain.User, cleared ...string) (int64, error) {
 	args := m.Called(ctx, u)
 	return args.Get(0).(int64), args.Error(1)
 }
@@ -49,11 +61,78 @@ func (m *MockRepository) Delete(ctx context.Context, id int64) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockRepository) List(ctx context.Context, query string, page, limit int64) ([]domain.User, int64, error) {
-	args := m.Called(ctx, query, page, limit)
+func (m *MockRepository) List(ctx context.Context, query, locale string, page, limit int64) ([]domain.User, int64, error) {
+	args := m.Called(ctx, query, locale, page, limit)
 	return args.Get(0).([]domain.User), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockRepository) Erase(ctx context.Context, id int64, reason string) (*domain.Erasure, error) {
+	args := m.Called(ctx, id, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Erasure), args.Error(1)
+}
+
+func (m *MockRepository) ListEmails(ctx context.Context, userID int64) ([]domain.Email, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Email), args.Error(1)
+}
+
+func (m *MockRepository) AddEmail(ctx context.Context, userID int64, address string) error {
+	args := m.Called(ctx, userID, address)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RemoveEmail(ctx context.Context, userID int64, address string) error {
+	args := m.Called(ctx, userID, address)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetPrimaryEmail(ctx context.Context, userID int64, address string) error {
+	args := m.Called(ctx, userID, address)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetEmailVerification(ctx context.Context, userID int64, address, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, userID, address, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) VerifyEmail(ctx context.Context, userID int64, address, tokenHash string) error {
+	args := m.Called(ctx, userID, address, tokenHash)
+	return args.Error(0)
+}
+
+func (m *MockRepository) LinkIdentity(ctx context.Context, identity *domain.Identity) error {
+	args := m.Called(ctx, identity)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UnlinkIdentity(ctx context.Context, userID int64, provider, subject string) error {
+	args := m.Called(ctx, userID, provider, subject)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByIdentity(ctx context.Context, provider, subject string) (*domain.User, error) {
+	args := m.Called(ctx, provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockRepository) ListIdentities(ctx context.Context, userID int64) ([]domain.Identity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Identity), args.Error(1)
+}
+
 // Test helper để tạo usecase với mock repo
 func setupTestUsecase(t *testing.T) (Usecase, *MockRepository) {
 	mockRepo := new(MockRepository)
@@ -105,20 +184,26 @@ func TestCreateUser_ValidationError_NameRequired(t *testing.T) {
 	assert.Contains(t, err.Error(), "Name is required")
 }
 
-func TestCreateUser_ValidationError_NameTooShort(t *testing.T) {
-	uc, _ := setupTestUsecase(t)
+func TestCreateUser_ShortNameAccepted(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
 	ctx := context.Background()
 
 	req := CreateUserRequest{
-		Name:  "Jo", // Too short (<3)
-		Email: "john@example.com",
+		Name:  "Li", // Short real-world names are valid
+		Email: "li@example.com",
 	}
 
+	mockRepo.On("GetByEmail", ctx, req.Email).Return(nil, nil)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+		return u.Name == "Li" && u.DisplayName == "Li"
+	})).Return(int64(1), nil)
+
 	resp, err := uc.CreateUser(ctx, req)
 
-	assert.Error(t, err)
-	assert.Nil(t, resp)
-	assert.Contains(t, err.Error(), "Name must be at least 3 characters")
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	mockRepo.AssertExpectations(t)
 }
 
 func TestCreateUser_ValidationError_EmailRequired(t *testing.T) {
@@ -158,15 +243,15 @@ func TestCreateUser_ValidationError_MultipleErrors(t *testing.T) {
 	ctx := context.Background()
 
 	req := CreateUserRequest{
-		Name:  "Jo",      // Too short
-		Email: "invalid", // Invalid email
+		Name:  strings.Repeat("a", 101), // Too long
+		Email: "invalid",                // Invalid email
 	}
 
 	resp, err := uc.CreateUser(ctx, req)
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
-	assert.Contains(t, err.Error(), "Name must be at least 3 characters")
+	assert.Contains(t, err.Error(), "Name must be at most 100 characters")
 	assert.Contains(t, err.Error(), "Email must be a valid email")
 }
 
@@ -193,6 +278,219 @@ func TestCreateUser_SemanticValidation_EmailAlreadyExists(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCreateUser_ContactFields_Normalized(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	req := CreateUserRequest{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Phone:    "+84 90-123-4567",
+		Locale:   "vi_vn",
+		Timezone: "Asia/Ho_Chi_Minh",
+	}
+
+	mockRepo.On("GetByEmail", ctx, req.Email).Return(nil, nil)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+		return u.Phone == "+84901234567" && u.Locale == "vi-VN" && u.Timezone == "Asia/Ho_Chi_Minh"
+	})).Return(int64(1), nil)
+
+	resp, err := uc.CreateUser(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateUser_ContactFields_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      CreateUserRequest
+		errorMsg string
+	}{
+		{
+			name:     "phone without international prefix",
+			req:      CreateUserRequest{Name: "John Doe", Email: "john@example.com", Phone: "0901234567"},
+			errorMsg: "phone",
+		},
+		{
+			name:     "malformed locale",
+			req:      CreateUserRequest{Name: "John Doe", Email: "john@example.com", Locale: "not a locale"},
+			errorMsg: "locale",
+		},
+		{
+			name:     "unknown timezone",
+			req:      CreateUserRequest{Name: "John Doe", Email: "john@example.com", Timezone: "Mars/Olympus_Mons"},
+			errorMsg: "timezone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, mockRepo := setupTestUsecase(t)
+
+			resp, err := uc.CreateUser(context.Background(), tt.req)
+
+			assert.Error(t, err)
+			assert.Nil(t, resp)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateUser_StructuredName(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	req := CreateUserRequest{
+		GivenName:  "  Nguy\u0065\u0302\u0303n  ", // Decomposed "Nguyễn" with surrounding spaces
+		FamilyName: "Văn   An",
+		Email:      "an@example.com",
+	}
+
+	mockRepo.On("GetByEmail", ctx, req.Email).Return(nil, nil)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+		return u.GivenName == "Nguy\u1ec5n" &&
+			u.FamilyName == "Văn An" &&
+			u.DisplayName == "" &&
+			u.Name == "Nguy\u1ec5n Văn An"
+	})).Return(int64(1), nil)
+
+	resp, err := uc.CreateUser(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateUser_DisplayNameWinsForComputedName(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	req := CreateUserRequest{
+		GivenName:   "Robert",
+		FamilyName:  "Smith",
+		DisplayName: "Bob",
+		Email:       "bob@example.com",
+	}
+
+	mockRepo.On("GetByEmail", ctx, req.Email).Return(nil, nil)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+		return u.Name == "Bob" && u.GivenName == "Robert" && u.FamilyName == "Smith"
+	})).Return(int64(1), nil)
+
+	_, err := uc.CreateUser(ctx, req)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateUser_ValidationError_WhitespaceOnlyName(t *testing.T) {
+	uc, _ := setupTestUsecase(t)
+
+	resp, err := uc.CreateUser(context.Background(), CreateUserRequest{
+		Name:  "   ",
+		Email: "john@example.com",
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "Name is required")
+}
+
+func TestCreateUser_Username_NormalizedAndUnique(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	req := CreateUserRequest{
+		Username: " John.Doe ",
+		Name:     "John Doe",
+		Email:    "john@example.com",
+	}
+
+	mockRepo.On("GetByEmail", ctx, req.Email).Return(nil, nil)
+	mockRepo.On("GetByUsername", ctx, "john.doe").Return(nil, nil)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+		return u.Username == "john.doe"
+	})).Return(int64(1), nil)
+
+	resp, err := uc.CreateUser(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateUser_Username_AlreadyExists(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	req := CreateUserRequest{
+		Username: "JohnDoe",
+		Name:     "John Doe",
+		Email:    "john@example.com",
+	}
+
+	mockRepo.On("GetByEmail", ctx, req.Email).Return(nil, nil)
+	mockRepo.On("GetByUsername", ctx, "johndoe").Return(&domain.User{ID: 2, Username: "johndoe"}, nil)
+
+	resp, err := uc.CreateUser(ctx, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "username already exists")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateUser_Username_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		errorMsg string
+	}{
+		{name: "reserved word", username: "Admin", errorMsg: "username is reserved"},
+		{name: "too short", username: "jd", errorMsg: "username must be between 3 and 30 characters"},
+		{name: "invalid characters", username: "john doe!", errorMsg: "username contains invalid characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, mockRepo := setupTestUsecase(t)
+
+			resp, err := uc.CreateUser(context.Background(), CreateUserRequest{
+				Username: tt.username,
+				Name:     "John Doe",
+				Email:    "john@example.com",
+			})
+
+			assert.Error(t, err)
+			assert.Nil(t, resp)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateUser_Username_CustomPolicy(t *testing.T) {
+	policy, err := NewUsernamePolicy(2, 8, "a-z", []string{"boss"})
+	assert.NoError(t, err)
+
+	mockRepo := new(MockRepository)
+	uc := New(mockRepo, zaptest.NewLogger(t), WithUsernamePolicy(policy))
+	ctx := context.Background()
+
+	_, err = uc.CreateUser(ctx, CreateUserRequest{Username: "Boss", Name: "Big Boss", Email: "boss@example.com"})
+	assert.ErrorContains(t, err, "username is reserved")
+
+	_, err = uc.CreateUser(ctx, CreateUserRequest{Username: "ab1", Name: "Ab", Email: "ab@example.com"})
+	assert.ErrorContains(t, err, "username contains invalid characters")
+}
+
 // ==================== UPDATE USER TESTS ====================
 
 func TestUpdateUser_Success(t *testing.T) {
@@ -245,13 +543,13 @@ func TestUpdateUser_PartialUpdate_NameOnly(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestUpdateUser_ValidationError_NameTooShort(t *testing.T) {
+func TestUpdateUser_ValidationError_NameTooLong(t *testing.T) {
 	uc, _ := setupTestUsecase(t)
 	ctx := context.Background()
 
 	req := UpdateUserRequest{
 		ID:    1,
-		Name:  "Jo", // Too short
+		Name:  strings.Repeat("a", 101), // Too long
 		Email: "john@example.com",
 	}
 
@@ -259,7 +557,20 @@ func TestUpdateUser_ValidationError_NameTooShort(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
-	assert.Contains(t, err.Error(), "Name must be at least 3 characters")
+	assert.Contains(t, err.Error(), "Name must be at most 100 characters")
+}
+
+func TestUpdateUser_ValidationError_SetAndClear(t *testing.T) {
+	uc, _ := setupTestUsecase(t)
+	ctx := context.Background()
+
+	resp, err := uc.UpdateUser(ctx, UpdateUserRequest{ID: 1, Phone: "+84901234567", Clear: []string{"phone"}})
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "cannot both set and clear phone")
+
+	_, err = uc.UpdateUser(ctx, UpdateUserRequest{ID: 1, Clear: []string{"email"}})
+	assert.Error(t, err, "only optional fields can be cleared")
 }
 
 func TestUpdateUser_ValidationError_EmailInvalid(t *testing.T) {
@@ -303,6 +614,29 @@ func TestUpdateUser_SemanticValidation_EmailAlreadyExists(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUpdateUser_GivenNameOnly_RecomputesName(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	req := UpdateUserRequest{
+		ID:        1,
+		GivenName: "Jonathan",
+	}
+
+	current := &domain.User{ID: 1, Name: "John Doe", GivenName: "John", FamilyName: "Doe"}
+	mockRepo.On("GetByID", ctx, req.ID).Return(current, nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(u *domain.User) bool {
+		return u.GivenName == "Jonathan" && u.FamilyName == "Doe" && u.Name == "Jonathan Doe"
+	})).Return(int64(1), nil)
+
+	resp, err := uc.UpdateUser(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	mockRepo.AssertExpectations(t)
+}
+
 // ==================== DELETE USER TESTS ====================
 
 func TestDeleteUser_Success(t *testing.T) {
@@ -372,8 +706,465 @@ func TestGetUser_InvalidID(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid user id")
 }
 
+func TestGetUserByUsername_Success(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	expectedUser := &domain.User{ID: 1, Username: "johndoe", Name: "John Doe", Email: "john@example.com"}
+	mockRepo.On("GetByUsername", ctx, "johndoe").Return(expectedUser, nil)
+
+	resp, err := uc.GetUserByUsername(ctx, GetUserByUsernameRequest{Username: "JohnDoe"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), resp.ID)
+	assert.Equal(t, "johndoe", resp.Username)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetUserByUsername_NotFound(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByUsername", ctx, "ghost").Return(nil, nil)
+
+	resp, err := uc.GetUserByUsername(ctx, GetUserByUsernameRequest{Username: "ghost"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "user not found")
+
+	mockRepo.AssertExpectations(t)
+}
+
 // ==================== LIST USERS TESTS ====================
 
+func TestAddUserEmail_Success(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByID", ctx, int64(1)).Return(&domain.User{ID: 1, Email: "john@example.com"}, nil)
+	mockRepo.On("GetByEmail", ctx, "john@work.example.com").Return(nil, nil)
+	mockRepo.On("AddEmail", ctx, int64(1), "john@work.example.com").Return(nil)
+	mockRepo.On("SetEmailVerification", ctx, int64(1), "john@work.example.com", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true},
+		{UserID: 1, Address: "john@work.example.com"},
+	}, nil)
+
+	resp, err := uc.AddUserEmail(ctx, AddUserEmailRequest{UserID: 1, Email: "john@work.example.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), resp.UserID)
+	assert.Len(t, resp.Emails, 2)
+	assert.True(t, resp.Emails[0].Primary)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// sentVerification records the token handed to a VerificationSender
+type sentVerification struct {
+	address, token string
+	expiresAt      time.Time
+}
+
+func (s *sentVerification) SendVerification(_ context.Context, _ int64, address, token string, expiresAt time.Time) error {
+	s.address, s.token, s.expiresAt = address, token, expiresAt
+	return nil
+}
+
+func TestAddUserEmail_SendsVerificationToken(t *testing.T) {
+	mockRepo := new(MockRepository)
+	sent := &sentVerification{}
+	uc := New(mockRepo, zaptest.NewLogger(t), WithVerificationSender(sent))
+	ctx := context.Background()
+
+	var storedHash string
+	mockRepo.On("GetByID", ctx, int64(1)).Return(&domain.User{ID: 1, Email: "john@example.com"}, nil)
+	mockRepo.On("GetByEmail", ctx, "john@work.example.com").Return(nil, nil)
+	mockRepo.On("AddEmail", ctx, int64(1), "john@work.example.com").Return(nil)
+	mockRepo.On("SetEmailVerification", ctx, int64(1), "john@work.example.com", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { storedHash = args.String(3) }).Return(nil)
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true},
+		{UserID: 1, Address: "john@work.example.com"},
+	}, nil)
+
+	_, err := uc.AddUserEmail(ctx, AddUserEmailRequest{UserID: 1, Email: "john@work.example.com"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "john@work.example.com", sent.address)
+	assert.NotEmpty(t, sent.token)
+	assert.Equal(t, hashVerificationToken(sent.token), storedHash, "only the hash of the token is stored")
+	assert.WithinDuration(t, time.Now().Add(EmailVerificationTTL), sent.expiresAt, time.Minute)
+}
+
+func TestAddUserEmail_AlreadyOwnedByAnotherUser(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	// GetByEmail matches secondary addresses too
+	mockRepo.On("GetByID", ctx, int64(1)).Return(&domain.User{ID: 1, Email: "john@example.com"}, nil)
+	mockRepo.On("GetByEmail", ctx, "shared@example.com").Return(&domain.User{ID: 2, Email: "jane@example.com"}, nil)
+
+	resp, err := uc.AddUserEmail(ctx, AddUserEmailRequest{UserID: 1, Email: "shared@example.com"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.IsType(t, &pkgerrors.AlreadyExistsError{}, err)
+	mockRepo.AssertNotCalled(t, "AddEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddUserEmail_ValidationError(t *testing.T) {
+	uc, _ := setupTestUsecase(t)
+
+	resp, err := uc.AddUserEmail(context.Background(), AddUserEmailRequest{UserID: 1, Email: "not-an-email"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "Email must be a valid email")
+}
+
+func TestRemoveUserEmail_PrimaryRejected(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true},
+	}, nil)
+
+	resp, err := uc.RemoveUserEmail(ctx, RemoveUserEmailRequest{UserID: 1, Email: "john@example.com"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.IsType(t, &pkgerrors.ValidationError{}, err)
+	mockRepo.AssertNotCalled(t, "RemoveEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemoveUserEmail_NotOwned(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true},
+	}, nil)
+
+	resp, err := uc.RemoveUserEmail(ctx, RemoveUserEmailRequest{UserID: 1, Email: "jane@example.com"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.IsType(t, &pkgerrors.NotFoundError{}, err)
+}
+
+func TestSetPrimaryUserEmail_Success(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true},
+		{UserID: 1, Address: "john@work.example.com"},
+	}, nil).Once()
+	mockRepo.On("SetPrimaryEmail", ctx, int64(1), "john@work.example.com").Return(nil)
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@work.example.com", Primary: true},
+		{UserID: 1, Address: "john@example.com"},
+	}, nil).Once()
+
+	resp, err := uc.SetPrimaryUserEmail(ctx, SetPrimaryUserEmailRequest{UserID: 1, Email: "john@work.example.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "john@work.example.com", resp.Emails[0].Email)
+	assert.True(t, resp.Emails[0].Primary)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVerifyUserEmail_AlreadyVerified(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true, Verified: true},
+	}, nil)
+
+	resp, err := uc.VerifyUserEmail(ctx, VerifyUserEmailRequest{UserID: 1, Email: "john@example.com", Token: "token"})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Emails[0].Verified)
+	mockRepo.AssertNotCalled(t, "VerifyEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVerifyUserEmail_ConsumesTokenHash(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true},
+	}, nil).Once()
+	mockRepo.On("VerifyEmail", ctx, int64(1), "john@example.com", hashVerificationToken("token")).Return(nil)
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true, Verified: true},
+	}, nil).Once()
+
+	resp, err := uc.VerifyUserEmail(ctx, VerifyUserEmailRequest{UserID: 1, Email: "john@example.com", Token: "token"})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Emails[0].Verified)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVerifyUserEmail_InvalidToken(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true},
+	}, nil)
+	mockRepo.On("VerifyEmail", ctx, int64(1), "john@example.com", hashVerificationToken("guess")).
+		Return(pkgerrors.NewValidationError("token", "invalid or expired verification token"))
+
+	resp, err := uc.VerifyUserEmail(ctx, VerifyUserEmailRequest{UserID: 1, Email: "john@example.com", Token: "guess"})
+
+	assert.Nil(t, resp)
+	assert.IsType(t, &pkgerrors.ValidationError{}, err)
+}
+
+func TestVerifyUserEmail_TokenRequired(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+
+	resp, err := uc.VerifyUserEmail(context.Background(), VerifyUserEmailRequest{UserID: 1, Email: "john@example.com"})
+
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "Token is required")
+	mockRepo.AssertNotCalled(t, "VerifyEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIssueUserEmailVerification_Success(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	var storedHash string
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true},
+	}, nil)
+	mockRepo.On("SetEmailVerification", ctx, int64(1), "john@example.com", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { storedHash = args.String(3) }).Return(nil)
+
+	resp, err := uc.IssueUserEmailVerification(ctx, IssueUserEmailVerificationRequest{UserID: 1, Email: "john@example.com"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "john@example.com", resp.Email)
+	assert.NotEmpty(t, resp.Token)
+	assert.Equal(t, hashVerificationToken(resp.Token), storedHash)
+	assert.True(t, resp.ExpiresAt.After(time.Now()))
+}
+
+func TestIssueUserEmailVerification_AlreadyVerified(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true, Verified: true},
+	}, nil)
+
+	resp, err := uc.IssueUserEmailVerification(ctx, IssueUserEmailVerificationRequest{UserID: 1, Email: "john@example.com"})
+
+	assert.Nil(t, resp)
+	assert.IsType(t, &pkgerrors.ValidationError{}, err)
+	mockRepo.AssertNotCalled(t, "SetEmailVerification", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLinkIdentity_Success(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByID", ctx, int64(1)).Return(&domain.User{ID: 1}, nil)
+	mockRepo.On("GetByIdentity", ctx, "google", "abc123").Return(nil, nil)
+	mockRepo.On("LinkIdentity", ctx, &domain.Identity{UserID: 1, Provider: "google", Subject: "abc123"}).Return(nil)
+
+	resp, err := uc.LinkIdentity(ctx, LinkIdentityRequest{UserID: 1, Provider: " Google ", Subject: "abc123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "google", resp.Provider)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLinkIdentity_AlreadyLinkedToSameUser(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByID", ctx, int64(1)).Return(&domain.User{ID: 1}, nil)
+	mockRepo.On("GetByIdentity", ctx, "google", "abc123").Return(&domain.User{ID: 1}, nil)
+
+	resp, err := uc.LinkIdentity(ctx, LinkIdentityRequest{UserID: 1, Provider: "google", Subject: "abc123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), resp.UserID)
+	mockRepo.AssertNotCalled(t, "LinkIdentity", mock.Anything, mock.Anything)
+}
+
+func TestLinkIdentity_LinkedToAnotherUser(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByID", ctx, int64(1)).Return(&domain.User{ID: 1}, nil)
+	mockRepo.On("GetByIdentity", ctx, "google", "abc123").Return(&domain.User{ID: 2}, nil)
+
+	resp, err := uc.LinkIdentity(ctx, LinkIdentityRequest{UserID: 1, Provider: "google", Subject: "abc123"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.IsType(t, &pkgerrors.AlreadyExistsError{}, err)
+}
+
+func TestLinkIdentity_ValidationError(t *testing.T) {
+	uc, _ := setupTestUsecase(t)
+
+	resp, err := uc.LinkIdentity(context.Background(), LinkIdentityRequest{UserID: 1, Provider: "google", Subject: "  "})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "Subject is required")
+}
+
+func TestUnlinkIdentity_Success(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("UnlinkIdentity", ctx, int64(1), "github", "42").Return(nil)
+
+	resp, err := uc.UnlinkIdentity(ctx, UnlinkIdentityRequest{UserID: 1, Provider: "GitHub", Subject: "42"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), resp.UserID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetUserByIdentity_NotFound(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByIdentity", ctx, "google", "unknown").Return(nil, nil)
+
+	resp, err := uc.GetUserByIdentity(ctx, GetUserByIdentityRequest{Provider: "google", Subject: "unknown"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.IsType(t, &pkgerrors.NotFoundError{}, err)
+}
+
+// memAuditLog is an in-memory AuditLog for tests
+type memAuditLog struct {
+	entries []domain.AuditEntry
+}
+
+func (a *memAuditLog) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	entry.ID = int64(len(a.entries) + 1)
+	entry.CreatedAt = time.Now()
+	a.entries = append(a.entries, *entry)
+	return nil
+}
+
+func (a *memAuditLog) ListByUser(ctx context.Context, userID int64) ([]domain.AuditEntry, error) {
+	var out []domain.AuditEntry
+	for _, e := range a.entries {
+		if e.UserID == userID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func TestUpdateUser_RecordsAuditEntryWithoutPersonalData(t *testing.T) {
+	mockRepo := new(MockRepository)
+	audit := &memAuditLog{}
+	uc := New(mockRepo, zaptest.NewLogger(t), WithAuditLog(audit))
+	ctx := context.Background()
+
+	mockRepo.On("GetByEmail", ctx, "john.new@example.com").Return(nil, nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*user.User")).Return(int64(1), nil)
+
+	_, err := uc.UpdateUser(ctx, UpdateUserRequest{ID: 1, Name: "John Updated", Email: "john.new@example.com"})
+
+	assert.NoError(t, err)
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, domain.AuditUserUpdated, audit.entries[0].Action)
+		assert.Equal(t, "name,email", audit.entries[0].Details)
+	}
+}
+
+func TestExportUserData_Success(t *testing.T) {
+	mockRepo := new(MockRepository)
+	audit := &memAuditLog{}
+	uc := New(mockRepo, zaptest.NewLogger(t), WithAuditLog(audit))
+	ctx := context.Background()
+
+	_ = audit.Record(ctx, &domain.AuditEntry{UserID: 1, Action: domain.AuditUserCreated})
+	mockRepo.On("GetByID", ctx, int64(1)).Return(&domain.User{ID: 1, Username: "johndoe", Name: "John Doe", Email: "john@example.com"}, nil)
+	mockRepo.On("ListEmails", ctx, int64(1)).Return([]domain.Email{
+		{UserID: 1, Address: "john@example.com", Primary: true},
+		{UserID: 1, Address: "john@work.example.com"},
+	}, nil)
+	mockRepo.On("ListIdentities", ctx, int64(1)).Return([]domain.Identity{
+		{UserID: 1, Provider: "google", Subject: "abc123"},
+	}, nil)
+
+	resp, err := uc.ExportUserData(ctx, ExportUserDataRequest{UserID: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ExportFormatVersion, resp.FormatVersion)
+	assert.Equal(t, "johndoe", resp.Profile.Username)
+	assert.Len(t, resp.Emails, 2)
+	assert.Equal(t, []LinkedIdentity{{Provider: "google", Subject: "abc123"}}, resp.Identities)
+	// The export itself is audited and part of the bundle
+	if assert.Len(t, resp.AuditEntries, 2) {
+		assert.Equal(t, domain.AuditUserCreated, resp.AuditEntries[0].Action)
+		assert.Equal(t, domain.AuditUserExported, resp.AuditEntries[1].Action)
+	}
+}
+
+func TestExportUserData_NotFound(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByID", ctx, int64(99)).Return(nil, pkgerrors.NewNotFoundError("user", "user not found: id=99"))
+
+	resp, err := uc.ExportUserData(ctx, ExportUserDataRequest{UserID: 99})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.IsType(t, &pkgerrors.NotFoundError{}, err)
+}
+
+func TestEraseUser_Success(t *testing.T) {
+	mockRepo := new(MockRepository)
+	audit := &memAuditLog{}
+	uc := New(mockRepo, zaptest.NewLogger(t), WithAuditLog(audit))
+	ctx := context.Background()
+
+	erasedAt := time.Now().UTC()
+	mockRepo.On("Erase", ctx, int64(1), "ticket-42").Return(&domain.Erasure{UserID: 1, Reason: "ticket-42", ErasedAt: erasedAt}, nil)
+
+	resp, err := uc.EraseUser(ctx, EraseUserRequest{UserID: 1, Reason: "ticket-42"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), resp.UserID)
+	assert.Equal(t, erasedAt, resp.ErasedAt)
+	if assert.Len(t, audit.entries, 1) {
+		assert.Equal(t, domain.AuditUserErased, audit.entries[0].Action)
+	}
+}
+
+func TestEraseUser_InvalidID(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+
+	resp, err := uc.EraseUser(context.Background(), EraseUserRequest{UserID: 0})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Erase", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestListUsers_Success(t *testing.T) {
 	uc, mockRepo := setupTestUsecase(t)
 	ctx := context.Background()
@@ -390,7 +1181,7 @@ func TestListUsers_Success(t *testing.T) {
 	}
 
 	// Mock List returns users and total count
-	mockRepo.On("List", ctx, req.Query, req.Page, req.Limit).Return(expectedUsers, int64(25), nil)
+	mockRepo.On("List", ctx, req.Query, req.Locale, req.Page, req.Limit).Return(expectedUsers, int64(25), nil)
 
 	resp, err := uc.ListUsers(ctx, req)
 
@@ -411,6 +1202,28 @@ func TestListUsers_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestListUsers_NormalizesLocale(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	mockRepo.On("List", ctx, "", "sv-SE", int64(1), int64(10)).Return([]domain.User{}, int64(0), nil)
+
+	_, err := uc.ListUsers(ctx, ListUsersRequest{Locale: "sv_se"})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListUsers_ValidationError_InvalidLocale(t *testing.T) {
+	uc, mockRepo := setupTestUsecase(t)
+	ctx := context.Background()
+
+	resp, err := uc.ListUsers(ctx, ListUsersRequest{Locale: "not a locale"})
+
+	assert.Nil(t, resp)
+	assert.IsType(t, &pkgerrors.ValidationError{}, err)
+	mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // ==================== VALIDATION HELPER TESTS ====================
 
 func TestFormatValidationError(t *testing.T) {
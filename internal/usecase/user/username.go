@@ -0,0 +1,100 @@
+package user
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+const (
+	// DefaultUsernameMinLength is the default minimum username length in characters
+	DefaultUsernameMinLength = 3
+	// DefaultUsernameMaxLength is the default maximum username length in characters
+	DefaultUsernameMaxLength = 30
+	// DefaultUsernameCharset is the default regexp character class body for usernames
+	DefaultUsernameCharset = "a-z0-9._-"
+)
+
+// DefaultReservedUsernames lists handles that cannot be claimed because they
+// collide with routes, system accounts or could be used to impersonate staff.
+var DefaultReservedUsernames = []string{
+	"admin", "administrator", "root", "system", "api", "www", "mail",
+	"support", "help", "security", "staff", "moderator", "me", "self",
+	"user", "users", "by-username", "null", "undefined",
+}
+
+// UsernamePolicy defines the rules a username must satisfy.
+// Usernames are case-insensitive and stored in lowercase.
+type UsernamePolicy struct {
+	minLength int
+	maxLength int
+	pattern   *regexp.Regexp
+	reserved  map[string]struct{}
+}
+
+// NewUsernamePolicy creates a username policy. charset is the body of a regexp
+// character class (e.g. "a-z0-9._-") matched against the lowercased username.
+func NewUsernamePolicy(minLength, maxLength int, charset string, reserved []string) (UsernamePolicy, error) {
+	if minLength <= 0 {
+		return UsernamePolicy{}, fmt.Errorf("username min length must be positive, got %d", minLength)
+	}
+	if maxLength < minLength {
+		return UsernamePolicy{}, fmt.Errorf("username max length (%d) cannot be less than min length (%d)", maxLength, minLength)
+	}
+
+	pattern, err := regexp.Compile("^[" + charset + "]+$")
+	if err != nil {
+		return UsernamePolicy{}, fmt.Errorf("invalid username charset %q: %w", charset, err)
+	}
+
+	reservedSet := make(map[string]struct{}, len(reserved))
+	for _, r := range reserved {
+		if r = strings.ToLower(strings.TrimSpace(r)); r != "" {
+			reservedSet[r] = struct{}{}
+		}
+	}
+
+	return UsernamePolicy{
+		minLength: minLength,
+		maxLength: maxLength,
+		pattern:   pattern,
+		reserved:  reservedSet,
+	}, nil
+}
+
+// DefaultUsernamePolicy returns the policy used when none is configured.
+func DefaultUsernamePolicy() UsernamePolicy {
+	policy, err := NewUsernamePolicy(DefaultUsernameMinLength, DefaultUsernameMaxLength, DefaultUsernameCharset, DefaultReservedUsernames)
+	if err != nil {
+		panic(err) // defaults are constant and always valid
+	}
+	return policy
+}
+
+// Normalize validates username against the policy and returns its canonical
+// lowercase form. An empty username is returned unchanged.
+func (p UsernamePolicy) Normalize(username string) (string, error) {
+	username = strings.ToLower(norm.NFC.String(strings.TrimSpace(username)))
+	if username == "" {
+		return "", nil
+	}
+
+	length := utf8.RuneCountInString(username)
+	if length < p.minLength || length > p.maxLength {
+		return "", pkgerrors.NewValidationError("username",
+			fmt.Sprintf("username must be between %d and %d characters", p.minLength, p.maxLength))
+	}
+	if !p.pattern.MatchString(username) {
+		return "", pkgerrors.NewValidationError("username", "username contains invalid characters")
+	}
+	if _, ok := p.reserved[username]; ok {
+		return "", pkgerrors.NewValidationError("username", "username is reserved")
+	}
+
+	return username, nil
+}
@@ -0,0 +1,58 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// EmailVerificationTTL is how long an email verification token stays valid.
+const EmailVerificationTTL = 24 * time.Hour
+
+// VerificationSender delivers email verification tokens to the addresses they
+// verify, typically by mail. Only the owner of an address can then read the
+// token and confirm the address with VerifyUserEmail.
+type VerificationSender interface {
+	SendVerification(ctx context.Context, userID int64, address, token string, expiresAt time.Time) error
+}
+
+// WithVerificationSender sends a verification token to every address added to
+// a user. Without it, tokens are only handed out by IssueUserEmailVerification,
+// for an internal mail service to deliver.
+func WithVerificationSender(s VerificationSender) Option {
+	return func(uc *usecaseImpl) {
+		uc.verifications = s
+	}
+}
+
+// hashVerificationToken returns the hex SHA-256 of a verification token. Only
+// the hash is stored, so a database dump cannot be used to verify addresses.
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueVerification stores a new verification token for an unverified address
+// of a user, replacing any pending one, and sends it when a sender is
+// configured. Send failures are logged; the token can be issued again.
+func (uc *usecaseImpl) issueVerification(ctx context.Context, userID int64, address string) (string, time.Time, error) {
+	token := rand.Text()
+	expiresAt := time.Now().Add(EmailVerificationTTL)
+
+	if err := uc.repo.SetEmailVerification(ctx, userID, address, hashVerificationToken(token), expiresAt); err != nil {
+		uc.log.Error("failed to store email verification token", zap.Int64("user_id", userID), zap.Error(err))
+		return "", time.Time{}, err
+	}
+
+	if uc.verifications != nil {
+		if err := uc.verifications.SendVerification(ctx, userID, address, token, expiresAt); err != nil {
+			uc.log.Warn("failed to send email verification token", zap.Int64("user_id", userID), zap.Error(err))
+		}
+	}
+
+	return token, expiresAt, nil
+}
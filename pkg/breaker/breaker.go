@@ -0,0 +1,235 @@
+// Package breaker implements circuit breakers, which stop calling a failing
+// dependency for a while instead of letting every caller wait for it to time
+// out.
+//
+// A breaker starts closed and lets calls through. After FailureThreshold
+// consecutive failures it opens and rejects calls with ErrOpen for
+// OpenTimeout. It then turns half-open and lets HalfOpenProbes calls through:
+// if they all succeed it closes again, and any failure opens it again.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrOpen is returned by Do when the circuit is open and the call was not made.
+var ErrOpen = errors.New("breaker: circuit open")
+
+// State is the state of a circuit.
+type State int
+
+// Circuit states.
+const (
+	Closed   State = iota // Calls go through
+	Open                  // Calls are rejected with ErrOpen
+	HalfOpen              // A few probe calls go through to test the dependency
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Config holds the thresholds of a breaker.
+type Config struct {
+	FailureThreshold int           // Consecutive failures that open the circuit
+	OpenTimeout      time.Duration // How long the circuit stays open before probing
+	HalfOpenProbes   int           // Successful probes needed to close the circuit
+}
+
+// metrics holds the state and counters of every breaker, published through
+// expvar under "circuit_breakers".
+var metrics = expvar.NewMap("circuit_breakers")
+
+// Counter names in the metrics of a breaker.
+const (
+	metricState      = "state"      // Current state
+	metricOpens      = "opens"      // Times the circuit opened
+	metricRejections = "rejections" // Calls rejected while open
+)
+
+// registry holds the breakers by name, for States.
+var registry sync.Map
+
+// Breaker is a circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	name    string
+	cfg     Config
+	log     *zap.Logger
+	now     func() time.Time
+	metrics *expvar.Map
+
+	mu         sync.Mutex
+	state      State
+	generation uint64 // Incremented on every state change
+	failures   int    // Consecutive failures while closed
+	probes     int    // Probes let through while half-open
+	successes  int    // Successful probes while half-open
+	openedAt   time.Time
+}
+
+// New creates a closed breaker called name, publishing its state and
+// counters in expvar and in States. A breaker created with the name of an
+// earlier one replaces it there.
+func New(name string, cfg Config, log *zap.Logger) *Breaker {
+	b := &Breaker{
+		name:    name,
+		cfg:     cfg,
+		log:     log,
+		now:     time.Now,
+		metrics: new(expvar.Map).Init(),
+	}
+	b.metrics.Set(metricState, expvar.Func(func() any { return b.State().String() }))
+	b.metrics.Add(metricOpens, 0)
+	b.metrics.Add(metricRejections, 0)
+
+	metrics.Set(name, b.metrics)
+	registry.Store(name, b)
+	return b
+}
+
+// States returns the state of every breaker by name.
+func States() map[string]State {
+	states := make(map[string]State)
+	registry.Range(func(name, b any) bool {
+		states[name.(string)] = b.(*Breaker).State()
+		return true
+	})
+	return states
+}
+
+// Name returns the name of the breaker.
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the circuit.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.expire()
+	return b.state
+}
+
+// Do calls fn unless the circuit is open, in which case it returns ErrOpen.
+// An error returned by fn counts as a failure of the dependency, except for
+// context cancellation by the caller; callers should return nil from fn for
+// errors that do not reflect on the dependency, such as a record not found.
+// A panic in fn counts as a failure and is propagated.
+func (b *Breaker) Do(fn func() error) error {
+	generation, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	// Recorded even if fn panics, so that a half-open probe frees its slot
+	success := false
+	defer func() {
+		b.record(generation, success)
+	}()
+
+	err = fn()
+	success = err == nil || errors.Is(err, context.Canceled)
+	return err
+}
+
+// allow reports whether a call may go through, and the generation of the
+// state it goes through in.
+func (b *Breaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.expire()
+	switch b.state {
+	case Open:
+		b.metrics.Add(metricRejections, 1)
+		return 0, ErrOpen
+	case HalfOpen:
+		if b.probes >= b.cfg.HalfOpenProbes {
+			b.metrics.Add(metricRejections, 1)
+			return 0, ErrOpen
+		}
+		b.probes++
+	}
+	return b.generation, nil
+}
+
+// record records the outcome of a call. Outcomes of calls made in an earlier
+// state are ignored.
+func (b *Breaker) record(generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	switch b.state {
+	case Closed:
+		if success {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.setState(Open)
+		}
+	case HalfOpen:
+		if !success {
+			b.setState(Open)
+			return
+		}
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenProbes {
+			b.setState(Closed)
+		}
+	}
+}
+
+// expire turns an open circuit half-open once its timeout has passed. The
+// caller must hold b.mu.
+func (b *Breaker) expire() {
+	if b.state == Open && !b.now().Before(b.openedAt.Add(b.cfg.OpenTimeout)) {
+		b.setState(HalfOpen)
+	}
+}
+
+// setState moves the circuit to state. The caller must hold b.mu.
+func (b *Breaker) setState(state State) {
+	from := b.state
+	b.state = state
+	b.generation++
+	b.failures = 0
+	b.probes = 0
+	b.successes = 0
+
+	switch state {
+	case Open:
+		b.openedAt = b.now()
+		b.metrics.Add(metricOpens, 1)
+		b.log.Warn("circuit breaker opened",
+			zap.String("breaker", b.name),
+			zap.String("from", from.String()),
+			zap.Duration("open_timeout", b.cfg.OpenTimeout),
+		)
+	case HalfOpen:
+		b.log.Info("circuit breaker half-open, probing", zap.String("breaker", b.name))
+	case Closed:
+		b.log.Info("circuit breaker closed", zap.String("breaker", b.name))
+	}
+}
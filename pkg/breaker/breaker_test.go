@@ -0,0 +1,141 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+var errDown = errors.New("connection refused")
+
+func fail() error    { return errDown }
+func succeed() error { return nil }
+
+// newTestBreaker creates a breaker on a clock the test moves
+func newTestBreaker(t *testing.T, name string) (*Breaker, *time.Time) {
+	b := New(name, Config{FailureThreshold: 3, OpenTimeout: 10 * time.Second, HalfOpenProbes: 2}, zaptest.NewLogger(t))
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(t, "test-opens")
+
+	// A success resets the count
+	assert.Equal(t, errDown, b.Do(fail))
+	assert.Equal(t, errDown, b.Do(fail))
+	require.NoError(t, b.Do(succeed))
+	assert.Equal(t, errDown, b.Do(fail))
+	assert.Equal(t, errDown, b.Do(fail))
+	assert.Equal(t, Closed, b.State())
+
+	assert.Equal(t, errDown, b.Do(fail))
+	assert.Equal(t, Open, b.State())
+
+	called := false
+	err := b.Do(func() error { called = true; return nil })
+	assert.ErrorIs(t, err, ErrOpen)
+	assert.False(t, called, "an open circuit skips the call")
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	b, now := newTestBreaker(t, "test-half-open")
+	for range 3 {
+		_ = b.Do(fail)
+	}
+	require.Equal(t, Open, b.State())
+
+	*now = now.Add(10 * time.Second)
+	assert.Equal(t, HalfOpen, b.State())
+
+	// A failed probe opens the circuit again
+	assert.Equal(t, errDown, b.Do(fail))
+	assert.Equal(t, Open, b.State())
+
+	*now = now.Add(10 * time.Second)
+	require.NoError(t, b.Do(succeed))
+	assert.Equal(t, HalfOpen, b.State())
+	require.NoError(t, b.Do(succeed))
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreaker_LimitsProbes(t *testing.T) {
+	b, now := newTestBreaker(t, "test-probes")
+	for range 3 {
+		_ = b.Do(fail)
+	}
+	*now = now.Add(10 * time.Second)
+
+	// Two slow probes are in flight; a third call is rejected
+	release := make(chan struct{})
+	done := make(chan error, 2)
+	started := make(chan struct{}, 2)
+	for range 2 {
+		go func() {
+			done <- b.Do(func() error {
+				started <- struct{}{}
+				<-release
+				return nil
+			})
+		}()
+	}
+	<-started
+	<-started
+	assert.ErrorIs(t, b.Do(succeed), ErrOpen)
+
+	close(release)
+	require.NoError(t, <-done)
+	require.NoError(t, <-done)
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreaker_PanicCountsAsFailure(t *testing.T) {
+	b, now := newTestBreaker(t, "test-panic")
+	for range 3 {
+		_ = b.Do(fail)
+	}
+	*now = now.Add(10 * time.Second)
+
+	// Panicking probes release their slots and open the circuit again
+	for range 2 {
+		assert.PanicsWithValue(t, "boom", func() {
+			_ = b.Do(func() error { panic("boom") })
+		})
+		assert.Equal(t, Open, b.State())
+		*now = now.Add(10 * time.Second)
+	}
+
+	require.NoError(t, b.Do(succeed))
+	require.NoError(t, b.Do(succeed))
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreaker_IgnoresCancellation(t *testing.T) {
+	b, _ := newTestBreaker(t, "test-cancel")
+
+	for range 5 {
+		_ = b.Do(func() error { return context.Canceled })
+	}
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreaker_Metrics(t *testing.T) {
+	b, _ := newTestBreaker(t, "test-metrics")
+	for range 4 {
+		_ = b.Do(fail)
+	}
+
+	m := metrics.Get("test-metrics").(*expvar.Map)
+	assert.Equal(t, `"open"`, m.Get(metricState).String())
+	assert.Equal(t, "1", m.Get(metricOpens).String())
+	assert.Equal(t, "1", m.Get(metricRejections).String())
+	assert.Equal(t, Open, States()["test-metrics"])
+	assert.Equal(t, "test-metrics", b.Name())
+}
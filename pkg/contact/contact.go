@@ -0,0 +1,100 @@
+package contact
+
+import (
+	"strings"
+	"time"
+
+	// Embed the IANA time zone database so timezone validation does not
+	// depend on the zoneinfo files installed on the host.
+	_ "time/tzdata"
+
+	"golang.org/x/text/language"
+
+	pkgerrors "grpc-user-service/pkg/errors"
+)
+
+const (
+	// MinPhoneDigits is the minimum number of digits accepted in an E.164 number (country code included)
+	MinPhoneDigits = 8
+	// MaxPhoneDigits is the maximum number of digits allowed by E.164 (country code included)
+	MaxPhoneDigits = 15
+)
+
+// NormalizePhone converts a phone number into E.164 format (e.g. "+84901234567").
+// The input must carry an international prefix, either "+" or "00". Common
+// formatting characters (spaces, dashes, dots, parentheses) are stripped.
+// An empty input is returned unchanged.
+func NormalizePhone(phone string) (string, error) {
+	phone = strings.TrimSpace(phone)
+	if phone == "" {
+		return "", nil
+	}
+
+	switch {
+	case strings.HasPrefix(phone, "+"):
+		phone = phone[1:]
+	case strings.HasPrefix(phone, "00"):
+		phone = phone[2:]
+	default:
+		return "", pkgerrors.NewValidationError("phone", "phone must include an international prefix (+ or 00)")
+	}
+
+	var digits strings.Builder
+	for _, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+			// Formatting characters are dropped
+		default:
+			return "", pkgerrors.NewValidationError("phone", "phone contains invalid characters")
+		}
+	}
+
+	normalized := digits.String()
+	if len(normalized) < MinPhoneDigits || len(normalized) > MaxPhoneDigits {
+		return "", pkgerrors.NewValidationError("phone", "phone must contain between 8 and 15 digits")
+	}
+	if normalized[0] == '0' {
+		return "", pkgerrors.NewValidationError("phone", "phone country code cannot start with 0")
+	}
+
+	return "+" + normalized, nil
+}
+
+// NormalizeLocale validates a BCP 47 language tag and returns its canonical form
+// (e.g. "en_us" becomes "en-US"). An empty input is returned unchanged.
+func NormalizeLocale(locale string) (string, error) {
+	locale = strings.TrimSpace(locale)
+	if locale == "" {
+		return "", nil
+	}
+
+	tag, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+	if err != nil {
+		return "", pkgerrors.NewValidationError("locale", "locale must be a valid BCP 47 language tag")
+	}
+
+	return tag.String(), nil
+}
+
+// ValidateTimezone checks that timezone is a known IANA time zone name
+// (e.g. "Asia/Ho_Chi_Minh"). An empty input is returned unchanged.
+func ValidateTimezone(timezone string) (string, error) {
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" {
+		return "", nil
+	}
+
+	// time.LoadLocation accepts "Local", which depends on the host and is not an IANA name
+	if timezone == "Local" {
+		return "", pkgerrors.NewValidationError("timezone", "timezone must be a valid IANA time zone")
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return "", pkgerrors.NewValidationError("timezone", "timezone must be a valid IANA time zone")
+	}
+
+	return loc.String(), nil
+}
@@ -0,0 +1,98 @@
+package contact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name        string
+		phone       string
+		expectError bool
+		expected    string
+	}{
+		{name: "empty phone", phone: "", expected: ""},
+		{name: "already E.164", phone: "+84901234567", expected: "+84901234567"},
+		{name: "formatted with spaces and dashes", phone: "+1 (415) 555-2671", expected: "+14155552671"},
+		{name: "00 international prefix", phone: "0044 20 7946 0958", expected: "+442079460958"},
+		{name: "missing international prefix", phone: "0901234567", expectError: true},
+		{name: "letters are rejected", phone: "+1415CALLNOW", expectError: true},
+		{name: "too short", phone: "+1234", expectError: true},
+		{name: "too long", phone: "+1234567890123456", expectError: true},
+		{name: "country code starting with zero", phone: "+0123456789", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NormalizePhone(tt.phone)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "phone")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		name        string
+		locale      string
+		expectError bool
+		expected    string
+	}{
+		{name: "empty locale", locale: "", expected: ""},
+		{name: "language only", locale: "vi", expected: "vi"},
+		{name: "language and region", locale: "en-US", expected: "en-US"},
+		{name: "underscore separator and casing", locale: "en_us", expected: "en-US"},
+		{name: "script subtag", locale: "zh-Hant-TW", expected: "zh-Hant-TW"},
+		{name: "malformed tag", locale: "not a locale", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NormalizeLocale(tt.locale)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "locale")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestValidateTimezone(t *testing.T) {
+	tests := []struct {
+		name        string
+		timezone    string
+		expectError bool
+		expected    string
+	}{
+		{name: "empty timezone", timezone: "", expected: ""},
+		{name: "UTC", timezone: "UTC", expected: "UTC"},
+		{name: "IANA zone", timezone: "Asia/Ho_Chi_Minh", expected: "Asia/Ho_Chi_Minh"},
+		{name: "surrounding whitespace", timezone: " Europe/Berlin ", expected: "Europe/Berlin"},
+		{name: "unknown zone", timezone: "Mars/Olympus_Mons", expectError: true},
+		{name: "host local zone", timezone: "Local", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateTimezone(tt.timezone)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "timezone")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package dataloader coalesces concurrent loads of single keys into batched
+// fetches, in the style of DataLoader: keys requested within a short window
+// are collected and fetched together, and each caller gets its own result.
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FetchFunc loads the values of a batch of distinct keys. Keys without a
+// value are left out of the result.
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// DefaultFetchTimeout bounds a batch fetch when Config.FetchTimeout is unset.
+const DefaultFetchTimeout = 5 * time.Second
+
+// Config tunes how keys are batched.
+type Config struct {
+	Wait         time.Duration // Wait is how long a batch collects keys after its first one
+	MaxBatch     int           // MaxBatch dispatches a batch as soon as it holds this many keys
+	FetchTimeout time.Duration // FetchTimeout bounds a batch fetch, DefaultFetchTimeout if zero
+}
+
+// Loader batches loads of single keys. It is safe for concurrent use.
+type Loader[K comparable, V any] struct {
+	fetch   FetchFunc[K, V]
+	cfg     Config
+	mu      sync.Mutex
+	pending *batch[K, V] // Batch still collecting keys, nil if none
+}
+
+// batch is a set of keys fetched together.
+type batch[K comparable, V any] struct {
+	ctx    context.Context
+	keys   []K
+	seen   map[K]bool
+	timer  *time.Timer
+	done   chan struct{} // Closed once values and err are set
+	values map[K]V
+	err    error
+	once   sync.Once
+}
+
+// New creates a Loader fetching batches with fetch.
+func New[K comparable, V any](fetch FetchFunc[K, V], cfg Config) *Loader[K, V] {
+	if cfg.FetchTimeout <= 0 {
+		cfg.FetchTimeout = DefaultFetchTimeout
+	}
+	return &Loader[K, V]{fetch: fetch, cfg: cfg}
+}
+
+// Load returns the value of key, and whether it has one. The fetch runs with
+// the context of the caller that opened the batch, detached from its
+// cancellation so that one caller giving up does not fail the others, and
+// bounded by the fetch timeout instead; a caller whose ctx is done returns
+// early with ctx.Err().
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, bool, error) {
+	b := l.add(ctx, key)
+
+	var zero V
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return zero, false, ctx.Err()
+	}
+	if b.err != nil {
+		return zero, false, b.err
+	}
+	v, ok := b.values[key]
+	return v, ok, nil
+}
+
+// add adds key to the pending batch, opening one if needed, and returns the
+// batch it was added to.
+func (l *Loader[K, V]) add(ctx context.Context, key K) *batch[K, V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.pending
+	if b == nil {
+		b = &batch[K, V]{
+			ctx:  context.WithoutCancel(ctx),
+			seen: make(map[K]bool),
+			done: make(chan struct{}),
+		}
+		b.timer = time.AfterFunc(l.cfg.Wait, func() { l.dispatch(b) })
+		l.pending = b
+	}
+
+	if !b.seen[key] {
+		b.seen[key] = true
+		b.keys = append(b.keys, key)
+	}
+	if l.cfg.MaxBatch > 0 && len(b.keys) >= l.cfg.MaxBatch {
+		l.pending = nil
+		b.timer.Stop()
+		go l.dispatch(b)
+	}
+	return b
+}
+
+// dispatch fetches a batch, once, and wakes its callers.
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.pending == b {
+			l.pending = nil
+		}
+		l.mu.Unlock()
+
+		// Bounded, so that a hanging fetch does not pile up batch goroutines
+		ctx, cancel := context.WithTimeout(b.ctx, l.cfg.FetchTimeout)
+		defer cancel()
+		b.values, b.err = l.fetch(ctx, b.keys)
+		close(b.done)
+	})
+}
@@ -0,0 +1,115 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingFetch squares keys, skipping negative ones, and records each batch
+type recordingFetch struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (f *recordingFetch) fetch(ctx context.Context, keys []int) (map[int]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, append([]int(nil), keys...))
+	if f.err != nil {
+		return nil, f.err
+	}
+	values := make(map[int]int, len(keys))
+	for _, k := range keys {
+		if k >= 0 {
+			values[k] = k * k
+		}
+	}
+	return values, nil
+}
+
+// loadAll loads keys concurrently and returns the values found
+func loadAll(t *testing.T, l *Loader[int, int], keys ...int) map[int]int {
+	var mu sync.Mutex
+	values := make(map[int]int)
+	var wg sync.WaitGroup
+	for _, k := range keys {
+		wg.Go(func() {
+			v, ok, err := l.Load(context.Background(), k)
+			assert.NoError(t, err)
+			if ok {
+				mu.Lock()
+				values[k] = v
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	return values
+}
+
+func TestLoader_BatchesConcurrentLoads(t *testing.T) {
+	f := &recordingFetch{}
+	l := New(f.fetch, Config{Wait: 20 * time.Millisecond, MaxBatch: 100})
+
+	values := loadAll(t, l, 1, 2, 3, 3, -1)
+
+	assert.Equal(t, map[int]int{1: 1, 2: 4, 3: 9}, values)
+	require.Len(t, f.batches, 1)
+	assert.ElementsMatch(t, []int{1, 2, 3, -1}, f.batches[0], "keys are fetched once")
+}
+
+func TestLoader_DispatchesFullBatches(t *testing.T) {
+	f := &recordingFetch{}
+	l := New(f.fetch, Config{Wait: time.Hour, MaxBatch: 2})
+
+	values := loadAll(t, l, 1, 2, 3, 4)
+
+	assert.Len(t, values, 4)
+	assert.Len(t, f.batches, 2)
+}
+
+func TestLoader_SharesErrors(t *testing.T) {
+	f := &recordingFetch{err: errors.New("db down")}
+	l := New(f.fetch, Config{Wait: time.Millisecond, MaxBatch: 10})
+
+	_, _, err := l.Load(context.Background(), 1)
+	assert.EqualError(t, err, "db down")
+}
+
+func TestLoader_CallerCancellation(t *testing.T) {
+	f := &recordingFetch{}
+	l := New(f.fetch, Config{Wait: 50 * time.Millisecond, MaxBatch: 10})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := l.Load(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The batch opened by the cancelled caller still serves the others
+	v, ok, err := l.Load(context.Background(), 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+	assert.Len(t, f.batches, 1)
+}
+
+func TestLoader_FetchTimeout(t *testing.T) {
+	fetch := func(ctx context.Context, keys []int) (map[int]int, error) {
+		// A database that hangs until the fetch gives up
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	l := New(fetch, Config{Wait: time.Millisecond, MaxBatch: 10, FetchTimeout: 20 * time.Millisecond})
+
+	start := time.Now()
+	_, _, err := l.Load(context.Background(), 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,35 @@
+// Package degraded lets lower layers report that a response is being served in
+// a degraded mode (for example from a stale cached copy while the database is
+// unavailable), so that transport layers can mark the response accordingly.
+package degraded
+
+import "context"
+
+// Header is the HTTP response header carrying the degradation reason.
+const Header = "X-Degraded"
+
+// MetadataKey is the gRPC response header metadata key carrying the
+// degradation reason.
+const MetadataKey = "x-degraded"
+
+// ReasonStaleCache marks data served from a last known good cached copy.
+const ReasonStaleCache = "stale-cache"
+
+// Reporter receives degradation reports for a request.
+type Reporter func(reason string)
+
+// reporterKey is the context key of the Reporter.
+type reporterKey struct{}
+
+// WithReporter returns a copy of ctx whose degradation reports go to r.
+func WithReporter(ctx context.Context, r Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey{}, r)
+}
+
+// Report reports that the response to the request carried by ctx is degraded.
+// It does nothing if no Reporter was installed.
+func Report(ctx context.Context, reason string) {
+	if r, ok := ctx.Value(reporterKey{}).(Reporter); ok {
+		r(reason)
+	}
+}
@@ -0,0 +1,21 @@
+package degraded
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	var reasons []string
+	ctx := WithReporter(context.Background(), func(reason string) {
+		reasons = append(reasons, reason)
+	})
+
+	Report(ctx, ReasonStaleCache)
+	assert.Equal(t, []string{ReasonStaleCache}, reasons)
+
+	// Without a reporter the report is dropped
+	assert.NotPanics(t, func() { Report(context.Background(), ReasonStaleCache) })
+}
@@ -0,0 +1,163 @@
+package ratelimit
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FailureMode is how requests are limited while Redis fails.
+type FailureMode string
+
+// Failure modes.
+const (
+	// FailOpen lets every request through, leaving the service unprotected
+	// for as long as Redis fails.
+	FailOpen FailureMode = "open"
+	// FailClosed denies every request, asking clients to retry in a second.
+	FailClosed FailureMode = "closed"
+	// FailLocal limits requests with a token bucket in process memory, at
+	// Config.LocalShare of each limit, since every replica enforces its own.
+	FailLocal FailureMode = "local"
+)
+
+// ParseFailureMode returns the failure mode called name.
+func ParseFailureMode(name string) (FailureMode, error) {
+	switch m := FailureMode(name); m {
+	case FailOpen, FailClosed, FailLocal:
+		return m, nil
+	}
+	return "", fmt.Errorf("failure mode must be one of [open, closed, local], got %q", name)
+}
+
+// failClosedRetry is the wait asked of clients denied while failing closed.
+const failClosedRetry = time.Second
+
+// metrics holds the counters of Redis failures, published through expvar
+// under "rate_limiter".
+var metrics = expvar.NewMap("rate_limiter")
+
+// Counter names in metrics.
+const (
+	metricRedisErrors   = "redis_errors"   // Requests Redis failed to limit
+	metricFailovers     = "failovers"      // Switches from Redis to the failure mode
+	metricFailedOpen    = "failed_open"    // Requests let through while failing open
+	metricFailedClosed  = "failed_closed"  // Requests denied while failing closed
+	metricLocalDecision = "local_decision" // Requests limited in memory while failing local
+)
+
+// fail limits a request that Redis failed to limit, by the failure mode. It
+// returns err when failing open, for the caller to let the request through.
+func (e *Enforcer) fail(ctx context.Context, key string, limit Limit, err error) (Result, error) {
+	if ctx.Err() != nil {
+		// The request was canceled, rather than Redis failing
+		return Result{}, err
+	}
+
+	switch e.failover(err) {
+	case FailClosed:
+		metrics.Add(metricFailedClosed, 1)
+		return Result{Limit: limit, RetryAfter: failClosedRetry}, nil
+	case FailLocal:
+		metrics.Add(metricLocalDecision, 1)
+		return e.local.Allow(ctx, key, limit.share(e.config.LocalShare))
+	default:
+		metrics.Add(metricFailedOpen, 1)
+		return Result{}, err
+	}
+}
+
+// failQuota counts a request that Redis failed to count against quotas, by
+// the failure mode: failing closed denies it, failing local counts it in
+// memory against LocalShare of each quota. It returns err when failing open,
+// for the caller to let the request through.
+func (e *Enforcer) failQuota(ctx context.Context, p Principal, quotas []quota, now time.Time, err error) (*QuotaExceeded, error) {
+	if ctx.Err() != nil {
+		// The request was canceled, rather than Redis failing
+		return nil, err
+	}
+
+	switch e.failover(err) {
+	case FailClosed:
+		metrics.Add(metricFailedClosed, 1)
+		q := quotas[0]
+		return &QuotaExceeded{
+			Principal:  p.ID,
+			Period:     q.period,
+			Limit:      q.limit,
+			ResetAt:    now.Add(failClosedRetry),
+			RetryAfter: failClosedRetry,
+			Unchecked:  true,
+		}, nil
+	case FailLocal:
+		metrics.Add(metricLocalDecision, 1)
+		shared := make([]quota, len(quotas))
+		for i, q := range quotas {
+			shared[i] = q.share(e.config.LocalShare)
+		}
+		return exceeded(p, shared, e.localQuotas.useQuota(shared), now), nil
+	default:
+		metrics.Add(metricFailedOpen, 1)
+		return nil, err
+	}
+}
+
+// failover counts a Redis failure and returns the failure mode deciding
+// instead. The first failure after Redis worked is logged as a switch to the
+// failure mode.
+func (e *Enforcer) failover(err error) FailureMode {
+	mode := e.failureMode()
+	metrics.Add(metricRedisErrors, 1)
+	if e.failing.CompareAndSwap(false, true) {
+		metrics.Add(metricFailovers, 1)
+		e.log.Warn("rate limiter lost Redis, switching to failure mode",
+			zap.String("failure_mode", string(mode)),
+			zap.Error(err),
+		)
+	}
+	return mode
+}
+
+// recovered logs the switch back to Redis after it failed.
+func (e *Enforcer) recovered() {
+	if e.failing.Load() && e.failing.CompareAndSwap(true, false) {
+		e.log.Info("rate limiter reached Redis again, leaving failure mode",
+			zap.String("failure_mode", string(e.failureMode())),
+		)
+	}
+}
+
+// failureMode returns the configured failure mode, failing open by default.
+func (e *Enforcer) failureMode() FailureMode {
+	if e.config.FailureMode == "" {
+		return FailOpen
+	}
+	return e.config.FailureMode
+}
+
+// share returns the limit scaled by share, keeping a burst of at least one
+// request. It is always a token bucket.
+func (l Limit) share(share float64) Limit {
+	if share <= 0 || share > 1 {
+		share = 1
+	}
+	return Limit{
+		RequestsPerSecond: l.RequestsPerSecond * share,
+		BurstCapacity:     max(1, int(math.Floor(float64(l.BurstCapacity)*share))),
+		Algorithm:         TokenBucket,
+	}
+}
+
+// share returns the quota scaled by share, keeping a limit of at least one
+// request.
+func (q quota) share(share float64) quota {
+	if share <= 0 || share > 1 {
+		share = 1
+	}
+	q.limit = max(1, int64(math.Floor(float64(q.limit)*share)))
+	return q
+}
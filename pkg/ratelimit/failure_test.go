@@ -0,0 +1,195 @@
+package ratelimit
+
+import (
+	"context"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metricValue returns a counter of metrics, zero before its first use
+func metricValue(name string) int64 {
+	if v, ok := metrics.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestParseFailureMode(t *testing.T) {
+	for _, name := range []string{"open", "closed", "local"} {
+		m, err := ParseFailureMode(name)
+		require.NoError(t, err)
+		assert.Equal(t, FailureMode(name), m)
+	}
+	_, err := ParseFailureMode("retry")
+	assert.Error(t, err)
+}
+
+func TestEnforcer_FailureModes(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{RequestsPerSecond: 10, BurstCapacity: 4}
+
+	t.Run("open", func(t *testing.T) {
+		e, mr, _ := setupRedisEnforcer(t, Config{FailureMode: FailOpen, Enabled: true})
+		mr.SetError("ERR injected failure")
+		before := metricValue(metricFailedOpen)
+
+		_, err := e.Allow(ctx, "key", limit)
+		assert.Error(t, err, "callers let the request through")
+		assert.Equal(t, before+1, metricValue(metricFailedOpen))
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		e, mr, _ := setupRedisEnforcer(t, Config{FailureMode: FailClosed, Enabled: true})
+		mr.SetError("ERR injected failure")
+		before := metricValue(metricFailedClosed)
+
+		res, err := e.Allow(ctx, "key", limit)
+		require.NoError(t, err)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, time.Second, res.RetryAfter)
+		assert.Equal(t, before+1, metricValue(metricFailedClosed))
+	})
+
+	t.Run("local", func(t *testing.T) {
+		e, mr, _ := setupRedisEnforcer(t, Config{FailureMode: FailLocal, LocalShare: 0.5, Enabled: true})
+		mr.SetError("ERR injected failure")
+		before := metricValue(metricLocalDecision)
+
+		// Half of the burst of 4
+		for range 2 {
+			res, err := e.Allow(ctx, "key", limit)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+		}
+		res, err := e.Allow(ctx, "key", limit)
+		require.NoError(t, err)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, Limit{RequestsPerSecond: 5, BurstCapacity: 2, Algorithm: TokenBucket}, res.Limit)
+		assert.Equal(t, before+3, metricValue(metricLocalDecision))
+	})
+}
+
+func TestEnforcer_QuotaFailureModes(t *testing.T) {
+	ctx := context.Background()
+	free := Principal{ID: "apikey:hobby", Tier: TierFree}
+
+	t.Run("open", func(t *testing.T) {
+		e, mr, _ := setupRedisEnforcer(t, quotaConfig)
+		mr.SetError("ERR injected failure")
+
+		_, err := e.UseQuota(ctx, free)
+		assert.Error(t, err, "callers let the request through")
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		config := quotaConfig
+		config.FailureMode = FailClosed
+		e, mr, now := setupRedisEnforcer(t, config)
+		mr.SetError("ERR injected failure")
+		before := metricValue(metricFailedClosed)
+
+		exceeded, err := e.UseQuota(ctx, free)
+		require.NoError(t, err)
+		require.NotNil(t, exceeded)
+		assert.True(t, exceeded.Unchecked)
+		assert.Equal(t, time.Second, exceeded.RetryAfter)
+		assert.Equal(t, now.Add(time.Second), exceeded.ResetAt)
+		assert.Contains(t, exceeded.Description(), "cannot be checked")
+		assert.Equal(t, before+1, metricValue(metricFailedClosed))
+	})
+
+	t.Run("local", func(t *testing.T) {
+		config := quotaConfig
+		config.FailureMode = FailLocal
+		config.LocalShare = 0.5
+		e, mr, _ := setupRedisEnforcer(t, config)
+		mr.SetError("ERR injected failure")
+		before := metricValue(metricLocalDecision)
+
+		// Half of the daily quota of 2
+		exceeded, err := e.UseQuota(ctx, free)
+		require.NoError(t, err)
+		assert.Nil(t, exceeded)
+		exceeded, err = e.UseQuota(ctx, free)
+		require.NoError(t, err)
+		require.NotNil(t, exceeded)
+		assert.False(t, exceeded.Unchecked)
+		assert.Equal(t, QuotaDaily, exceeded.Period)
+		assert.Equal(t, int64(1), exceeded.Limit)
+		assert.Equal(t, before+2, metricValue(metricLocalDecision))
+
+		// Redis answering again switches back to its counters
+		mr.SetError("")
+		exceeded, err = e.UseQuota(ctx, free)
+		require.NoError(t, err)
+		assert.Nil(t, exceeded)
+		assert.False(t, e.failing.Load())
+	})
+}
+
+func TestEnforcer_FailoverSwitches(t *testing.T) {
+	e, mr, _ := setupRedisEnforcer(t, Config{FailureMode: FailLocal, LocalShare: 1, Enabled: true})
+	ctx := context.Background()
+	limit := Limit{RequestsPerSecond: 10, BurstCapacity: 4}
+	before := metricValue(metricFailovers)
+
+	// Only the first failure in a row switches
+	mr.SetError("ERR injected failure")
+	for range 3 {
+		_, err := e.Allow(ctx, "key", limit)
+		require.NoError(t, err)
+	}
+	assert.True(t, e.failing.Load())
+	assert.Equal(t, before+1, metricValue(metricFailovers))
+
+	// Redis answering again switches back
+	mr.SetError("")
+	_, err := e.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.False(t, e.failing.Load())
+
+	mr.SetError("ERR injected failure")
+	_, err = e.Allow(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.Equal(t, before+2, metricValue(metricFailovers))
+}
+
+func TestEnforcer_CanceledRequestsDoNotFailOver(t *testing.T) {
+	e, _, _ := setupRedisEnforcer(t, Config{FailureMode: FailClosed, Enabled: true})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := e.Allow(ctx, "key", Limit{RequestsPerSecond: 10, BurstCapacity: 4})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, e.failing.Load())
+}
+
+func TestEnforcer_CanceledQuotaRequestsDoNotFailOver(t *testing.T) {
+	config := quotaConfig
+	config.FailureMode = FailClosed
+	e, _, _ := setupRedisEnforcer(t, config)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := e.UseQuota(ctx, Principal{ID: "apikey:hobby", Tier: TierFree})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, e.failing.Load())
+}
+
+func TestLimit_Share(t *testing.T) {
+	limit := Limit{RequestsPerSecond: 10, BurstCapacity: 3, Algorithm: GCRA}
+	assert.Equal(t, Limit{RequestsPerSecond: 2.5, BurstCapacity: 1, Algorithm: TokenBucket}, limit.share(0.25))
+	assert.Equal(t, Limit{RequestsPerSecond: 10, BurstCapacity: 3, Algorithm: TokenBucket}, limit.share(0),
+		"shares out of range keep the whole limit")
+}
+
+func TestQuota_Share(t *testing.T) {
+	q := quota{period: QuotaDaily, limit: 10}
+	assert.Equal(t, int64(2), q.share(0.25).limit)
+	assert.Equal(t, int64(1), quota{limit: 1}.share(0.5).limit, "shares keep a limit of one request")
+	assert.Equal(t, int64(10), q.share(2).limit, "shares out of range keep the whole quota")
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcra admits a request by the generic cell rate algorithm atomically, and
+// returns whether it did, the requests left and, when denied, the
+// milliseconds until one is allowed. It stores the theoretical arrival time
+// (TAT) of the client: when it would have used no burst at all, if it had
+// sent its requests an interval apart. A request is allowed unless it would
+// push the TAT more than the burst tolerance ahead of now. The key expires at
+// the TAT, since a missing TAT reads as now.
+var gcra = redis.NewScript(`
+	local key = KEYS[1]
+	local interval = tonumber(ARGV[1])     -- milliseconds between requests at the rate
+	local capacity = tonumber(ARGV[2])     -- requests allowed at once
+	local now = tonumber(ARGV[3])          -- current timestamp in milliseconds
+
+	-- A TAT in the past is now, so that idle time does not add to the burst.
+	-- A clock behind the one that wrote it reads the stored TAT, so that the
+	-- TAT never moves back.
+	local tat = math.max(tonumber(redis.call('GET', key)) or now, now)
+	local tolerance = interval * capacity
+	local new_tat = tat + interval
+	local allow_at = new_tat - tolerance
+
+	if now < allow_at then
+		return {0, 0, math.max(1, math.ceil(allow_at - now))}
+	end
+
+	redis.call('SET', key, new_tat, 'PX', math.max(1, math.ceil(new_tat - now)))
+	return {1, math.floor((now + tolerance - new_tat) / interval), 0}
+`)
+
+// redisGCRA keeps theoretical arrival times in Redis.
+type redisGCRA struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+// Allow admits a request of the client called key, spacing requests
+// 1/RequestsPerSecond apart with bursts of up to BurstCapacity.
+func (g *redisGCRA) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	return runScript(ctx, g.client, gcra, GCRAKeyPrefix+key, limit,
+		limit.intervalMillis(),
+		limit.BurstCapacity,
+		g.now().UnixMilli(),
+	)
+}
+
+// memoryGCRA keeps theoretical arrival times, in milliseconds, in process
+// memory.
+type memoryGCRA struct {
+	states *memoryStates[float64]
+}
+
+// newMemoryGCRA creates an empty set of in-memory theoretical arrival times.
+func newMemoryGCRA(now func() time.Time) memoryGCRA {
+	return memoryGCRA{states: newMemoryStates[float64](now)}
+}
+
+// Allow admits a request of the client called key, like the gcra script.
+func (g memoryGCRA) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	return g.states.update(GCRAKeyPrefix+key, func(tat *float64, now time.Time) (Result, time.Duration) {
+		interval := limit.intervalMillis()
+		nowMillis := float64(now.UnixMilli())
+
+		current := max(*tat, nowMillis)
+		tolerance := interval * float64(limit.BurstCapacity)
+		newTAT := current + interval
+		allowAt := newTAT - tolerance
+
+		if nowMillis < allowAt {
+			return Result{Limit: limit, RetryAfter: retryMillis(allowAt - nowMillis)}, retryMillis(current - nowMillis)
+		}
+		*tat = newTAT
+		return Result{
+			Allowed:   true,
+			Limit:     limit,
+			Remaining: int(math.Floor((nowMillis + tolerance - newTAT) / interval)),
+		}, retryMillis(newTAT - nowMillis)
+	}), nil
+}
+
+// intervalMillis returns the emission interval of GCRA, the time between
+// requests at the rate.
+func (l Limit) intervalMillis() float64 {
+	if l.RequestsPerSecond <= 0 {
+		return float64(bucketIdleTTL.Milliseconds())
+	}
+	return 1000 / l.RequestsPerSecond
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGCRA_Boundary(t *testing.T) {
+	// A request every half second, two at once
+	limit := Limit{RequestsPerSecond: 2, BurstCapacity: 2, Algorithm: GCRA}
+	forEachBackend(t, GCRA, func(t *testing.T, l Limiter, now *time.Time) {
+		res := assertAllowed(t, l, "key", limit, true)
+		assert.Equal(t, 1, res.Remaining)
+		res = assertAllowed(t, l, "key", limit, true)
+		assert.Equal(t, 0, res.Remaining)
+		res = assertAllowed(t, l, "key", limit, false)
+		assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+		*now = testStart.Add(499 * time.Millisecond)
+		res = assertAllowed(t, l, "key", limit, false)
+		assert.Equal(t, time.Millisecond, res.RetryAfter)
+
+		*now = testStart.Add(500 * time.Millisecond)
+		res = assertAllowed(t, l, "key", limit, true)
+		assert.Equal(t, 0, res.Remaining)
+		assertAllowed(t, l, "key", limit, false)
+
+		// Idle time does not add to the burst
+		*now = testStart.Add(time.Hour)
+		res = assertAllowed(t, l, "key", limit, true)
+		assert.Equal(t, 1, res.Remaining)
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, false)
+	})
+}
+
+func TestGCRA_ClockSkew(t *testing.T) {
+	limit := Limit{RequestsPerSecond: 2, BurstCapacity: 2, Algorithm: GCRA}
+	forEachBackend(t, GCRA, func(t *testing.T, l Limiter, now *time.Time) {
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, true)
+
+		// A replica with its clock 10 seconds behind waits for the stored
+		// arrival time
+		*now = testStart.Add(-10 * time.Second)
+		res := assertAllowed(t, l, "key", limit, false)
+		assert.Equal(t, 10500*time.Millisecond, res.RetryAfter)
+
+		// and does not move it
+		*now = testStart.Add(500 * time.Millisecond)
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, false)
+	})
+}
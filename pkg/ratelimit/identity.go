@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+)
+
+// Tier is the service level of an identified client. Each tier has its own
+// limits and quotas.
+type Tier string
+
+// Tiers of identified clients.
+const (
+	TierFree     Tier = "free"
+	TierInternal Tier = "internal"
+	TierPartner  Tier = "partner"
+)
+
+// ParseTier returns the tier called name.
+func ParseTier(name string) (Tier, error) {
+	switch t := Tier(name); t {
+	case TierFree, TierInternal, TierPartner:
+		return t, nil
+	}
+	return "", fmt.Errorf("tier must be one of [free, internal, partner], got %q", name)
+}
+
+// Principal is an identified client. Requests of a principal are limited by
+// its ID instead of the client IP, with the limits of its tier.
+type Principal struct {
+	ID   string
+	Tier Tier
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the principal authenticated for a
+// request. It takes precedence over an API key.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal set by WithPrincipal.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok && p.ID != ""
+}
+
+// APIKey is a secret identifying a client, with the tier it belongs to.
+type APIKey struct {
+	ID     string
+	Tier   Tier
+	Secret string
+}
+
+// APIKeys finds the principal presenting an API key. The secrets are kept
+// as SHA-256 hashes only. A nil APIKeys knows no keys.
+type APIKeys struct {
+	principals map[[sha256.Size]byte]Principal
+}
+
+// NewAPIKeys creates the set of keys. Principals are named "apikey:{id}", so
+// that the secrets never appear in Redis keys or logs.
+func NewAPIKeys(keys []APIKey) *APIKeys {
+	principals := make(map[[sha256.Size]byte]Principal, len(keys))
+	for _, k := range keys {
+		principals[sha256.Sum256([]byte(k.Secret))] = Principal{ID: "apikey:" + k.ID, Tier: k.Tier}
+	}
+	return &APIKeys{principals: principals}
+}
+
+// Lookup returns the principal of secret.
+func (k *APIKeys) Lookup(secret string) (Principal, bool) {
+	if k == nil || secret == "" {
+		return Principal{}, false
+	}
+	// Hashing first keeps the lookup time independent of the secret's prefix
+	p, ok := k.principals[sha256.Sum256([]byte(secret))]
+	return p, ok
+}
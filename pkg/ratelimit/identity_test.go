@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestParseTier(t *testing.T) {
+	tier, err := ParseTier("partner")
+	require.NoError(t, err)
+	assert.Equal(t, TierPartner, tier)
+
+	_, err = ParseTier("gold")
+	assert.Error(t, err)
+}
+
+func TestEnforcer_Identify(t *testing.T) {
+	l := New(nil, Config{
+		APIKeys: NewAPIKeys([]APIKey{{ID: "acme", Tier: TierPartner, Secret: "s3cret"}}),
+	}, zaptest.NewLogger(t))
+	ctx := context.Background()
+
+	p, ok := l.Identify(ctx, "s3cret")
+	require.True(t, ok)
+	assert.Equal(t, Principal{ID: "apikey:acme", Tier: TierPartner}, p)
+
+	_, ok = l.Identify(ctx, "wrong")
+	assert.False(t, ok)
+	_, ok = l.Identify(ctx, "")
+	assert.False(t, ok)
+
+	// An authenticated principal takes precedence over the API key
+	ctx = WithPrincipal(ctx, Principal{ID: "user:42", Tier: TierInternal})
+	p, ok = l.Identify(ctx, "s3cret")
+	require.True(t, ok)
+	assert.Equal(t, "user:42", p.ID)
+}
+
+func TestConfig_PrincipalLimit(t *testing.T) {
+	config := Config{
+		RequestsPerSecond: 10,
+		BurstCapacity:     20,
+		Policies: Policies{Tiers: map[Tier]TierLimits{
+			TierPartner:  {RequestsPerSecond: 50, BurstCapacity: 100},
+			TierFree:     {DailyQuota: 1000},
+			TierInternal: {Algorithm: GCRA},
+		}},
+	}
+
+	assert.Equal(t, Limit{RequestsPerSecond: 50, BurstCapacity: 100}, config.PrincipalLimit(Principal{ID: "apikey:acme", Tier: TierPartner}))
+	assert.Equal(t, Limit{RequestsPerSecond: 10, BurstCapacity: 20}, config.PrincipalLimit(Principal{ID: "apikey:hobby", Tier: TierFree}),
+		"tiers without a rate get the global limit")
+	assert.Equal(t, Limit{RequestsPerSecond: 10, BurstCapacity: 20}, config.PrincipalLimit(Principal{}))
+	assert.Equal(t, Limit{RequestsPerSecond: 10, BurstCapacity: 20, Algorithm: GCRA}, config.PrincipalLimit(Principal{ID: "apikey:ops", Tier: TierInternal}),
+		"tiers may change the algorithm alone")
+}
+
+func TestConfig_GRPCLimit_TiersUnderRules(t *testing.T) {
+	policies, err := LoadPolicies(filepath.Join("..", "..", "deployments", "ratelimit-policy.yaml"))
+	require.NoError(t, err)
+	config := Config{RequestsPerSecond: 10, BurstCapacity: 20, Policies: policies}
+
+	anonymous := Principal{}
+	internal := Principal{ID: "user:42", Tier: TierInternal}
+	partner := Principal{ID: "apikey:acme", Tier: TierPartner}
+
+	// The catch-all rule holds anonymous clients, while tiers keep their own limits
+	assert.Equal(t, Limit{RequestsPerSecond: 20, BurstCapacity: 40}, config.GRPCLimit("/user.UserService/GetUser", anonymous))
+	assert.Equal(t, Limit{RequestsPerSecond: 200, BurstCapacity: 400}, config.GRPCLimit("/user.UserService/GetUser", internal))
+	assert.Equal(t, Limit{RequestsPerSecond: 50, BurstCapacity: 100}, config.GRPCLimit("/user.UserService/GetUser", partner))
+	assert.Equal(t, Limit{RequestsPerSecond: 200, BurstCapacity: 400}, config.HTTPLimit("GET /v1/users/:id", internal))
+
+	// Specific rules scale by tier or apply to every tier not listed
+	assert.Equal(t, Limit{RequestsPerSecond: 50, BurstCapacity: 100}, config.GRPCLimit("/user.UserService/ListUsers", internal))
+	assert.Equal(t, Limit{RequestsPerSecond: 20, BurstCapacity: 40, Algorithm: GCRA}, config.GRPCLimit("/user.UserService/DeleteUser", internal))
+	assert.Equal(t, Limit{RequestsPerSecond: 1, BurstCapacity: 2, Algorithm: GCRA}, config.GRPCLimit("/user.UserService/DeleteUser", partner))
+}
@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Algorithm names a rate limiting algorithm.
+type Algorithm string
+
+// Rate limiting algorithms. Each reads a Limit in its own way, so that the
+// same rate and burst capacity give comparable limits.
+const (
+	// TokenBucket refills a bucket of BurstCapacity tokens at
+	// RequestsPerSecond, and takes a token per request.
+	TokenBucket Algorithm = "token_bucket"
+	// SlidingWindow allows BurstCapacity requests per window of
+	// BurstCapacity/RequestsPerSecond seconds. It counts the requests of the
+	// current and the previous fixed window, weighting the previous one by
+	// how much of it the sliding window still covers.
+	SlidingWindow Algorithm = "sliding_window"
+	// GCRA, the generic cell rate algorithm, spaces requests 1/RequestsPerSecond
+	// apart, tolerating bursts of up to BurstCapacity. It behaves like a token
+	// bucket but stores a single timestamp.
+	GCRA Algorithm = "gcra"
+)
+
+// ParseAlgorithm returns the algorithm called name.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch a := Algorithm(name); a {
+	case TokenBucket, SlidingWindow, GCRA:
+		return a, nil
+	}
+	return "", fmt.Errorf("algorithm must be one of [token_bucket, sliding_window, gcra], got %q", name)
+}
+
+// Limiter decides whether a request may proceed under a limit, keeping the
+// state of each key in Redis or in memory. Implementations are safe for
+// concurrent use.
+type Limiter interface {
+	// Allow counts a request against the state called key and reports
+	// whether limit allows it. Callers decide how to handle an error from
+	// Redis.
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Result is the outcome of a request under a limit.
+type Result struct {
+	Allowed    bool
+	Limit      Limit
+	Remaining  int           // Requests that would still be allowed right away
+	RetryAfter time.Duration // Time until a request is allowed again, when denied
+}
+
+// newLimiter creates the Limiter of algorithm a, in Redis or, with a nil
+// client, in memory.
+func newLimiter(a Algorithm, client *redis.Client, now func() time.Time) Limiter {
+	switch a {
+	case SlidingWindow:
+		if client == nil {
+			return newMemorySlidingWindow(now)
+		}
+		return &redisSlidingWindow{client: client, now: now}
+	case GCRA:
+		if client == nil {
+			return newMemoryGCRA(now)
+		}
+		return &redisGCRA{client: client, now: now}
+	default:
+		if client == nil {
+			buckets := NewMemoryBuckets()
+			buckets.now = now
+			return memoryTokenBucket{buckets}
+		}
+		return &redisTokenBucket{client: client, now: now}
+	}
+}
+
+// NewTokenBucket creates a token bucket Limiter in Redis or, with a nil
+// client, in memory.
+func NewTokenBucket(client *redis.Client) Limiter {
+	return newLimiter(TokenBucket, client, time.Now)
+}
+
+// NewSlidingWindow creates a sliding window Limiter in Redis or, with a nil
+// client, in memory.
+func NewSlidingWindow(client *redis.Client) Limiter {
+	return newLimiter(SlidingWindow, client, time.Now)
+}
+
+// NewGCRA creates a GCRA Limiter in Redis or, with a nil client, in memory.
+func NewGCRA(client *redis.Client) Limiter {
+	return newLimiter(GCRA, client, time.Now)
+}
+
+// runScript runs a limiter script, by EVALSHA, loading it on first use or
+// after a Redis restart. Every script returns whether it allowed the request,
+// the requests remaining and the milliseconds to wait when denied.
+func runScript(ctx context.Context, client *redis.Client, script *redis.Script, key string, limit Limit, args ...any) (Result, error) {
+	reply, err := script.Run(ctx, client, []string{key}, args...).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(reply) != 3 {
+		return Result{}, fmt.Errorf("unexpected rate limiter reply %v", reply)
+	}
+	return Result{
+		Allowed:    reply[0] == 1,
+		Limit:      limit,
+		Remaining:  int(reply[1]),
+		RetryAfter: time.Duration(reply[2]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStart is the time limiter tests start at, on a whole second so that
+// fixed windows start with the test
+var testStart = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+// forEachBackend runs test against the Limiter of algorithm on miniredis and
+// in memory, each with a settable clock starting at testStart
+func forEachBackend(t *testing.T, algorithm Algorithm, test func(t *testing.T, l Limiter, now *time.Time)) {
+	t.Run("redis", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() {
+			_ = client.Close()
+		})
+
+		now := testStart
+		test(t, newLimiter(algorithm, client, func() time.Time { return now }), &now)
+	})
+	t.Run("memory", func(t *testing.T) {
+		now := testStart
+		test(t, newLimiter(algorithm, nil, func() time.Time { return now }), &now)
+	})
+}
+
+// assertAllowed takes a request through l and checks whether it was allowed
+func assertAllowed(t *testing.T, l Limiter, key string, limit Limit, allowed bool) Result {
+	t.Helper()
+	res, err := l.Allow(context.Background(), key, limit)
+	require.NoError(t, err)
+	assert.Equal(t, allowed, res.Allowed)
+	if allowed {
+		assert.Zero(t, res.RetryAfter)
+	} else {
+		assert.Zero(t, res.Remaining)
+		assert.Positive(t, res.RetryAfter)
+	}
+	return res
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	for _, name := range []string{"token_bucket", "sliding_window", "gcra"} {
+		a, err := ParseAlgorithm(name)
+		require.NoError(t, err)
+		assert.Equal(t, Algorithm(name), a)
+	}
+	_, err := ParseAlgorithm("leaky_bucket")
+	assert.Error(t, err)
+}
+
+func TestEnforcer_Algorithms(t *testing.T) {
+	e, mr, _ := setupRedisEnforcer(t, Config{
+		RequestsPerSecond: 1,
+		BurstCapacity:     1,
+		Algorithm:         SlidingWindow,
+		Enabled:           true,
+		Policies: Policies{GRPC: &Policy{rules: []Rule{
+			{Match: "/user.UserService/DeleteUser", RequestsPerSecond: 1, BurstCapacity: 1, Algorithm: GCRA},
+		}}},
+	})
+	ctx := context.Background()
+
+	_, err := e.Allow(ctx, "get", e.Config().GRPCLimit("/user.UserService/GetUser", Principal{}))
+	require.NoError(t, err)
+	_, err = e.Allow(ctx, "delete", e.Config().GRPCLimit("/user.UserService/DeleteUser", Principal{}))
+	require.NoError(t, err)
+	_, err = e.Allow(ctx, "default", Limit{RequestsPerSecond: 1, BurstCapacity: 1})
+	require.NoError(t, err)
+
+	assert.True(t, mr.Exists(SlidingWindowKeyPrefix+"get"))
+	assert.True(t, mr.Exists(GCRAKeyPrefix+"delete"))
+	assert.True(t, mr.Exists(KeyPrefix+"default"), "limits without an algorithm use a token bucket")
+
+	_, err = e.Allow(ctx, "key", Limit{RequestsPerSecond: 1, BurstCapacity: 1, Algorithm: "leaky_bucket"})
+	assert.Error(t, err)
+}
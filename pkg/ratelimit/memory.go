@@ -0,0 +1,194 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// bucketIdleTTL is how long state is kept for limits without a refill rate,
+// and how often expired in-memory state is swept.
+const bucketIdleTTL = 60 * time.Second
+
+// memoryBucket is the state of one token bucket.
+type memoryBucket struct {
+	tokens     float64
+	lastRefill time.Time
+	expiresAt  time.Time // When the bucket is full again, like its Redis key
+}
+
+// memoryCounter is the state of one quota counter.
+type memoryCounter struct {
+	used    int64
+	resetAt time.Time
+}
+
+// MemoryBuckets holds token buckets and quota counters in process memory,
+// for rate limiting without Redis. Limits are then enforced per replica
+// instead of across the service. It is safe for concurrent use.
+type MemoryBuckets struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	counters  map[string]*memoryCounter
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryBuckets creates an empty set of in-memory token buckets.
+func NewMemoryBuckets() *MemoryBuckets {
+	return &MemoryBuckets{
+		buckets:  make(map[string]*memoryBucket),
+		counters: make(map[string]*memoryCounter),
+		now:      time.Now,
+	}
+}
+
+// Allow takes a token from the bucket called key, refilled at rate tokens
+// per second up to capacity, and reports whether there was one.
+func (b *MemoryBuckets) Allow(key string, rate float64, capacity int) bool {
+	return b.Take(key, Limit{RequestsPerSecond: rate, BurstCapacity: capacity}).Allowed
+}
+
+// Take takes a token from the bucket called key, refilled according to
+// limit, and returns the state of the bucket.
+func (b *MemoryBuckets) Take(key string, limit Limit) Result {
+	rate, capacity := limit.RequestsPerSecond, limit.BurstCapacity
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.sweep(now)
+
+	bucket, ok := b.buckets[key]
+	if !ok || !now.Before(bucket.expiresAt) {
+		bucket = &memoryBucket{tokens: float64(capacity), lastRefill: now}
+		b.buckets[key] = bucket
+	}
+	if expiresAt := now.Add(time.Duration(limit.refillMillis()) * time.Millisecond); expiresAt.After(bucket.expiresAt) {
+		// A bucket is dropped only once it would have refilled, so that it
+		// does not come back full early
+		bucket.expiresAt = expiresAt
+	}
+
+	elapsed := max(0, now.Sub(bucket.lastRefill).Seconds())
+	bucket.tokens = min(float64(capacity), bucket.tokens+elapsed*rate)
+	if now.After(bucket.lastRefill) {
+		// Like in Redis, a clock moving back does not count refill twice
+		bucket.lastRefill = now
+	}
+
+	if bucket.tokens < 1 {
+		return Result{
+			Limit:      limit,
+			Remaining:  0,
+			RetryAfter: time.Duration(math.Ceil((1 - bucket.tokens) / rate * float64(time.Second))),
+		}
+	}
+	bucket.tokens--
+	return Result{Allowed: true, Limit: limit, Remaining: int(bucket.tokens)}
+}
+
+// useQuota counts a request against quotas, unless one of them is used up.
+// It returns the index of the first used up quota, or -1.
+func (b *MemoryBuckets) useQuota(quotas []quota) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sweep(b.now())
+
+	for i, q := range quotas {
+		if c, ok := b.counters[q.key]; ok && c.used >= q.limit {
+			return i
+		}
+	}
+	for _, q := range quotas {
+		c, ok := b.counters[q.key]
+		if !ok {
+			c = &memoryCounter{resetAt: q.resetAt}
+			b.counters[q.key] = c
+		}
+		c.used++
+	}
+	return -1
+}
+
+// sweep drops expired buckets and counters of past periods, at most once per
+// bucketIdleTTL. The caller must hold b.mu.
+func (b *MemoryBuckets) sweep(now time.Time) {
+	if now.Sub(b.lastSweep) < bucketIdleTTL {
+		return
+	}
+
+	for key, bucket := range b.buckets {
+		if !now.Before(bucket.expiresAt) {
+			delete(b.buckets, key)
+		}
+	}
+	for key, counter := range b.counters {
+		if !now.Before(counter.resetAt) {
+			delete(b.counters, key)
+		}
+	}
+	b.lastSweep = now
+}
+
+// memoryStates holds the state of each key of an in-memory limiter, like
+// the keys of its Redis script, until it expires. It is safe for concurrent
+// use.
+type memoryStates[T any] struct {
+	mu        sync.Mutex
+	states    map[string]*memoryState[T]
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// memoryState is the state of one key and its expiry.
+type memoryState[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+// newMemoryStates creates an empty set of states, read with the clock now.
+func newMemoryStates[T any](now func() time.Time) *memoryStates[T] {
+	return &memoryStates[T]{
+		states: make(map[string]*memoryState[T]),
+		now:    now,
+	}
+}
+
+// update calls fn with the state of key, the zero T when it is missing or
+// expired, and keeps the state for the time fn returns along with its result.
+func (m *memoryStates[T]) update(key string, fn func(state *T, now time.Time) (Result, time.Duration)) Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	state, ok := m.states[key]
+	if !ok || !now.Before(state.expiresAt) {
+		state = &memoryState[T]{}
+		m.states[key] = state
+	}
+	res, ttl := fn(&state.value, now)
+	if expiresAt := now.Add(ttl); expiresAt.After(state.expiresAt) {
+		// A clock moving back does not expire the state early
+		state.expiresAt = expiresAt
+	}
+	return res
+}
+
+// sweep drops expired states, at most once per bucketIdleTTL. The caller
+// must hold m.mu.
+func (m *memoryStates[T]) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < bucketIdleTTL {
+		return
+	}
+
+	for key, state := range m.states {
+		if !now.Before(state.expiresAt) {
+			delete(m.states, key)
+		}
+	}
+	m.lastSweep = now
+}
@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBuckets_Refill(t *testing.T) {
+	b := NewMemoryBuckets()
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	assert.True(t, b.Allow("key", 2, 2))
+	assert.True(t, b.Allow("key", 2, 2))
+	assert.False(t, b.Allow("key", 2, 2))
+	assert.True(t, b.Allow("other", 2, 2), "buckets are independent")
+
+	// Half a second refills one token at 2 per second
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, b.Allow("key", 2, 2))
+	assert.False(t, b.Allow("key", 2, 2))
+
+	// Refill stops at capacity
+	now = now.Add(time.Hour)
+	assert.True(t, b.Allow("key", 2, 2))
+	assert.True(t, b.Allow("key", 2, 2))
+	assert.False(t, b.Allow("key", 2, 2))
+}
+
+func TestMemoryBuckets_DropsIdleBuckets(t *testing.T) {
+	b := NewMemoryBuckets()
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	b.Allow("idle", 1, 1)
+	now = now.Add(bucketIdleTTL)
+	b.Allow("active", 1, 1)
+
+	assert.NotContains(t, b.buckets, "idle")
+	assert.Contains(t, b.buckets, "active")
+}
+
+func TestMemoryBuckets_KeepsSlowRefillBucketsAcrossSweep(t *testing.T) {
+	b := NewMemoryBuckets()
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	// 10 requests per 100 seconds take 100 seconds to refill
+	limit := Limit{RequestsPerSecond: 0.1, BurstCapacity: 10}
+	for range 10 {
+		assert.True(t, b.Take("slow", limit).Allowed)
+	}
+	assert.False(t, b.Take("slow", limit).Allowed)
+
+	// A sweep past the idle TTL keeps the bucket, which refilled 6 tokens
+	now = now.Add(bucketIdleTTL)
+	b.Allow("active", 1, 1)
+	assert.Contains(t, b.buckets, "slow")
+	for range 6 {
+		assert.True(t, b.Take("slow", limit).Allowed)
+	}
+	assert.False(t, b.Take("slow", limit).Allowed)
+
+	// Once it would have refilled, it is dropped
+	now = now.Add(2 * bucketIdleTTL)
+	b.Allow("active", 1, 1)
+	assert.NotContains(t, b.buckets, "slow")
+}
@@ -0,0 +1,216 @@
+package ratelimit
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Limit is the rate and burst capacity allowed to a client, and the
+// algorithm enforcing them. An empty Algorithm is a token bucket.
+type Limit struct {
+	RequestsPerSecond float64
+	BurstCapacity     int
+	Algorithm         Algorithm
+}
+
+// Rule gives the requests whose name matches Match their own limit. In
+// Match, * stands for any run of characters, including none. The limit applies
+// to anonymous clients and to identified clients of every tier without an
+// entry in Tiers.
+type Rule struct {
+	Match             string                `mapstructure:"match"`               // gRPC full method or "METHOD /route/template"
+	RequestsPerSecond float64               `mapstructure:"requests_per_second"` // Token refill rate (tokens per second)
+	BurstCapacity     int                   `mapstructure:"burst_capacity"`      // Maximum tokens in bucket
+	Algorithm         Algorithm             `mapstructure:"algorithm"`           // Algorithm enforcing the limit; empty keeps the default
+	Tiers             map[Tier]TierOverride `mapstructure:"tiers"`               // Limits of the rule for identified clients by tier
+}
+
+// TierOverride is the limit of a rule for the identified clients of one tier.
+// Without a rate the tier is exempt from the rule and gets its own limits.
+type TierOverride struct {
+	RequestsPerSecond float64   `mapstructure:"requests_per_second"` // Token refill rate (tokens per second)
+	BurstCapacity     int       `mapstructure:"burst_capacity"`      // Maximum tokens in bucket
+	Algorithm         Algorithm `mapstructure:"algorithm"`           // Algorithm enforcing the limit; empty keeps that of the rule
+}
+
+// TierLimits are the limits of the identified clients of one tier. Without a
+// rate the tier gets the global limit; a zero quota is unlimited.
+type TierLimits struct {
+	RequestsPerSecond float64   `mapstructure:"requests_per_second"` // Token refill rate of each bucket of a client
+	BurstCapacity     int       `mapstructure:"burst_capacity"`      // Maximum tokens in each bucket of a client
+	Algorithm         Algorithm `mapstructure:"algorithm"`           // Algorithm enforcing the limits of the tier; empty keeps the default
+	DailyQuota        int64     `mapstructure:"daily_quota"`         // Requests per client per UTC day
+	MonthlyQuota      int64     `mapstructure:"monthly_quota"`       // Requests per client per UTC month
+}
+
+// PolicyFile is the content of a rate limit policy file.
+type PolicyFile struct {
+	GRPC  []Rule                `mapstructure:"grpc"`  // Rules by gRPC full method name
+	HTTP  []Rule                `mapstructure:"http"`  // Rules by HTTP method and Gin route template
+	Tiers map[string]TierLimits `mapstructure:"tiers"` // Limits by tier of identified clients
+}
+
+// Policy picks the limit of a request by name. The most specific matching
+// rule applies: an exact match first, then the pattern with the most
+// characters besides *, then the earlier rule. A nil Policy has no rules.
+type Policy struct {
+	rules []Rule
+}
+
+// NewPolicy validates rules and creates a Policy from them.
+func NewPolicy(rules []Rule) (*Policy, error) {
+	for i, r := range rules {
+		if r.Match == "" {
+			return nil, fmt.Errorf("rule %d: match is required", i)
+		}
+		if r.RequestsPerSecond <= 0 {
+			return nil, fmt.Errorf("rule %q: requests_per_second must be positive, got %f", r.Match, r.RequestsPerSecond)
+		}
+		if r.BurstCapacity <= 0 {
+			return nil, fmt.Errorf("rule %q: burst_capacity must be positive, got %d", r.Match, r.BurstCapacity)
+		}
+		if r.Algorithm != "" {
+			if _, err := ParseAlgorithm(string(r.Algorithm)); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", r.Match, err)
+			}
+		}
+		for tier, o := range r.Tiers {
+			if _, err := ParseTier(string(tier)); err != nil {
+				return nil, fmt.Errorf("rule %q: %w", r.Match, err)
+			}
+			if o.RequestsPerSecond < 0 || o.BurstCapacity < 0 || (o.RequestsPerSecond > 0) != (o.BurstCapacity > 0) {
+				return nil, fmt.Errorf("rule %q: tier %q: requests_per_second and burst_capacity must both be positive or both unset", r.Match, tier)
+			}
+			if o.Algorithm != "" {
+				if _, err := ParseAlgorithm(string(o.Algorithm)); err != nil {
+					return nil, fmt.Errorf("rule %q: tier %q: %w", r.Match, tier, err)
+				}
+			}
+		}
+	}
+	return &Policy{rules: rules}, nil
+}
+
+// Lookup returns the limit of the rule matching name for clients of tier,
+// which is empty for anonymous clients, or fallback when no rule matches. A
+// rule without an algorithm keeps the one of fallback. Fallback is also the
+// limit of a tier the matching rule exempts.
+func (p *Policy) Lookup(name string, tier Tier, fallback Limit) Limit {
+	if p == nil {
+		return fallback
+	}
+
+	best, bestScore := -1, -1
+	for i, r := range p.rules {
+		if !match(r.Match, name) {
+			continue
+		}
+		score := len(r.Match) - strings.Count(r.Match, "*")
+		if r.Match == name {
+			// Exact matches outrank every pattern
+			score = len(name) + 1
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return fallback
+	}
+	rule := p.rules[best]
+	if o, ok := rule.Tiers[tier]; ok {
+		if o.RequestsPerSecond == 0 {
+			fallback.Algorithm = cmp.Or(o.Algorithm, fallback.Algorithm)
+			return fallback
+		}
+		return Limit{
+			RequestsPerSecond: o.RequestsPerSecond,
+			BurstCapacity:     o.BurstCapacity,
+			Algorithm:         cmp.Or(o.Algorithm, rule.Algorithm, fallback.Algorithm),
+		}
+	}
+	return Limit{
+		RequestsPerSecond: rule.RequestsPerSecond,
+		BurstCapacity:     rule.BurstCapacity,
+		Algorithm:         cmp.Or(rule.Algorithm, fallback.Algorithm),
+	}
+}
+
+// match reports whether name matches pattern, where * matches any run of
+// characters.
+func match(pattern, name string) bool {
+	literal, rest, wildcard := strings.Cut(pattern, "*")
+	if !wildcard {
+		return pattern == name
+	}
+	if !strings.HasPrefix(name, literal) {
+		return false
+	}
+	name = name[len(literal):]
+	for i := 0; i <= len(name); i++ {
+		if match(rest, name[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Policies holds the policies of both transports and the limits of tiers.
+type Policies struct {
+	GRPC  *Policy
+	HTTP  *Policy
+	Tiers map[Tier]TierLimits
+}
+
+// LoadPolicies reads a policy file, in any format viper reads (YAML, JSON,
+// TOML), and creates the policies in it. HTTP rules must match "METHOD
+// /route", where either part may use *.
+func LoadPolicies(path string) (Policies, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return Policies{}, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var file PolicyFile
+	if err := v.Unmarshal(&file); err != nil {
+		return Policies{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for _, r := range file.HTTP {
+		if r.Match != "*" && !strings.Contains(r.Match, " ") {
+			return Policies{}, fmt.Errorf("http rule %q: match must be \"METHOD /route\"", r.Match)
+		}
+	}
+
+	grpcPolicy, err := NewPolicy(file.GRPC)
+	if err != nil {
+		return Policies{}, fmt.Errorf("grpc %w", err)
+	}
+	httpPolicy, err := NewPolicy(file.HTTP)
+	if err != nil {
+		return Policies{}, fmt.Errorf("http %w", err)
+	}
+	tiers := make(map[Tier]TierLimits, len(file.Tiers))
+	for name, limits := range file.Tiers {
+		tier, err := ParseTier(name)
+		if err != nil {
+			return Policies{}, fmt.Errorf("tiers: %w", err)
+		}
+		if limits.RequestsPerSecond < 0 || limits.BurstCapacity < 0 || (limits.RequestsPerSecond > 0) != (limits.BurstCapacity > 0) {
+			return Policies{}, fmt.Errorf("tier %q: requests_per_second and burst_capacity must both be positive or both unset", name)
+		}
+		if limits.DailyQuota < 0 || limits.MonthlyQuota < 0 {
+			return Policies{}, fmt.Errorf("tier %q: quotas must not be negative", name)
+		}
+		if limits.Algorithm != "" {
+			if _, err := ParseAlgorithm(string(limits.Algorithm)); err != nil {
+				return Policies{}, fmt.Errorf("tier %q: %w", name, err)
+			}
+		}
+		tiers[tier] = limits
+	}
+	return Policies{GRPC: grpcPolicy, HTTP: httpPolicy, Tiers: tiers}, nil
+}
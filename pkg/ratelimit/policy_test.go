@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Lookup(t *testing.T) {
+	p, err := NewPolicy([]Rule{
+		{Match: "*", RequestsPerSecond: 50, BurstCapacity: 100},
+		{Match: "GET /v1/users/*", RequestsPerSecond: 20, BurstCapacity: 40},
+		{Match: "GET /v1/users", RequestsPerSecond: 5, BurstCapacity: 10},
+		{Match: "* /v1/users/:id", RequestsPerSecond: 2, BurstCapacity: 4},
+		{Match: "DELETE /v1/users/:id", RequestsPerSecond: 1, BurstCapacity: 2, Algorithm: GCRA},
+	})
+	require.NoError(t, err)
+	// Rules without an algorithm keep that of the fallback
+	fallback := Limit{RequestsPerSecond: 10, BurstCapacity: 20, Algorithm: SlidingWindow}
+
+	tests := []struct {
+		name string
+		want Limit
+	}{
+		{"DELETE /v1/users/:id", Limit{RequestsPerSecond: 1, BurstCapacity: 2, Algorithm: GCRA}},
+		{"PUT /v1/users/:id", Limit{RequestsPerSecond: 2, BurstCapacity: 4, Algorithm: SlidingWindow}},
+		{"GET /v1/users", Limit{RequestsPerSecond: 5, BurstCapacity: 10, Algorithm: SlidingWindow}},
+		{"GET /v1/users/:id/emails", Limit{RequestsPerSecond: 20, BurstCapacity: 40, Algorithm: SlidingWindow}},
+		{"POST /admin/cache/flush", Limit{RequestsPerSecond: 50, BurstCapacity: 100, Algorithm: SlidingWindow}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, p.Lookup(tt.name, "", fallback))
+		})
+	}
+
+	var nilPolicy *Policy
+	assert.Equal(t, fallback, nilPolicy.Lookup("GET /v1/users", "", fallback))
+}
+
+func TestPolicy_ExactMatchWins(t *testing.T) {
+	p, err := NewPolicy([]Rule{
+		{Match: "/user.UserService/*User*", RequestsPerSecond: 20, BurstCapacity: 40},
+		{Match: "/user.UserService/GetUser", RequestsPerSecond: 5, BurstCapacity: 10},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, Limit{RequestsPerSecond: 5, BurstCapacity: 10}, p.Lookup("/user.UserService/GetUser", "", Limit{}))
+	assert.Equal(t, Limit{RequestsPerSecond: 20, BurstCapacity: 40}, p.Lookup("/user.UserService/ListUsers", "", Limit{}))
+	assert.Equal(t, Limit{}, p.Lookup("/user.UserService/AddEmail", "", Limit{}))
+}
+
+func TestPolicy_Lookup_TierOverrides(t *testing.T) {
+	p, err := NewPolicy([]Rule{
+		{Match: "/user.UserService/*", RequestsPerSecond: 20, BurstCapacity: 40, Tiers: map[Tier]TierOverride{
+			TierInternal: {},
+			TierPartner:  {Algorithm: GCRA},
+		}},
+		{Match: "/user.UserService/ListUsers", RequestsPerSecond: 5, BurstCapacity: 10, Algorithm: SlidingWindow, Tiers: map[Tier]TierOverride{
+			TierInternal: {RequestsPerSecond: 50, BurstCapacity: 100},
+		}},
+	})
+	require.NoError(t, err)
+	fallback := Limit{RequestsPerSecond: 200, BurstCapacity: 400}
+
+	// An empty override exempts the tier from the rule
+	assert.Equal(t, fallback, p.Lookup("/user.UserService/GetUser", TierInternal, fallback))
+	assert.Equal(t, Limit{RequestsPerSecond: 200, BurstCapacity: 400, Algorithm: GCRA}, p.Lookup("/user.UserService/GetUser", TierPartner, fallback))
+	assert.Equal(t, Limit{RequestsPerSecond: 20, BurstCapacity: 40}, p.Lookup("/user.UserService/GetUser", TierFree, fallback))
+	assert.Equal(t, Limit{RequestsPerSecond: 20, BurstCapacity: 40}, p.Lookup("/user.UserService/GetUser", "", fallback))
+
+	// An override with a rate replaces the rule's limit and keeps its algorithm
+	assert.Equal(t, Limit{RequestsPerSecond: 50, BurstCapacity: 100, Algorithm: SlidingWindow}, p.Lookup("/user.UserService/ListUsers", TierInternal, fallback))
+	assert.Equal(t, Limit{RequestsPerSecond: 5, BurstCapacity: 10, Algorithm: SlidingWindow}, p.Lookup("/user.UserService/ListUsers", TierPartner, fallback))
+}
+
+func TestNewPolicy_Invalid(t *testing.T) {
+	_, err := NewPolicy([]Rule{{Match: "", RequestsPerSecond: 1, BurstCapacity: 1}})
+	assert.Error(t, err)
+	_, err = NewPolicy([]Rule{{Match: "*", RequestsPerSecond: 0, BurstCapacity: 1}})
+	assert.Error(t, err)
+	_, err = NewPolicy([]Rule{{Match: "*", RequestsPerSecond: 1, BurstCapacity: 0}})
+	assert.Error(t, err)
+	_, err = NewPolicy([]Rule{{Match: "*", RequestsPerSecond: 1, BurstCapacity: 1, Algorithm: "leaky_bucket"}})
+	assert.ErrorContains(t, err, "algorithm")
+	_, err = NewPolicy([]Rule{{Match: "*", RequestsPerSecond: 1, BurstCapacity: 1, Tiers: map[Tier]TierOverride{"gold": {}}}})
+	assert.ErrorContains(t, err, "tier must be one of")
+	_, err = NewPolicy([]Rule{{Match: "*", RequestsPerSecond: 1, BurstCapacity: 1, Tiers: map[Tier]TierOverride{TierPartner: {RequestsPerSecond: 5}}}})
+	assert.ErrorContains(t, err, "both be positive")
+}
+
+func TestLoadPolicies(t *testing.T) {
+	policies, err := LoadPolicies(filepath.Join("..", "..", "deployments", "ratelimit-policy.yaml"))
+	require.NoError(t, err)
+
+	fallback := Limit{RequestsPerSecond: 10, BurstCapacity: 20}
+	assert.Equal(t, Limit{RequestsPerSecond: 1, BurstCapacity: 2, Algorithm: GCRA}, policies.GRPC.Lookup("/user.UserService/DeleteUser", "", fallback))
+	assert.Equal(t, Limit{RequestsPerSecond: 20, BurstCapacity: 40}, policies.GRPC.Lookup("/user.UserService/GetUser", "", fallback))
+	assert.Equal(t, Limit{RequestsPerSecond: 1, BurstCapacity: 2, Algorithm: GCRA}, policies.HTTP.Lookup("DELETE /v1/users/:id", "", fallback))
+	assert.Equal(t, Limit{RequestsPerSecond: 1, BurstCapacity: 5, Algorithm: SlidingWindow}, policies.HTTP.Lookup("POST /admin/cache/flush", "", fallback))
+	assert.Equal(t, fallback, policies.HTTP.Lookup("POST /v1/users", "", fallback))
+	assert.Equal(t, TierLimits{RequestsPerSecond: 50, BurstCapacity: 100, DailyQuota: 1000000}, policies.Tiers[TierPartner])
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("http:\n  - match: /v1/users\n    requests_per_second: 1\n    burst_capacity: 1\n"), 0o600))
+	_, err = LoadPolicies(path)
+	assert.ErrorContains(t, err, "METHOD /route")
+
+	require.NoError(t, os.WriteFile(path, []byte("tiers:\n  gold:\n    daily_quota: 10\n"), 0o600))
+	_, err = LoadPolicies(path)
+	assert.ErrorContains(t, err, "tier must be one of")
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// QuotaKeyPrefix starts the Redis key of every quota counter.
+const QuotaKeyPrefix = "ratelimit:quota:"
+
+// quotaGrace keeps counters past the end of their period, so that replicas
+// with a slightly late clock still find them.
+const quotaGrace = time.Hour
+
+// Quota periods.
+const (
+	QuotaDaily   = "daily"
+	QuotaMonthly = "monthly"
+)
+
+// quota is a counter of the requests of a principal in one period.
+type quota struct {
+	period  string
+	key     string
+	limit   int64
+	resetAt time.Time
+}
+
+// QuotaExceeded describes the quota a principal has used up.
+type QuotaExceeded struct {
+	Principal  string
+	Period     string // QuotaDaily or QuotaMonthly
+	Limit      int64
+	ResetAt    time.Time
+	RetryAfter time.Duration // Time until ResetAt
+	Unchecked  bool          // Denied because Redis failed while failing closed, rather than used up
+}
+
+// Description returns a sentence describing the exhausted quota.
+func (q *QuotaExceeded) Description() string {
+	if q.Unchecked {
+		return fmt.Sprintf("%s quota of %d requests cannot be checked, retry in %s",
+			q.Period, q.Limit, q.RetryAfter)
+	}
+	return fmt.Sprintf("%s quota of %d requests exhausted, resets at %s",
+		q.Period, q.Limit, q.ResetAt.Format(time.RFC3339))
+}
+
+// useQuota counts a request against quota counters, unless one of them is
+// used up. It returns the 1-based index of the first used up counter, or 0.
+var useQuota = redis.NewScript(`
+	-- ARGV holds the limit and expiry (unix seconds) of each counter in KEYS
+	for i, key in ipairs(KEYS) do
+		local used = tonumber(redis.call('GET', key) or '0')
+		if used >= tonumber(ARGV[2 * i - 1]) then
+			return i
+		end
+	end
+
+	for i, key in ipairs(KEYS) do
+		redis.call('INCR', key)
+		redis.call('EXPIREAT', key, ARGV[2 * i])
+	end
+	return 0
+`)
+
+// UseQuota counts a request of p against the daily and monthly quotas of its
+// tier. When a quota is used up it returns that quota and does not count the
+// request. When Redis fails, the failure mode decides instead, like for Allow;
+// failing closed, the request is denied with an Unchecked quota, and failing
+// open, UseQuota returns the error and callers let the request through.
+func (e *Enforcer) UseQuota(ctx context.Context, p Principal) (*QuotaExceeded, error) {
+	now := e.now()
+	quotas := e.quotas(p, now)
+	if len(quotas) == 0 {
+		return nil, nil
+	}
+
+	var exhausted int
+	if e.buckets != nil {
+		exhausted = e.buckets.useQuota(quotas)
+	} else {
+		keys := make([]string, len(quotas))
+		args := make([]any, 0, 2*len(quotas))
+		for i, q := range quotas {
+			keys[i] = q.key
+			args = append(args, q.limit, q.resetAt.Add(quotaGrace).Unix())
+		}
+		index, err := useQuota.Run(ctx, e.client, keys, args...).Int()
+		if err != nil {
+			return e.failQuota(ctx, p, quotas, now, err)
+		}
+		e.recovered()
+		exhausted = index - 1
+	}
+	return exceeded(p, quotas, exhausted, now), nil
+}
+
+// exceeded returns the quota at index exhausted, or nil for a negative index.
+func exceeded(p Principal, quotas []quota, exhausted int, now time.Time) *QuotaExceeded {
+	if exhausted < 0 {
+		return nil
+	}
+	q := quotas[exhausted]
+	return &QuotaExceeded{
+		Principal:  p.ID,
+		Period:     q.period,
+		Limit:      q.limit,
+		ResetAt:    q.resetAt,
+		RetryAfter: q.resetAt.Sub(now),
+	}
+}
+
+// quotas returns the quota counters of p at now, in UTC periods.
+func (e *Enforcer) quotas(p Principal, now time.Time) []quota {
+	if p.ID == "" {
+		return nil
+	}
+	limits := e.config.Policies.Tiers[p.Tier]
+
+	now = now.UTC()
+	var quotas []quota
+	if limits.DailyQuota > 0 {
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		quotas = append(quotas, quota{
+			period:  QuotaDaily,
+			key:     QuotaKeyPrefix + p.ID + ":day:" + day.Format("2006-01-02"),
+			limit:   limits.DailyQuota,
+			resetAt: day.AddDate(0, 0, 1),
+		})
+	}
+	if limits.MonthlyQuota > 0 {
+		month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		quotas = append(quotas, quota{
+			period:  QuotaMonthly,
+			key:     QuotaKeyPrefix + p.ID + ":month:" + month.Format("2006-01"),
+			limit:   limits.MonthlyQuota,
+			resetAt: month.AddDate(0, 1, 0),
+		})
+	}
+	return quotas
+}
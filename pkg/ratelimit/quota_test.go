@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// quotaConfig gives free tier clients a daily quota of 2 and a monthly quota of 3
+var quotaConfig = Config{
+	RequestsPerSecond: 10,
+	BurstCapacity:     10,
+	Enabled:           true,
+	Policies: Policies{Tiers: map[Tier]TierLimits{
+		TierFree: {DailyQuota: 2, MonthlyQuota: 3},
+	}},
+}
+
+// useQuotaN uses the quota of p n times and returns the last result
+func useQuotaN(t *testing.T, l *Enforcer, p Principal, n int) *QuotaExceeded {
+	var exceeded *QuotaExceeded
+	for range n {
+		var err error
+		exceeded, err = l.UseQuota(context.Background(), p)
+		require.NoError(t, err)
+	}
+	return exceeded
+}
+
+func testQuotas(t *testing.T, l *Enforcer, now *time.Time) {
+	free := Principal{ID: "apikey:hobby", Tier: TierFree}
+	*now = time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, useQuotaN(t, l, free, 2))
+	exceeded := useQuotaN(t, l, free, 1)
+	require.NotNil(t, exceeded)
+	assert.Equal(t, QuotaExceeded{
+		Principal:  "apikey:hobby",
+		Period:     QuotaDaily,
+		Limit:      2,
+		ResetAt:    time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		RetryAfter: time.Hour,
+	}, *exceeded)
+
+	// The next day the daily quota is fresh, but the monthly one runs out
+	*now = now.Add(2 * time.Hour)
+	assert.Nil(t, useQuotaN(t, l, free, 1))
+	exceeded = useQuotaN(t, l, free, 1)
+	require.NotNil(t, exceeded)
+	assert.Equal(t, QuotaMonthly, exceeded.Period)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), exceeded.ResetAt)
+
+	// Denied requests are not counted, and other clients have their own quota
+	assert.Nil(t, useQuotaN(t, l, Principal{ID: "apikey:other", Tier: TierFree}, 2))
+
+	// Tiers without quotas and anonymous clients are unlimited
+	assert.Nil(t, useQuotaN(t, l, Principal{ID: "apikey:acme", Tier: TierPartner}, 5))
+	assert.Nil(t, useQuotaN(t, l, Principal{}, 5))
+}
+
+func TestEnforcer_Quotas(t *testing.T) {
+	l, mr, now := setupRedisEnforcer(t, quotaConfig)
+	testQuotas(t, l, now)
+
+	// Counters expire after their period, with some grace
+	ttl := mr.TTL(QuotaKeyPrefix + "apikey:other:day:2026-10-19")
+	assert.Greater(t, ttl, time.Duration(0))
+}
+
+func TestEnforcer_QuotasWithoutRedis(t *testing.T) {
+	l := New(nil, quotaConfig, zaptest.NewLogger(t))
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	testQuotas(t, l, &now)
+}
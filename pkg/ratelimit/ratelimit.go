@@ -0,0 +1,162 @@
+// Package ratelimit is the rate limiter shared by the gRPC and Gin
+// transports, with token bucket, sliding window and GCRA algorithms. Their
+// state lives in Redis, so that limits hold across replicas, or in process
+// memory when the service runs without Redis.
+package ratelimit
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Prefixes of the Redis keys holding the state of each algorithm.
+const (
+	KeyPrefix              = "ratelimit:tb:"   // Token buckets
+	SlidingWindowKeyPrefix = "ratelimit:sw:"   // Sliding window counters
+	GCRAKeyPrefix          = "ratelimit:gcra:" // GCRA theoretical arrival times
+)
+
+// Headers, and gRPC metadata keys in lower case, telling clients about their
+// limits.
+const (
+	HeaderLimit      = "X-RateLimit-Limit"     // Burst capacity of the bucket
+	HeaderRemaining  = "X-RateLimit-Remaining" // Whole tokens left in the bucket
+	HeaderRetryAfter = "Retry-After"           // Seconds to wait before retrying a denied request
+)
+
+// RetryAfterSeconds rounds d up to whole seconds, as sent in Retry-After.
+func RetryAfterSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// Config holds configuration for the rate limiter.
+type Config struct {
+	RequestsPerSecond float64     // Token refill rate (tokens per second)
+	BurstCapacity     int         // Maximum tokens in bucket (allows burst traffic)
+	Algorithm         Algorithm   // Algorithm of limits not naming one; empty is a token bucket
+	FailureMode       FailureMode // How requests are limited while Redis fails; empty fails open
+	LocalShare        float64     // Share of each limit enforced per replica while failing local, in (0, 1]
+	Enabled           bool
+	Policies          Policies // Limits of particular methods, routes and tiers; others get the limit above
+	APIKeys           *APIKeys // Keys identifying clients, limited by key instead of IP
+}
+
+// Limit returns the limit applied where no policy rule matches.
+func (c Config) Limit() Limit {
+	return Limit{RequestsPerSecond: c.RequestsPerSecond, BurstCapacity: c.BurstCapacity, Algorithm: c.Algorithm}
+}
+
+// PrincipalLimit returns the limit applied to p where no policy rule
+// matches: that of its tier, or the global limit for anonymous clients and
+// tiers without a rate. A tier may change the algorithm alone.
+func (c Config) PrincipalLimit(p Principal) Limit {
+	limit := c.Limit()
+	if p.ID == "" {
+		return limit
+	}
+	t := c.Policies.Tiers[p.Tier]
+	if t.RequestsPerSecond > 0 {
+		limit.RequestsPerSecond, limit.BurstCapacity = t.RequestsPerSecond, t.BurstCapacity
+	}
+	limit.Algorithm = cmp.Or(t.Algorithm, limit.Algorithm)
+	return limit
+}
+
+// GRPCLimit returns the limit of a gRPC method, by full method name, for p,
+// which is the zero Principal for anonymous clients.
+func (c Config) GRPCLimit(fullMethod string, p Principal) Limit {
+	return c.Policies.GRPC.Lookup(fullMethod, p.Tier, c.PrincipalLimit(p))
+}
+
+// HTTPLimit returns the limit of an HTTP route, named "METHOD /route/template",
+// for p, which is the zero Principal for anonymous clients.
+func (c Config) HTTPLimit(route string, p Principal) Limit {
+	return c.Policies.HTTP.Lookup(route, p.Tier, c.PrincipalLimit(p))
+}
+
+// Enforcer applies the configured limits, taking each request through the
+// Limiter of the algorithm of its limit, and counts quotas. It is safe for
+// concurrent use.
+type Enforcer struct {
+	client      *redis.Client
+	buckets     *MemoryBuckets
+	limiters    map[Algorithm]Limiter
+	local       Limiter        // In-memory token buckets used while failing local
+	localQuotas *MemoryBuckets // In-memory quota counters used while failing local
+	config      Config
+	log         *zap.Logger
+	now         func() time.Time
+	failing     atomic.Bool // Whether the last request to Redis failed
+}
+
+// New creates an Enforcer keeping its state in Redis, and limiting requests
+// by config.FailureMode while Redis fails. With a nil client the state is
+// kept in memory, and limits apply per replica.
+func New(client *redis.Client, config Config, log *zap.Logger) *Enforcer {
+	e := &Enforcer{
+		client: client,
+		config: config,
+		log:    log,
+		now:    time.Now,
+	}
+	clock := func() time.Time { return e.now() }
+	if client == nil {
+		e.buckets = NewMemoryBuckets()
+		e.buckets.now = clock
+	} else {
+		e.local = newLimiter(TokenBucket, nil, clock)
+		e.localQuotas = NewMemoryBuckets()
+		e.localQuotas.now = clock
+	}
+	e.limiters = map[Algorithm]Limiter{
+		TokenBucket:   newLimiter(TokenBucket, client, clock),
+		SlidingWindow: newLimiter(SlidingWindow, client, clock),
+		GCRA:          newLimiter(GCRA, client, clock),
+	}
+	return e
+}
+
+// Enabled reports whether requests should be limited at all.
+func (e *Enforcer) Enabled() bool {
+	return e != nil && e.config.Enabled
+}
+
+// Config returns the limits applied.
+func (e *Enforcer) Config() Config {
+	return e.config
+}
+
+// Identify returns the principal of a request: the one in ctx, set by an
+// authentication layer, or else the owner of apiKey. Requests of neither are
+// anonymous, and limited by client IP.
+func (e *Enforcer) Identify(ctx context.Context, apiKey string) (Principal, bool) {
+	if p, ok := PrincipalFrom(ctx); ok {
+		return p, true
+	}
+	return e.config.APIKeys.Lookup(apiKey)
+}
+
+// Allow counts a request against the state called key with the algorithm of
+// limit, a token bucket by default, and reports whether limit allows it.
+// When Redis fails, the failure mode decides instead; failing open, Allow
+// returns the error and callers let the request through.
+func (e *Enforcer) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	algorithm := cmp.Or(limit.Algorithm, TokenBucket)
+	limiter, ok := e.limiters[algorithm]
+	if !ok {
+		return Result{}, fmt.Errorf("unknown rate limit algorithm %q", algorithm)
+	}
+	res, err := limiter.Allow(ctx, key, limit)
+	if err != nil {
+		return e.fail(ctx, key, limit, err)
+	}
+	e.recovered()
+	return res, nil
+}
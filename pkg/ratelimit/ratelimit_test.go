@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// setupRedisEnforcer creates an Enforcer on miniredis with a settable clock
+func setupRedisEnforcer(t *testing.T, config Config) (*Enforcer, *miniredis.Miniredis, *time.Time) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	l := New(client, config, zaptest.NewLogger(t))
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	return l, mr, &now
+}
+
+func TestEnforcer_WithoutRedis(t *testing.T) {
+	l := New(nil, Config{RequestsPerSecond: 1, BurstCapacity: 1, Enabled: true}, zaptest.NewLogger(t))
+
+	res, err := l.Allow(context.Background(), "key", l.Config().Limit())
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	res, err = l.Allow(context.Background(), "key", l.Config().Limit())
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.InDelta(t, time.Second, res.RetryAfter, float64(10*time.Millisecond))
+}
+
+func TestEnforcer_Enabled(t *testing.T) {
+	var nilEnforcer *Enforcer
+	assert.False(t, nilEnforcer.Enabled())
+	assert.False(t, New(nil, Config{}, zaptest.NewLogger(t)).Enabled())
+	assert.True(t, New(nil, Config{Enabled: true}, zaptest.NewLogger(t)).Enabled())
+}
@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindow counts a request in a sliding window atomically, and returns
+// whether it did, the requests left and, when denied, the milliseconds until
+// one is allowed. It keeps counters of the current and the previous fixed
+// window, and approximates the sliding window by weighting the previous
+// counter with the share of it the sliding window still covers. The counters
+// expire once neither would count, since missing counters read as zero.
+var slidingWindow = redis.NewScript(`
+	local key = KEYS[1]
+	local capacity = tonumber(ARGV[1])     -- requests per window
+	local window = tonumber(ARGV[2])       -- window length in milliseconds
+	local now = tonumber(ARGV[3])          -- current timestamp in milliseconds
+
+	-- Get current counters
+	local state = redis.call('HMGET', key, 'window', 'current', 'previous')
+	local index = math.floor(now / window)
+	local stored = tonumber(state[1]) or index
+	local current = tonumber(state[2]) or 0
+	local previous = tonumber(state[3]) or 0
+
+	-- Roll the counters over to the window of now. A clock behind the one
+	-- that wrote them counts in the stored window, never resetting them.
+	if index < stored then
+		index = stored
+	elseif index == stored + 1 then
+		previous = current
+		current = 0
+	elseif index > stored + 1 then
+		previous = 0
+		current = 0
+	end
+
+	-- Weight the previous window by the share of it still in the sliding window
+	local elapsed = math.min(window, math.max(0, now - index * window))
+	local count = previous * (window - elapsed) / window + current
+
+	local allowed = 0
+	local remaining = 0
+	local retry_after = 0
+	if count + 1 <= capacity then
+		current = current + 1
+		allowed = 1
+		remaining = math.floor(capacity - count - 1)
+	elseif current + 1 <= capacity then
+		-- Wait for enough of the previous window to slide out
+		retry_after = math.max(1, math.ceil(window * (1 - (capacity - 1 - current) / previous) - elapsed))
+	else
+		-- Wait for the next window, where the current one weighs less
+		retry_after = math.max(1, math.ceil(window - elapsed + window * (1 - (capacity - 1) / current)))
+	end
+
+	redis.call('HSET', key, 'window', index, 'current', current, 'previous', previous)
+	redis.call('PEXPIRE', key, 2 * window)
+	return {allowed, remaining, retry_after}
+`)
+
+// redisSlidingWindow keeps sliding window counters in Redis.
+type redisSlidingWindow struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+// Allow counts a request in the window called key, allowing BurstCapacity
+// requests per window of BurstCapacity/RequestsPerSecond seconds.
+func (w *redisSlidingWindow) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	return runScript(ctx, w.client, slidingWindow, SlidingWindowKeyPrefix+key, limit,
+		limit.BurstCapacity,
+		limit.windowMillis(),
+		w.now().UnixMilli(),
+	)
+}
+
+// slidingWindowState is the counters of one sliding window: the index of the
+// current fixed window, and the requests counted in it and in the previous
+// one.
+type slidingWindowState struct {
+	window   int64
+	current  int64
+	previous int64
+}
+
+// memorySlidingWindow keeps sliding window counters in process memory.
+type memorySlidingWindow struct {
+	states *memoryStates[slidingWindowState]
+}
+
+// newMemorySlidingWindow creates an empty set of in-memory sliding windows.
+func newMemorySlidingWindow(now func() time.Time) memorySlidingWindow {
+	return memorySlidingWindow{states: newMemoryStates[slidingWindowState](now)}
+}
+
+// Allow counts a request in the window called key, like the slidingWindow
+// script.
+func (w memorySlidingWindow) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	return w.states.update(SlidingWindowKeyPrefix+key, func(s *slidingWindowState, now time.Time) (Result, time.Duration) {
+		capacity := float64(limit.BurstCapacity)
+		windowMillis := limit.windowMillis()
+		window := float64(windowMillis)
+		nowMillis := float64(now.UnixMilli())
+
+		index := int64(math.Floor(nowMillis / window))
+		switch {
+		case s.window == 0:
+			// A new window
+		case index < s.window:
+			index = s.window
+		case index == s.window+1:
+			s.previous, s.current = s.current, 0
+		case index > s.window+1:
+			s.previous, s.current = 0, 0
+		}
+		s.window = index
+
+		elapsed := min(window, max(0, nowMillis-float64(index)*window))
+		count := float64(s.previous)*(window-elapsed)/window + float64(s.current)
+		previous, current := float64(s.previous), float64(s.current)
+
+		res := Result{Limit: limit}
+		switch {
+		case count+1 <= capacity:
+			s.current++
+			res.Allowed = true
+			res.Remaining = int(math.Floor(capacity - count - 1))
+		case current+1 <= capacity:
+			res.RetryAfter = retryMillis(window*(1-(capacity-1-current)/previous) - elapsed)
+		default:
+			res.RetryAfter = retryMillis(window - elapsed + window*(1-(capacity-1)/current))
+		}
+		return res, 2 * time.Duration(windowMillis) * time.Millisecond
+	}), nil
+}
+
+// windowMillis returns the length of a sliding window, in which the burst
+// capacity refills at the rate.
+func (l Limit) windowMillis() int64 {
+	if l.RequestsPerSecond <= 0 {
+		return bucketIdleTTL.Milliseconds()
+	}
+	return max(1, int64(math.Ceil(float64(l.BurstCapacity)/l.RequestsPerSecond*1000)))
+}
+
+// retryMillis rounds a wait in milliseconds up, to at least a millisecond,
+// like the scripts do.
+func retryMillis(ms float64) time.Duration {
+	return time.Duration(max(1, math.Ceil(ms))) * time.Millisecond
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlidingWindow_Boundary(t *testing.T) {
+	// Two requests per window of a second
+	limit := Limit{RequestsPerSecond: 2, BurstCapacity: 2, Algorithm: SlidingWindow}
+	forEachBackend(t, SlidingWindow, func(t *testing.T, l Limiter, now *time.Time) {
+		res := assertAllowed(t, l, "key", limit, true)
+		assert.Equal(t, 1, res.Remaining)
+		res = assertAllowed(t, l, "key", limit, true)
+		assert.Equal(t, 0, res.Remaining)
+
+		// The full window weighs on the next one until half of it slid out
+		res = assertAllowed(t, l, "key", limit, false)
+		assert.Equal(t, 1500*time.Millisecond, res.RetryAfter)
+
+		*now = testStart.Add(999 * time.Millisecond)
+		assertAllowed(t, l, "key", limit, false)
+
+		*now = testStart.Add(time.Second)
+		res = assertAllowed(t, l, "key", limit, false)
+		assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+		*now = testStart.Add(1499 * time.Millisecond)
+		res = assertAllowed(t, l, "key", limit, false)
+		assert.Equal(t, time.Millisecond, res.RetryAfter)
+
+		*now = testStart.Add(1500 * time.Millisecond)
+		res = assertAllowed(t, l, "key", limit, true)
+		assert.Equal(t, 0, res.Remaining)
+		res = assertAllowed(t, l, "key", limit, false)
+		assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+		// Counters of windows long past are gone
+		*now = testStart.Add(time.Hour)
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, false)
+	})
+}
+
+func TestSlidingWindow_ClockSkew(t *testing.T) {
+	limit := Limit{RequestsPerSecond: 2, BurstCapacity: 2, Algorithm: SlidingWindow}
+	forEachBackend(t, SlidingWindow, func(t *testing.T, l Limiter, now *time.Time) {
+		*now = testStart.Add(500 * time.Millisecond)
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, true)
+
+		// A replica with its clock 5 seconds behind counts in the stored
+		// window, rather than starting over in its own
+		*now = testStart.Add(-5 * time.Second)
+		assertAllowed(t, l, "key", limit, false)
+
+		// and leaves the counters as they were
+		*now = testStart.Add(900 * time.Millisecond)
+		assertAllowed(t, l, "key", limit, false)
+		*now = testStart.Add(2 * time.Second)
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, false)
+	})
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucket takes a token from a bucket atomically, and returns whether it
+// did, the whole tokens left and, when denied, the milliseconds until the
+// next token. Timestamps are in milliseconds, so that tokens refill smoothly
+// rather than once per second. The bucket expires once it would be full
+// again, since a missing bucket reads as full.
+var tokenBucket = redis.NewScript(`
+	local key = KEYS[1]
+	local rate = tonumber(ARGV[1])         -- tokens per second
+	local capacity = tonumber(ARGV[2])     -- max tokens in bucket
+	local now = tonumber(ARGV[3])          -- current timestamp in milliseconds
+	local requested = tonumber(ARGV[4])    -- tokens requested (always 1)
+	local ttl = tonumber(ARGV[5])          -- milliseconds to refill an empty bucket
+
+	-- Get current bucket state
+	local bucket = redis.call('HMGET', key, 'last_refill', 'tokens')
+	local last_refill = tonumber(bucket[1]) or now
+	local tokens = tonumber(bucket[2]) or capacity
+
+	-- Add the tokens refilled since the last request
+	local elapsed = math.max(0, now - last_refill)
+	tokens = math.min(capacity, tokens + elapsed * rate / 1000)
+
+	local allowed = 0
+	local retry_after = 0
+	if tokens >= requested then
+		tokens = tokens - requested
+		allowed = 1
+	else
+		retry_after = math.ceil((requested - tokens) * 1000 / rate)
+	end
+
+	-- Update last_refill even when denied, so that refill is not counted twice.
+	-- It never moves back, so that a replica whose clock is behind cannot make
+	-- the next request see the refill of the time in between twice.
+	redis.call('HSET', key, 'last_refill', math.max(last_refill, now), 'tokens', tokens)
+	redis.call('PEXPIRE', key, ttl)
+	return {allowed, math.floor(tokens), retry_after}
+`)
+
+// redisTokenBucket keeps token buckets in Redis.
+type redisTokenBucket struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+// Allow takes a token from the bucket called key, refilled according to
+// limit, and reports whether there was one along with the state of the
+// bucket.
+func (b *redisTokenBucket) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	return runScript(ctx, b.client, tokenBucket, KeyPrefix+key, limit,
+		limit.RequestsPerSecond,
+		limit.BurstCapacity,
+		b.now().UnixMilli(),
+		1, // Always request 1 token
+		limit.refillMillis(),
+	)
+}
+
+// memoryTokenBucket keeps token buckets in process memory.
+type memoryTokenBucket struct {
+	buckets *MemoryBuckets
+}
+
+// Allow takes a token from the bucket called key, refilled according to
+// limit, and reports whether there was one along with the state of the
+// bucket.
+func (b memoryTokenBucket) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	return b.buckets.Take(KeyPrefix+key, limit), nil
+}
+
+// refillMillis returns the time to refill an empty bucket, at least a second.
+func (l Limit) refillMillis() int64 {
+	if l.RequestsPerSecond <= 0 {
+		return bucketIdleTTL.Milliseconds()
+	}
+	return max(1000, int64(math.Ceil(float64(l.BurstCapacity)/l.RequestsPerSecond*1000)))
+}
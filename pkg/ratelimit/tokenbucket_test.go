@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_RefillsWithinSecond(t *testing.T) {
+	l, _, now := setupRedisEnforcer(t, Config{RequestsPerSecond: 10, BurstCapacity: 2, Enabled: true})
+	ctx := context.Background()
+
+	for i := range 2 {
+		res, err := l.Allow(ctx, "key", l.Config().Limit())
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 1-i, res.Remaining)
+		assert.Zero(t, res.RetryAfter)
+	}
+	res, err := l.Allow(ctx, "key", l.Config().Limit())
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 100*time.Millisecond, res.RetryAfter)
+
+	// Part of the way, the wait shrinks
+	*now = now.Add(40 * time.Millisecond)
+	res, err = l.Allow(ctx, "key", l.Config().Limit())
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 60*time.Millisecond, res.RetryAfter)
+
+	// A tenth of a second refills one token at 10 per second
+	*now = now.Add(100 * time.Millisecond)
+	res, err = l.Allow(ctx, "key", l.Config().Limit())
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = l.Allow(ctx, "key", l.Config().Limit())
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+}
+
+func TestTokenBucket_LoadsScriptOnce(t *testing.T) {
+	l, mr, _ := setupRedisEnforcer(t, Config{RequestsPerSecond: 1, BurstCapacity: 5, Enabled: true})
+	ctx := context.Background()
+
+	_, err := l.Allow(ctx, "key", l.Config().Limit())
+	require.NoError(t, err)
+	exists, err := l.client.ScriptExists(ctx, tokenBucket.Hash()).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true}, exists)
+
+	// The script is loaded again after Redis loses it
+	mr.FlushAll()
+	require.NoError(t, l.client.ScriptFlush(ctx).Err())
+	res, err := l.Allow(ctx, "key", l.Config().Limit())
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestTokenBucket_ExpiresFullBuckets(t *testing.T) {
+	l, mr, _ := setupRedisEnforcer(t, Config{RequestsPerSecond: 2, BurstCapacity: 10, Enabled: true})
+
+	_, err := l.Allow(context.Background(), "key", l.Config().Limit())
+	require.NoError(t, err)
+
+	// An empty bucket refills in capacity/rate seconds
+	assert.Equal(t, 5*time.Second, mr.TTL(KeyPrefix+"key"))
+}
+
+func TestTokenBucket_Boundary(t *testing.T) {
+	limit := Limit{RequestsPerSecond: 10, BurstCapacity: 1}
+	forEachBackend(t, TokenBucket, func(t *testing.T, l Limiter, now *time.Time) {
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, false)
+
+		// One millisecond short of a token
+		*now = now.Add(99 * time.Millisecond)
+		res := assertAllowed(t, l, "key", limit, false)
+		assert.InDelta(t, time.Millisecond, res.RetryAfter, float64(time.Millisecond))
+
+		*now = now.Add(time.Millisecond)
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, false)
+	})
+}
+
+func TestTokenBucket_ClockSkew(t *testing.T) {
+	limit := Limit{RequestsPerSecond: 10, BurstCapacity: 2}
+	forEachBackend(t, TokenBucket, func(t *testing.T, l Limiter, now *time.Time) {
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, true)
+
+		// A replica with its clock 10 seconds behind finds the bucket empty
+		*now = now.Add(-10 * time.Second)
+		assertAllowed(t, l, "key", limit, false)
+
+		// and does not make the next request see 10 seconds of refill
+		*now = now.Add(10*time.Second + 100*time.Millisecond)
+		assertAllowed(t, l, "key", limit, true)
+		assertAllowed(t, l, "key", limit, false)
+	})
+}
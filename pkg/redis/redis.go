@@ -3,36 +3,97 @@ package redis
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
+// DefaultHealthCheckInterval is how often a client created by Connect checks
+// whether Redis is reachable unless configured otherwise.
+const DefaultHealthCheckInterval = 5 * time.Second
+
 // Config holds Redis connection configuration.
 type Config struct {
-	Host        string
-	Port        string
-	Password    string
-	DB          int
-	MaxRetries  int
-	PoolSize    int
-	MinIdleConn int
+	Host                string
+	Port                string
+	Password            string
+	DB                  int
+	MaxRetries          int
+	PoolSize            int
+	MinIdleConn         int
+	HealthCheckInterval time.Duration // Used by Connect; DefaultHealthCheckInterval if zero
 }
 
 // Client wraps redis.Client with additional functionality.
 type Client struct {
 	*redis.Client
-	log *zap.Logger
+	log       *zap.Logger
+	available atomic.Bool
+	stop      chan struct{}
+	done      chan struct{}
 }
 
 // NewClient creates a new Redis client with the provided configuration.
 // It establishes a connection pool and verifies connectivity with a ping.
 func NewClient(cfg Config, log *zap.Logger) (*Client, error) {
-	addr := fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
+	c := newClient(cfg, log)
+
+	if err := c.ping(); err != nil {
+		_ = c.Client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis at %s: %w", c.Options().Addr, err)
+	}
+	c.available.Store(true)
+
+	log.Info("Redis connected successfully",
+		zap.String("addr", c.Options().Addr),
+		zap.Int("db", cfg.DB),
+		zap.Int("pool_size", cfg.PoolSize),
+	)
+
+	return c, nil
+}
 
+// Connect creates a Redis client like NewClient, but does not fail when Redis
+// is unreachable. The client is returned anyway and commands fail until Redis
+// comes back, when the connection pool redials on its own. Meanwhile callers
+// fall back as they do on any Redis error.
+//
+// The client pings Redis in the background to keep Available up to date and
+// to log when it is lost and restored.
+func Connect(cfg Config, log *zap.Logger) *Client {
+	c := newClient(cfg, log)
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+
+	if err := c.ping(); err != nil {
+		log.Warn("Redis unavailable, starting in degraded mode",
+			zap.String("addr", c.Options().Addr),
+			zap.Error(err),
+		)
+	} else {
+		c.available.Store(true)
+		log.Info("Redis connected successfully",
+			zap.String("addr", c.Options().Addr),
+			zap.Int("db", cfg.DB),
+			zap.Int("pool_size", cfg.PoolSize),
+		)
+	}
+
+	interval := cfg.HealthCheckInterval
+	if interval <= 0 {
+		interval = DefaultHealthCheckInterval
+	}
+	go c.watch(interval)
+
+	return c
+}
+
+// newClient creates a client without connecting.
+func newClient(cfg Config, log *zap.Logger) *Client {
 	rdb := redis.NewClient(&redis.Options{
-		Addr:         addr,
+		Addr:         fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
 		Password:     cfg.Password,
 		DB:           cfg.DB,
 		MaxRetries:   cfg.MaxRetries,
@@ -44,24 +105,49 @@ func NewClient(cfg Config, log *zap.Logger) (*Client, error) {
 		PoolTimeout:  4 * time.Second,
 	})
 
-	// Verify connection
+	return &Client{
+		Client: rdb,
+		log:    log,
+	}
+}
+
+// ping verifies the connection.
+func (c *Client) ping() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := rdb.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis at %s: %w", addr, err)
-	}
+	return c.Client.Ping(ctx).Err()
+}
 
-	log.Info("Redis connected successfully",
-		zap.String("addr", addr),
-		zap.Int("db", cfg.DB),
-		zap.Int("pool_size", cfg.PoolSize),
-	)
+// watch pings Redis every interval until the client is closed, recording and
+// logging changes in availability.
+func (c *Client) watch(interval time.Duration) {
+	defer close(c.done)
 
-	return &Client{
-		Client: rdb,
-		log:    log,
-	}, nil
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := c.ping()
+		switch {
+		case err == nil && !c.available.Swap(true):
+			c.log.Info("Redis connection restored", zap.String("addr", c.Options().Addr))
+		case err != nil && c.available.Swap(false):
+			c.log.Warn("Redis connection lost", zap.String("addr", c.Options().Addr), zap.Error(err))
+		}
+	}
+}
+
+// Available reports whether Redis answered the last health check. Clients
+// created by NewClient only check at startup.
+func (c *Client) Available() bool {
+	return c.available.Load()
 }
 
 // Ping checks if the Redis connection is alive.
@@ -71,6 +157,11 @@ func (c *Client) Ping(ctx context.Context) error {
 
 // Close gracefully closes the Redis connection.
 func (c *Client) Close() error {
+	if c.stop != nil {
+		close(c.stop)
+		<-c.done
+	}
+
 	c.log.Info("Closing Redis connection")
 	return c.Client.Close()
 }
@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// testConfig returns the configuration of a client for the Redis at addr.
+func testConfig(t *testing.T, addr string) Config {
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	return Config{Host: host, Port: port, PoolSize: 2, HealthCheckInterval: 10 * time.Millisecond}
+}
+
+func TestNewClient_Unreachable(t *testing.T) {
+	mr := miniredis.RunT(t)
+	addr := mr.Addr()
+	mr.Close()
+
+	_, err := NewClient(testConfig(t, addr), zaptest.NewLogger(t))
+	assert.Error(t, err)
+}
+
+func TestConnect_ReconnectsWhenRedisComesBack(t *testing.T) {
+	mr := miniredis.RunT(t)
+	addr := mr.Addr()
+	mr.Close()
+
+	c := Connect(testConfig(t, addr), zaptest.NewLogger(t))
+	t.Cleanup(func() { _ = c.Close() })
+	assert.False(t, c.Available())
+
+	require.NoError(t, mr.Restart())
+	require.Eventually(t, c.Available, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return c.Set(context.Background(), "key", "value", 0).Err() == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mr.Close()
+	require.Eventually(t, func() bool { return !c.Available() }, 5*time.Second, 10*time.Millisecond)
+}
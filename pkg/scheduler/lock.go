@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	redisclient "grpc-user-service/pkg/redis"
+)
+
+// ErrLockHeld is returned by AcquireLock when another owner holds the lock.
+var ErrLockHeld = errors.New("scheduler: lock held by another owner")
+
+// acquireScript takes the lock and, only if it was free, issues the next
+// fencing token. Tokens increase monotonically per lock and are never reused,
+// even after the lock expires. A counter below the floor in ARGV[3], such as
+// one lost with Redis, is first raised to it. The script returns the token, 0
+// if the lock is held, and 1 if the counter was raised.
+var acquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	local floor = tonumber(ARGV[3])
+	local reseeded = 0
+	if floor > tonumber(redis.call("GET", KEYS[2]) or "0") then
+		redis.call("SET", KEYS[2], floor)
+		reseeded = 1
+	end
+	return {redis.call("INCR", KEYS[2]), reseeded}
+end
+return {0, 0}
+`)
+
+// releaseScript deletes the lock only if it is still owned by the caller, so an
+// owner whose lock expired cannot release a lock taken over by another one.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Lock is a held distributed lock.
+type Lock struct {
+	rdb     *redisclient.Client
+	key     string
+	owner   string
+	token   int64
+	release func() // Set instead of rdb for in-process locks
+}
+
+// AcquireLock takes the lock called name for ttl. It returns ErrLockHeld if
+// the lock is already taken.
+//
+// Each successful acquisition carries a fencing token that is greater than
+// the token of every earlier holder. A holder that was paused past its TTL can
+// keep running without knowing it lost the lock; resources it writes to can
+// reject such stale writes by refusing tokens lower than the last one seen.
+func AcquireLock(ctx context.Context, rdb *redisclient.Client, name string, ttl time.Duration) (*Lock, error) {
+	lock, _, err := acquireLock(ctx, rdb, name, ttl, 0)
+	return lock, err
+}
+
+// acquireLock takes the lock called name for ttl, issuing a fencing token
+// greater than floor. It reports whether the token counter had to be raised
+// to floor, which means Redis lost it.
+func acquireLock(ctx context.Context, rdb *redisclient.Client, name string, ttl time.Duration, floor int64) (*Lock, bool, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, false, fmt.Errorf("scheduler: failed to generate lock owner: %w", err)
+	}
+	owner := hex.EncodeToString(buf)
+	key := lockKey(name)
+
+	reply, err := acquireScript.Run(ctx, rdb.Client, []string{key, fenceKey(name)}, owner, ttl.Milliseconds(), floor).Int64Slice()
+	if err != nil {
+		return nil, false, fmt.Errorf("scheduler: failed to acquire lock %s: %w", name, err)
+	}
+	if len(reply) != 2 {
+		return nil, false, fmt.Errorf("scheduler: unexpected lock reply %v", reply)
+	}
+	if reply[0] == 0 {
+		return nil, false, ErrLockHeld
+	}
+
+	return &Lock{rdb: rdb, key: key, owner: owner, token: reply[0]}, reply[1] == 1, nil
+}
+
+// Token returns the fencing token issued with the lock.
+func (l *Lock) Token() int64 {
+	return l.token
+}
+
+// fenced reports whether the token is issued by Redis and therefore keeps
+// increasing across processes and restarts.
+func (l *Lock) fenced() bool {
+	return l.rdb != nil
+}
+
+// Release frees the lock if it is still owned by this holder.
+func (l *Lock) Release(ctx context.Context) error {
+	if l.release != nil {
+		l.release()
+		return nil
+	}
+	return releaseScript.Run(ctx, l.rdb.Client, []string{l.key}, l.owner).Err()
+}
+
+// localLocks stands in for Redis locks when the scheduler runs without Redis.
+// It only excludes runs within this process, which is enough when there is a
+// single instance. Fencing tokens restart from 1 with the process, so they are
+// reported in the job status but not passed to jobs.
+type localLocks struct {
+	mu     sync.Mutex
+	held   map[string]bool
+	tokens map[string]int64
+}
+
+// newLocalLocks creates an empty set of in-process locks.
+func newLocalLocks() *localLocks {
+	return &localLocks{held: make(map[string]bool), tokens: make(map[string]int64)}
+}
+
+// acquire takes the lock called name. It returns ErrLockHeld if the lock is
+// already taken.
+func (l *localLocks) acquire(name string) (*Lock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[name] {
+		return nil, ErrLockHeld
+	}
+	l.held[name] = true
+	l.tokens[name]++
+
+	return &Lock{
+		key:   name,
+		token: l.tokens[name],
+		release: func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			delete(l.held, name)
+		},
+	}, nil
+}
+
+// lockKey returns the Redis key of a lock.
+func lockKey(name string) string {
+	return fmt.Sprintf("scheduler:lock:%s", name)
+}
+
+// fenceKey returns the Redis key of the fencing token counter of a lock.
+func fenceKey(name string) string {
+	return fmt.Sprintf("scheduler:fence:%s", name)
+}
+
+// tokenKey is the context key of the fencing token.
+type tokenKey struct{}
+
+// FencingToken returns the fencing token of the Redis lock held by the job
+// running with ctx, if any. Jobs pass it along with their writes, so that
+// resources can refuse writes carrying a lower token than one already seen.
+func FencingToken(ctx context.Context) (int64, bool) {
+	token, ok := ctx.Value(tokenKey{}).(int64)
+	return token, ok
+}
+
+// WithFencingToken returns a copy of ctx carrying the fencing token. The
+// scheduler sets it for every run; it is exported for running jobs directly.
+func WithFencingToken(ctx context.Context, token int64) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
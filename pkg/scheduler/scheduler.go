@@ -0,0 +1,381 @@
+// Package scheduler runs periodic background jobs across several instances of
+// the service. Every run of a job holds a Redis lock, so a job runs on at most
+// one instance at a time.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	redisclient "grpc-user-service/pkg/redis"
+)
+
+// DefaultTimeout is the timeout of a job attempt when JobOptions.Timeout is unset.
+const DefaultTimeout = 5 * time.Minute
+
+// Outcomes of a run reported in JobStatus.LastOutcome.
+const (
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+	OutcomeSkipped   = "skipped" // Another instance held the lock
+)
+
+// Job is a unit of periodic background work.
+type Job interface {
+	// Name identifies the job in logs and status and names its lock.
+	Name() string
+	// Run performs one pass of the job and returns the number of items processed.
+	Run(ctx context.Context) (int64, error)
+}
+
+// JobOptions configures when and how a job runs.
+type JobOptions struct {
+	Schedule     string        // Cron expression, descriptor or "@every <duration>"
+	Timeout      time.Duration // Maximum duration of one attempt (default DefaultTimeout)
+	Retries      int           // Additional attempts after a failed one
+	RetryBackoff time.Duration // Delay between attempts
+	Jitter       time.Duration // Upper bound of a random delay added to every activation
+}
+
+// JobStatus describes a registered job and its most recent run on this instance.
+type JobStatus struct {
+	Name             string
+	Schedule         string
+	NextRun          time.Time
+	Running          bool
+	LastRun          time.Time
+	LastOutcome      string
+	LastError        string
+	LastProcessed    int64
+	LastDuration     time.Duration
+	LastAttempts     int
+	LastFencingToken int64
+	Runs             int64 // Runs that held the lock
+	Failures         int64 // Runs that failed after all attempts
+	Skips            int64 // Activations skipped because another instance held the lock
+}
+
+// entry is a registered job.
+type entry struct {
+	job      Job
+	opts     JobOptions
+	schedule Schedule
+	status   JobStatus
+}
+
+// FenceStore is a resource that refuses writes with stale fencing tokens, and
+// records the highest token it accepted per job.
+type FenceStore interface {
+	// LastFencingToken returns the highest fencing token recorded for job, or
+	// 0 if there is none.
+	LastFencingToken(ctx context.Context, job string) (int64, error)
+}
+
+// Scheduler runs registered jobs on their schedules.
+type Scheduler struct {
+	rdb    *redisclient.Client
+	local  *localLocks
+	fences FenceStore // Optional floor of fencing tokens
+	log    *zap.Logger
+	now    func() time.Time
+	mu     sync.Mutex
+	jobs   map[string]*entry
+	wg     sync.WaitGroup
+}
+
+// Option configures optional behaviour of the scheduler.
+type Option func(*Scheduler)
+
+// WithFenceStore issues every fencing token above the last one fences
+// recorded for the job. Without it, a Redis that lost its token counters
+// restarts tokens from 1, and fences refuse every run as stale.
+func WithFenceStore(fences FenceStore) Option {
+	return func(s *Scheduler) {
+		s.fences = fences
+	}
+}
+
+// New creates a new scheduler using rdb for locking. With a nil rdb, runs
+// are only locked against each other within this process.
+func New(rdb *redisclient.Client, log *zap.Logger, opts ...Option) *Scheduler {
+	var local *localLocks
+	if rdb == nil {
+		local = newLocalLocks()
+	}
+	s := &Scheduler{
+		rdb:   rdb,
+		local: local,
+		log:   log,
+		now:   time.Now,
+		jobs:  make(map[string]*entry),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register adds a job. It fails if the schedule cannot be parsed or a job
+// with the same name is already registered.
+func (s *Scheduler) Register(j Job, opts JobOptions) error {
+	schedule, err := ParseSchedule(opts.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", j.Name(), err)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Retries < 0 || opts.RetryBackoff < 0 || opts.Jitter < 0 {
+		return fmt.Errorf("job %s: retries, retry backoff and jitter cannot be negative", j.Name())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[j.Name()]; ok {
+		return fmt.Errorf("job %s is already registered", j.Name())
+	}
+	s.jobs[j.Name()] = &entry{
+		job:      j,
+		opts:     opts,
+		schedule: schedule,
+		status:   JobStatus{Name: j.Name(), Schedule: opts.Schedule},
+	}
+	return nil
+}
+
+// Start runs every registered job on its schedule until ctx is cancelled.
+// It returns immediately; use Wait to block until all jobs have stopped.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.jobs {
+		s.wg.Add(1)
+		go func(e *entry) {
+			defer s.wg.Done()
+			s.loop(ctx, e)
+		}(e)
+	}
+}
+
+// Wait blocks until all jobs started by Start have returned.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// Status returns the status of all registered jobs, sorted by name.
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]JobStatus, 0, len(s.jobs))
+	for _, e := range s.jobs {
+		out = append(out, e.status)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// RunOnce runs the named job immediately, outside its schedule, and returns
+// its status afterwards. The run still takes the job's lock.
+func (s *Scheduler) RunOnce(ctx context.Context, name string) (JobStatus, error) {
+	s.mu.Lock()
+	e, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return JobStatus{}, fmt.Errorf("job %s is not registered", name)
+	}
+
+	err := s.run(ctx, e)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return e.status, err
+}
+
+// loop waits for each activation of a job and runs it until ctx is cancelled.
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	for {
+		next := e.schedule.Next(s.now())
+		if next.IsZero() {
+			s.log.Warn("job schedule never fires again", zap.String("job", e.job.Name()))
+			return
+		}
+		if e.opts.Jitter > 0 {
+			next = next.Add(rand.N(e.opts.Jitter))
+		}
+
+		s.mu.Lock()
+		e.status.NextRun = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// Errors are logged and recorded in the status; the job runs again on its next activation
+		_ = s.run(ctx, e)
+	}
+}
+
+// lockTTL returns how long a run of the job may hold its lock: every attempt
+// with its timeout plus the backoff between attempts.
+func (e *entry) lockTTL() time.Duration {
+	attempts := time.Duration(e.opts.Retries + 1)
+	return attempts*e.opts.Timeout + (attempts-1)*e.opts.RetryBackoff
+}
+
+// acquire takes the lock of a job. With a fence store, the fencing token is
+// issued above the last one the store recorded, and a token counter found
+// behind it is logged as lost.
+func (s *Scheduler) acquire(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	if s.local != nil {
+		return s.local.acquire(name)
+	}
+
+	var floor int64
+	if s.fences != nil {
+		last, err := s.fences.LastFencingToken(ctx, name)
+		if err != nil {
+			// The run's writes go to the fenced store, so they will likely fail too
+			s.log.Warn("failed to read last fencing token", zap.String("job", name), zap.Error(err))
+		}
+		floor = last
+	}
+
+	lock, reseeded, err := acquireLock(ctx, s.rdb, name, ttl, floor)
+	if reseeded {
+		s.log.Warn("fencing token counter was behind the fence store, likely lost with Redis; reseeded",
+			zap.String("job", name),
+			zap.Int64("last_fencing_token", floor),
+			zap.Int64("fencing_token", lock.Token()),
+		)
+	}
+	return lock, err
+}
+
+// run takes the job's lock and runs the job, retrying failed attempts.
+func (s *Scheduler) run(ctx context.Context, e *entry) error {
+	name := e.job.Name()
+
+	lock, err := s.acquire(ctx, name, e.lockTTL())
+	if errors.Is(err, ErrLockHeld) {
+		s.log.Debug("job skipped, lock held by another instance", zap.String("job", name))
+		s.mu.Lock()
+		e.status.LastOutcome = OutcomeSkipped
+		e.status.Skips++
+		s.mu.Unlock()
+		return nil
+	}
+	if err != nil {
+		s.log.Error("failed to acquire job lock", zap.String("job", name), zap.Error(err))
+		return err
+	}
+	defer func() {
+		// Release with a fresh context so that the lock is freed even after cancellation
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := lock.Release(releaseCtx); err != nil {
+			s.log.Warn("failed to release job lock", zap.String("job", name), zap.Error(err))
+		}
+	}()
+
+	start := s.now()
+	s.mu.Lock()
+	e.status.Running = true
+	e.status.LastRun = start
+	s.mu.Unlock()
+
+	// Only tokens of Redis locks are handed to the job for fencing; in-process
+	// tokens restart with the process and would be refused as stale
+	runCtx := ctx
+	if lock.fenced() {
+		runCtx = WithFencingToken(ctx, lock.Token())
+	}
+
+	var processed int64
+	attempts := 0
+	for {
+		attempts++
+		processed, err = s.attempt(runCtx, e)
+		if err == nil || attempts > e.opts.Retries || ctx.Err() != nil {
+			break
+		}
+
+		s.log.Warn("job attempt failed, retrying",
+			zap.String("job", name),
+			zap.Int("attempt", attempts),
+			zap.Duration("backoff", e.opts.RetryBackoff),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+		case <-time.After(e.opts.RetryBackoff):
+		}
+	}
+	duration := s.now().Sub(start)
+
+	s.mu.Lock()
+	e.status.Running = false
+	e.status.Runs++
+	e.status.LastProcessed = processed
+	e.status.LastDuration = duration
+	e.status.LastAttempts = attempts
+	e.status.LastFencingToken = lock.Token()
+	if err != nil {
+		e.status.LastOutcome = OutcomeFailed
+		e.status.LastError = err.Error()
+		e.status.Failures++
+	} else {
+		e.status.LastOutcome = OutcomeSucceeded
+		e.status.LastError = ""
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		s.log.Error("job failed",
+			zap.String("job", name),
+			zap.Int("attempts", attempts),
+			zap.Int64("processed", processed),
+			zap.Duration("duration", duration),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	s.log.Info("job completed",
+		zap.String("job", name),
+		zap.Int64("processed", processed),
+		zap.Duration("duration", duration),
+		zap.Int64("fencing_token", lock.Token()),
+	)
+	return nil
+}
+
+// attempt runs the job once within its timeout. A panic in the job is
+// reported as a failed attempt instead of crashing the service.
+func (s *Scheduler) attempt(ctx context.Context, e *entry) (processed int64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, e.opts.Timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error("job panicked", zap.String("job", e.job.Name()), zap.Any("panic", r), zap.Stack("stack"))
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return e.job.Run(ctx)
+}
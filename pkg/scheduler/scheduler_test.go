@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	redisclient "grpc-user-service/pkg/redis"
+)
+
+// testJob counts its runs and fails the first failFirst attempts
+type testJob struct {
+	name      string
+	failFirst int32
+	processed int64
+	calls     atomic.Int32
+	tokens    chan int64
+	panics    bool
+}
+
+func (j *testJob) Name() string { return j.name }
+
+func (j *testJob) Run(ctx context.Context) (int64, error) {
+	n := j.calls.Add(1)
+	if j.tokens != nil {
+		token, _ := FencingToken(ctx)
+		j.tokens <- token
+	}
+	if j.panics {
+		panic("boom")
+	}
+	if n <= j.failFirst {
+		return 0, errors.New("transient failure")
+	}
+	return j.processed, nil
+}
+
+func setupScheduler(t *testing.T) (*Scheduler, *miniredis.Miniredis, *redisclient.Client) {
+	mr := miniredis.RunT(t)
+	rdb, err := redisclient.NewClient(redisclient.Config{Host: mr.Host(), Port: mr.Port(), PoolSize: 2}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = rdb.Close()
+	})
+	return New(rdb, zaptest.NewLogger(t)), mr, rdb
+}
+
+func TestAcquireLock_FencingTokensIncrease(t *testing.T) {
+	_, mr, rdb := setupScheduler(t)
+	ctx := context.Background()
+
+	first, err := AcquireLock(ctx, rdb, "job", time.Second)
+	require.NoError(t, err)
+
+	_, err = AcquireLock(ctx, rdb, "job", time.Second)
+	assert.ErrorIs(t, err, ErrLockHeld)
+
+	// The first holder stalls past its TTL and the lock is taken over
+	mr.FastForward(2 * time.Second)
+	second, err := AcquireLock(ctx, rdb, "job", time.Minute)
+	require.NoError(t, err)
+	assert.Greater(t, second.Token(), first.Token())
+
+	// The stale holder cannot release the new holder's lock
+	require.NoError(t, first.Release(ctx))
+	assert.True(t, mr.Exists(lockKey("job")))
+
+	require.NoError(t, second.Release(ctx))
+	assert.False(t, mr.Exists(lockKey("job")))
+}
+
+func TestScheduler_Register_Validation(t *testing.T) {
+	s, _, _ := setupScheduler(t)
+
+	require.NoError(t, s.Register(&testJob{name: "a"}, JobOptions{Schedule: "@hourly"}))
+	assert.Error(t, s.Register(&testJob{name: "a"}, JobOptions{Schedule: "@hourly"}))
+	assert.Error(t, s.Register(&testJob{name: "b"}, JobOptions{Schedule: "every hour"}))
+	assert.Error(t, s.Register(&testJob{name: "c"}, JobOptions{Schedule: "@hourly", Retries: -1}))
+}
+
+func TestScheduler_RunOnce_RetriesAndReports(t *testing.T) {
+	s, mr, _ := setupScheduler(t)
+	job := &testJob{name: "purge", failFirst: 2, processed: 7, tokens: make(chan int64, 3)}
+	require.NoError(t, s.Register(job, JobOptions{Schedule: "@daily", Timeout: time.Second, Retries: 2, RetryBackoff: time.Millisecond}))
+
+	status, err := s.RunOnce(context.Background(), "purge")
+
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeSucceeded, status.LastOutcome)
+	assert.Equal(t, 3, status.LastAttempts)
+	assert.Equal(t, int64(7), status.LastProcessed)
+	assert.Equal(t, int64(1), status.Runs)
+	assert.Equal(t, int64(1), status.LastFencingToken)
+	assert.False(t, status.Running)
+	assert.False(t, mr.Exists(lockKey("purge")))
+
+	// Every attempt sees the fencing token of the run
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, int64(1), <-job.tokens)
+	}
+}
+
+func TestScheduler_RunOnce_FailsAfterRetries(t *testing.T) {
+	s, _, _ := setupScheduler(t)
+	job := &testJob{name: "purge", failFirst: 10}
+	require.NoError(t, s.Register(job, JobOptions{Schedule: "@daily", Retries: 1}))
+
+	status, err := s.RunOnce(context.Background(), "purge")
+
+	assert.Error(t, err)
+	assert.Equal(t, OutcomeFailed, status.LastOutcome)
+	assert.Equal(t, "transient failure", status.LastError)
+	assert.Equal(t, int32(2), job.calls.Load())
+	assert.Equal(t, int64(1), status.Failures)
+}
+
+func TestScheduler_RunOnce_RecoversPanic(t *testing.T) {
+	s, _, _ := setupScheduler(t)
+	require.NoError(t, s.Register(&testJob{name: "panicky", panics: true}, JobOptions{Schedule: "@daily"}))
+
+	status, err := s.RunOnce(context.Background(), "panicky")
+
+	assert.Error(t, err)
+	assert.Equal(t, OutcomeFailed, status.LastOutcome)
+}
+
+func TestScheduler_RunOnce_SkipsWhenLocked(t *testing.T) {
+	s, _, rdb := setupScheduler(t)
+	job := &testJob{name: "purge"}
+	require.NoError(t, s.Register(job, JobOptions{Schedule: "@daily"}))
+
+	// Another instance is running the job
+	_, err := AcquireLock(context.Background(), rdb, "purge", time.Minute)
+	require.NoError(t, err)
+
+	status, err := s.RunOnce(context.Background(), "purge")
+
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeSkipped, status.LastOutcome)
+	assert.Equal(t, int64(1), status.Skips)
+	assert.Zero(t, job.calls.Load())
+}
+
+func TestScheduler_RunOnce_UnknownJob(t *testing.T) {
+	s, _, _ := setupScheduler(t)
+
+	_, err := s.RunOnce(context.Background(), "missing")
+
+	assert.Error(t, err)
+}
+
+func TestScheduler_Start_RunsOnSchedule(t *testing.T) {
+	s, _, _ := setupScheduler(t)
+	job := &testJob{name: "tick", tokens: make(chan int64, 10)}
+	require.NoError(t, s.Register(job, JobOptions{Schedule: "@every 1s"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+
+	select {
+	case token := <-job.tokens:
+		assert.Equal(t, int64(1), token)
+	case <-time.After(3 * time.Second):
+		t.Fatal("job did not run")
+	}
+
+	cancel()
+	s.Wait()
+
+	status := s.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, "tick", status[0].Name)
+	assert.Equal(t, "@every 1s", status[0].Schedule)
+	assert.False(t, status[0].NextRun.IsZero())
+}
+
+func TestScheduler_WithoutRedis_LocksInProcess(t *testing.T) {
+	s := New(nil, zaptest.NewLogger(t))
+	job := &testJob{name: "purge", tokens: make(chan int64, 2)}
+	require.NoError(t, s.Register(job, JobOptions{Schedule: "@daily"}))
+
+	// A run in progress in this process
+	lock, err := s.local.acquire("purge")
+	require.NoError(t, err)
+
+	status, err := s.RunOnce(context.Background(), "purge")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeSkipped, status.LastOutcome)
+
+	require.NoError(t, lock.Release(context.Background()))
+	status, err = s.RunOnce(context.Background(), "purge")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeSucceeded, status.LastOutcome)
+	assert.Equal(t, int64(2), status.LastFencingToken, "fencing tokens keep increasing")
+	assert.Zero(t, <-job.tokens, "in-process tokens are not used for fencing")
+}
+
+// fenceStore is a FenceStore with fixed last tokens.
+type fenceStore map[string]int64
+
+func (f fenceStore) LastFencingToken(_ context.Context, job string) (int64, error) {
+	return f[job], nil
+}
+
+func TestScheduler_WithFenceStore_ReseedsLostCounter(t *testing.T) {
+	_, mr, rdb := setupScheduler(t)
+	s := New(rdb, zaptest.NewLogger(t), WithFenceStore(fenceStore{"purge": 41}))
+	job := &testJob{name: "purge", tokens: make(chan int64, 2)}
+	require.NoError(t, s.Register(job, JobOptions{Schedule: "@daily"}))
+
+	// Redis lost the counter: tokens resume above the recorded one
+	require.False(t, mr.Exists(fenceKey("purge")))
+	status, err := s.RunOnce(context.Background(), "purge")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeSucceeded, status.LastOutcome)
+	assert.Equal(t, int64(42), <-job.tokens)
+
+	// A counter already ahead of the recorded token is left alone
+	status, err = s.RunOnce(context.Background(), "purge")
+	require.NoError(t, err)
+	assert.Equal(t, int64(43), status.LastFencingToken)
+	assert.Equal(t, int64(43), <-job.tokens)
+}
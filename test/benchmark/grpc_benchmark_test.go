@@ -67,7 +67,19 @@ func (m *MockRepository) GetByEmail(ctx context.Context, email string) (*grpcdom
 	return nil, nil
 }
 
-func (m *MockRepository) Update(ctx context.Context, u *grpcdomain.User) (int64, error) {
+func (m *MockRepository) GetByUsername(ctx context.Context, username string) (*grpcdomain.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, user := range m.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockRepository) Update(ctx context.Context, u *grpcdomain.User, cleared ...string) (int64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -89,7 +101,7 @@ func (m *MockRepository) Delete(ctx context.Context, id int64) (int64, error) {
 	return 0, fmt.Errorf("user not found")
 }
 
-func (m *MockRepository) List(ctx context.Context, query string, page, limit int64) ([]grpcdomain.User, int64, error) {
+func (m *MockRepository) List(ctx context.Context, query, locale string, page, limit int64) ([]grpcdomain.User, int64, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -113,6 +125,72 @@ func (m *MockRepository) List(ctx context.Context, query string, page, limit int
 	return users[start:end], total, nil
 }
 
+func (m *MockRepository) Erase(ctx context.Context, id int64, reason string) (*grpcdomain.Erasure, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	user, exists := m.users[id]
+	if !exists {
+		return nil, fmt.Errorf("user not found")
+	}
+	m.users[id] = &grpcdomain.User{ID: user.ID, Name: "Erased user", Email: fmt.Sprintf("erased-%d@erased.invalid", id)}
+	return &grpcdomain.Erasure{UserID: id, Reason: reason, ErasedAt: time.Now()}, nil
+}
+
+// The benchmark mock only tracks the primary address stored on the user.
+func (m *MockRepository) ListEmails(ctx context.Context, userID int64) ([]grpcdomain.Email, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if user, exists := m.users[userID]; exists {
+		return []grpcdomain.Email{{UserID: userID, Address: user.Email, Primary: true}}, nil
+	}
+	return []grpcdomain.Email{}, nil
+}
+
+func (m *MockRepository) AddEmail(ctx context.Context, userID int64, address string) error {
+	return fmt.Errorf("secondary emails not supported by benchmark mock")
+}
+
+func (m *MockRepository) RemoveEmail(ctx context.Context, userID int64, address string) error {
+	return fmt.Errorf("secondary emails not supported by benchmark mock")
+}
+
+func (m *MockRepository) SetPrimaryEmail(ctx context.Context, userID int64, address string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if user, exists := m.users[userID]; exists {
+		user.Email = address
+		return nil
+	}
+	return fmt.Errorf("user not found")
+}
+
+func (m *MockRepository) SetEmailVerification(ctx context.Context, userID int64, address, tokenHash string, expiresAt time.Time) error {
+	return nil
+}
+
+func (m *MockRepository) VerifyEmail(ctx context.Context, userID int64, address, tokenHash string) error {
+	return nil
+}
+
+func (m *MockRepository) LinkIdentity(ctx context.Context, identity *grpcdomain.Identity) error {
+	return fmt.Errorf("identities not supported by benchmark mock")
+}
+
+func (m *MockRepository) UnlinkIdentity(ctx context.Context, userID int64, provider, subject string) error {
+	return fmt.Errorf("identities not supported by benchmark mock")
+}
+
+func (m *MockRepository) GetByIdentity(ctx context.Context, provider, subject string) (*grpcdomain.User, error) {
+	return nil, nil
+}
+
+func (m *MockRepository) ListIdentities(ctx context.Context, userID int64) ([]grpcdomain.Identity, error) {
+	return []grpcdomain.Identity{}, nil
+}
+
 // Benchmark setup
 type BenchmarkServer struct {
 	server   *grpc.Server
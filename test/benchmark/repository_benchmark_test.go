@@ -0,0 +1,112 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"grpc-user-service/internal/adapter/repository/batched"
+	"grpc-user-service/internal/adapter/repository/postgres"
+	grpcdomain "grpc-user-service/internal/domain/user"
+	"grpc-user-service/internal/usecase/user"
+	"grpc-user-service/pkg/dataloader"
+)
+
+// repositoryBenchmarkUsers is the number of users the lookups pick from
+const repositoryBenchmarkUsers = 1000
+
+// databaseRoundTrip models the network latency of a PostgreSQL query, which
+// the in-process SQLite database used here does not have
+const databaseRoundTrip = 500 * time.Microsecond
+
+// databaseConnections models the connection pool: at most this many queries
+// are in flight, as with DB_MAX_OPEN_CONNS
+const databaseConnections = 10
+
+// lookupConcurrency is the number of concurrent lookups per CPU
+const lookupConcurrency = 64
+
+// setupRepositoryBenchmark creates a user repository on a SQLite database
+// whose queries take at least databaseRoundTrip on one of
+// databaseConnections connections, and counts them.
+func setupRepositoryBenchmark(b *testing.B) (*postgres.UserRepoPG, *atomic.Int64) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(b.TempDir(), "users.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		b.Fatalf("failed to open database: %v", err)
+	}
+	if err := db.AutoMigrate(&postgres.UserSchema{}, &postgres.UserEmailSchema{}); err != nil {
+		b.Fatalf("failed to migrate database: %v", err)
+	}
+
+	repo := postgres.NewUserRepoPG(db, zap.NewNop())
+	ctx := context.Background()
+	for i := range repositoryBenchmarkUsers {
+		u := &grpcdomain.User{Name: "Benchmark User", Email: fmt.Sprintf("user%d@example.com", i)}
+		if _, err := repo.Create(ctx, u); err != nil {
+			b.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	queries := &atomic.Int64{}
+	connections := make(chan struct{}, databaseConnections)
+	err = db.Callback().Query().Before("gorm:query").Register("benchmark:round_trip", func(*gorm.DB) {
+		queries.Add(1)
+		connections <- struct{}{}
+		time.Sleep(databaseRoundTrip)
+		<-connections
+	})
+	if err != nil {
+		b.Fatalf("failed to register query callback: %v", err)
+	}
+
+	return repo, queries
+}
+
+// runGetByIDBenchmark looks up random users from many goroutines at once,
+// like concurrent cache misses, and reports the queries issued per lookup.
+func runGetByIDBenchmark(b *testing.B, repo user.Repository, queries *atomic.Int64) {
+	ctx := context.Background()
+	queries.Store(0)
+
+	b.SetParallelism(lookupConcurrency)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			id := rand.Int64N(repositoryBenchmarkUsers) + 1
+			if _, err := repo.GetByID(ctx, id); err != nil {
+				b.Errorf("GetByID(%d) failed: %v", id, err)
+			}
+		}
+	})
+
+	b.ReportMetric(float64(queries.Load())/float64(b.N), "queries/op")
+}
+
+// BenchmarkRepository_GetByID compares one query per lookup with lookups
+// coalesced into IN queries by the batched repository.
+func BenchmarkRepository_GetByID(b *testing.B) {
+	b.Run("Unbatched", func(b *testing.B) {
+		repo, queries := setupRepositoryBenchmark(b)
+		runGetByIDBenchmark(b, repo, queries)
+	})
+
+	for _, wait := range []time.Duration{500 * time.Microsecond, 2 * time.Millisecond} {
+		b.Run(fmt.Sprintf("Batched_Wait%s", wait), func(b *testing.B) {
+			repo, queries := setupRepositoryBenchmark(b)
+			batchedRepo := batched.NewUserRepository(repo, dataloader.Config{Wait: wait, MaxBatch: 100})
+			runGetByIDBenchmark(b, batchedRepo, queries)
+		})
+	}
+}
@@ -52,7 +52,15 @@ func (m *MockRepository) GetByEmail(ctx context.Context, email string) (*grpcdom
 	return args.Get(0).(*grpcdomain.User), args.Error(1)
 }
 
-func (m *MockRepository) Update(ctx context.Context, u *grpcdomain.User) (int64, error) {
+func (m *MockRepository) GetByUsername(ctx context.Context, username string) (*grpcdomain.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*grpcdomain.User), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, u *grpcdomain.User, cleared ...string) (int64, error) {
 	args := m.Called(ctx, u)
 	return args.Get(0).(int64), args.Error(1)
 }
@@ -62,11 +70,78 @@ func (m *MockRepository) Delete(ctx context.Context, id int64) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockRepository) List(ctx context.Context, query string, page, limit int64) ([]grpcdomain.User, int64, error) {
-	args := m.Called(ctx, query, page, limit)
+func (m *MockRepository) List(ctx context.Context, query, locale string, page, limit int64) ([]grpcdomain.User, int64, error) {
+	args := m.Called(ctx, query, locale, page, limit)
 	return args.Get(0).([]grpcdomain.User), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockRepository) Erase(ctx context.Context, id int64, reason string) (*grpcdomain.Erasure, error) {
+	args := m.Called(ctx, id, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*grpcdomain.Erasure), args.Error(1)
+}
+
+func (m *MockRepository) ListEmails(ctx context.Context, userID int64) ([]grpcdomain.Email, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]grpcdomain.Email), args.Error(1)
+}
+
+func (m *MockRepository) AddEmail(ctx context.Context, userID int64, address string) error {
+	args := m.Called(ctx, userID, address)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RemoveEmail(ctx context.Context, userID int64, address string) error {
+	args := m.Called(ctx, userID, address)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetPrimaryEmail(ctx context.Context, userID int64, address string) error {
+	args := m.Called(ctx, userID, address)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetEmailVerification(ctx context.Context, userID int64, address, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, userID, address, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) VerifyEmail(ctx context.Context, userID int64, address, tokenHash string) error {
+	args := m.Called(ctx, userID, address, tokenHash)
+	return args.Error(0)
+}
+
+func (m *MockRepository) LinkIdentity(ctx context.Context, identity *grpcdomain.Identity) error {
+	args := m.Called(ctx, identity)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UnlinkIdentity(ctx context.Context, userID int64, provider, subject string) error {
+	args := m.Called(ctx, userID, provider, subject)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByIdentity(ctx context.Context, provider, subject string) (*grpcdomain.User, error) {
+	args := m.Called(ctx, provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*grpcdomain.User), args.Error(1)
+}
+
+func (m *MockRepository) ListIdentities(ctx context.Context, userID int64) ([]grpcdomain.Identity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]grpcdomain.Identity), args.Error(1)
+}
+
 // UserAPIIntegrationTestSuite tests the HTTP API through grpc-gateway
 type UserAPIIntegrationTestSuite struct {
 	suite.Suite
@@ -277,7 +352,7 @@ func (suite *UserAPIIntegrationTestSuite) TestListUsersAPI() {
 		{ID: 1, Name: "John Doe", Email: "john@example.com"},
 		{ID: 2, Name: "Jane Smith", Email: "jane@example.com"},
 	}
-	suite.mockRepo.On("List", mock.Anything, "", int64(1), mock.AnythingOfType("int64")).Return(mockUsers, int64(50), nil)
+	suite.mockRepo.On("List", mock.Anything, "", "", int64(1), mock.AnythingOfType("int64")).Return(mockUsers, int64(50), nil)
 
 	// Make HTTP request
 	resp, err := suite.makeRequest("GET", "/v1/users?page=1&limit=10", nil)
@@ -3,7 +3,9 @@ package test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
@@ -41,7 +43,15 @@ func (m *ComprehensiveMockRepository) GetByEmail(ctx context.Context, email stri
 	return args.Get(0).(*grpcdomain.User), args.Error(1)
 }
 
-func (m *ComprehensiveMockRepository) Update(ctx context.Context, u *grpcdomain.User) (int64, error) {
+func (m *ComprehensiveMockRepository) GetByUsername(ctx context.Context, username string) (*grpcdomain.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*grpcdomain.User), args.Error(1)
+}
+
+func (m *ComprehensiveMockRepository) Update(ctx context.Context, u *grpcdomain.User, cleared ...string) (int64, error) {
 	args := m.Called(ctx, u)
 	return args.Get(0).(int64), args.Error(1)
 }
@@ -51,11 +61,78 @@ func (m *ComprehensiveMockRepository) Delete(ctx context.Context, id int64) (int
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *ComprehensiveMockRepository) List(ctx context.Context, query string, page, limit int64) ([]grpcdomain.User, int64, error) {
-	args := m.Called(ctx, query, page, limit)
+func (m *ComprehensiveMockRepository) List(ctx context.Context, query, locale string, page, limit int64) ([]grpcdomain.User, int64, error) {
+	args := m.Called(ctx, query, locale, page, limit)
 	return args.Get(0).([]grpcdomain.User), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *ComprehensiveMockRepository) Erase(ctx context.Context, id int64, reason string) (*grpcdomain.Erasure, error) {
+	args := m.Called(ctx, id, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*grpcdomain.Erasure), args.Error(1)
+}
+
+func (m *ComprehensiveMockRepository) ListEmails(ctx context.Context, userID int64) ([]grpcdomain.Email, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]grpcdomain.Email), args.Error(1)
+}
+
+func (m *ComprehensiveMockRepository) AddEmail(ctx context.Context, userID int64, address string) error {
+	args := m.Called(ctx, userID, address)
+	return args.Error(0)
+}
+
+func (m *ComprehensiveMockRepository) RemoveEmail(ctx context.Context, userID int64, address string) error {
+	args := m.Called(ctx, userID, address)
+	return args.Error(0)
+}
+
+func (m *ComprehensiveMockRepository) SetPrimaryEmail(ctx context.Context, userID int64, address string) error {
+	args := m.Called(ctx, userID, address)
+	return args.Error(0)
+}
+
+func (m *ComprehensiveMockRepository) SetEmailVerification(ctx context.Context, userID int64, address, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, userID, address, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *ComprehensiveMockRepository) VerifyEmail(ctx context.Context, userID int64, address, tokenHash string) error {
+	args := m.Called(ctx, userID, address, tokenHash)
+	return args.Error(0)
+}
+
+func (m *ComprehensiveMockRepository) LinkIdentity(ctx context.Context, identity *grpcdomain.Identity) error {
+	args := m.Called(ctx, identity)
+	return args.Error(0)
+}
+
+func (m *ComprehensiveMockRepository) UnlinkIdentity(ctx context.Context, userID int64, provider, subject string) error {
+	args := m.Called(ctx, userID, provider, subject)
+	return args.Error(0)
+}
+
+func (m *ComprehensiveMockRepository) GetByIdentity(ctx context.Context, provider, subject string) (*grpcdomain.User, error) {
+	args := m.Called(ctx, provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*grpcdomain.User), args.Error(1)
+}
+
+func (m *ComprehensiveMockRepository) ListIdentities(ctx context.Context, userID int64) ([]grpcdomain.Identity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]grpcdomain.Identity), args.Error(1)
+}
+
 // setupComprehensiveTestUsecase creates a new usecase instance with a mock repository for testing.
 // It returns both the usecase and the mock repository for test setup and verification.
 func setupComprehensiveTestUsecase(t *testing.T) (grpcuser.Usecase, *ComprehensiveMockRepository) {
@@ -112,20 +189,26 @@ func TestCreateUser_ValidationError_NameRequired(t *testing.T) {
 	assert.Contains(t, err.Error(), "Name is required")
 }
 
-func TestCreateUser_ValidationError_NameTooShort(t *testing.T) {
-	uc, _ := setupComprehensiveTestUsecase(t)
+func TestCreateUser_ShortNameAccepted(t *testing.T) {
+	uc, mockRepo := setupComprehensiveTestUsecase(t)
 	ctx := context.Background()
 
 	req := grpcuser.CreateUserRequest{
-		Name:  "Jo", // Too short (<3)
-		Email: "john@example.com",
+		Name:  "Li", // Short real-world names are valid
+		Email: "li@example.com",
 	}
 
+	mockRepo.On("GetByEmail", ctx, req.Email).Return(nil, nil)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(u *grpcdomain.User) bool {
+		return u.Name == "Li" && u.DisplayName == "Li"
+	})).Return(int64(1), nil)
+
 	resp, err := uc.CreateUser(ctx, req)
 
-	assert.Error(t, err)
-	assert.Nil(t, resp)
-	assert.Contains(t, err.Error(), "Name must be at least 3 characters")
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	mockRepo.AssertExpectations(t)
 }
 
 func TestCreateUser_ValidationError_EmailRequired(t *testing.T) {
@@ -165,15 +248,15 @@ func TestCreateUser_ValidationError_MultipleErrors(t *testing.T) {
 	ctx := context.Background()
 
 	req := grpcuser.CreateUserRequest{
-		Name:  "Jo",      // Too short
-		Email: "invalid", // Invalid email
+		Name:  strings.Repeat("a", 101), // Too long
+		Email: "invalid",                // Invalid email
 	}
 
 	resp, err := uc.CreateUser(ctx, req)
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
-	assert.Contains(t, err.Error(), "Name must be at least 3 characters")
+	assert.Contains(t, err.Error(), "Name must be at most 100 characters")
 	assert.Contains(t, err.Error(), "Email must be a valid email")
 }
 
@@ -252,13 +335,13 @@ func TestUpdateUser_PartialUpdate_NameOnly(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestUpdateUser_ValidationError_NameTooShort(t *testing.T) {
+func TestUpdateUser_ValidationError_NameTooLong(t *testing.T) {
 	uc, _ := setupComprehensiveTestUsecase(t)
 	ctx := context.Background()
 
 	req := grpcuser.UpdateUserRequest{
 		ID:    1,
-		Name:  "Jo", // Too short
+		Name:  strings.Repeat("a", 101), // Too long
 		Email: "john@example.com",
 	}
 
@@ -266,7 +349,7 @@ func TestUpdateUser_ValidationError_NameTooShort(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
-	assert.Contains(t, err.Error(), "Name must be at least 3 characters")
+	assert.Contains(t, err.Error(), "Name must be at most 100 characters")
 }
 
 func TestUpdateUser_ValidationError_EmailInvalid(t *testing.T) {
@@ -397,7 +480,7 @@ func TestListUsers_Success(t *testing.T) {
 	}
 
 	// Mock List returns users and total count
-	mockRepo.On("List", ctx, req.Query, req.Page, req.Limit).Return(expectedUsers, int64(30), nil)
+	mockRepo.On("List", ctx, req.Query, req.Locale, req.Page, req.Limit).Return(expectedUsers, int64(30), nil)
 
 	resp, err := uc.ListUsers(ctx, req)
 